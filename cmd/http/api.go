@@ -8,6 +8,9 @@ import (
 
 	"github.com/SyamSolution/transaction-service/config"
 	"github.com/SyamSolution/transaction-service/config/middleware"
+	"github.com/SyamSolution/transaction-service/helper"
+	"github.com/SyamSolution/transaction-service/internal/discount"
+	"github.com/SyamSolution/transaction-service/internal/gateway"
 	"github.com/SyamSolution/transaction-service/internal/handler"
 	"github.com/SyamSolution/transaction-service/internal/repository"
 	"github.com/SyamSolution/transaction-service/internal/usecase"
@@ -18,6 +21,7 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/joho/godotenv"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
 )
 
 func main() {
@@ -37,14 +41,53 @@ func main() {
 
 	//=== repository lists start ===//
 	transactionRepo := repository.NewTransactionRepository(db, baseDep.Logger)
+	voucherRepo := repository.NewVoucherRepository(db, baseDep.Logger)
+	waitlistRepo := repository.NewWaitlistRepository(db, baseDep.Logger)
+	paymentShareRepo := repository.NewPaymentShareRepository(db, baseDep.Logger)
+	ticketOwnershipRepo := repository.NewTicketOwnershipRepository(db, baseDep.Logger)
+	issuedTicketRepo := repository.NewIssuedTicketRepository(db, baseDep.Logger)
+	reportRepo := repository.NewReportRepository(db, baseDep.Logger)
+	invoiceRepo := repository.NewInvoiceRepository(db, baseDep.Logger)
+	adminRepo := repository.NewAdminRepository(db, baseDep.Logger)
 	//=== repository lists end ===//
 
+	//=== gateway lists start ===//
+	paymentGateway := gateway.NewMidtransGateway()
+
+	discountPolicy, err := discount.NewDiscountPolicy()
+	if err != nil {
+		baseDep.Logger.Error("Error when loading discount policy", zap.Error(err))
+		os.Exit(1)
+	}
+	//=== gateway lists end ===//
+
 	//=== usecase lists start ===//
-	transactionUsecase := usecase.NewTransactionUsecase(transactionRepo, baseDep.Logger)
+	transactionUsecase := usecase.NewTransactionUsecase(transactionRepo, voucherRepo, waitlistRepo, paymentShareRepo, paymentGateway, helper.NewOrderIDGenerator(), discountPolicy, baseDep.Logger)
+	voucherUsecase := usecase.NewVoucherUsecase(voucherRepo, baseDep.Logger)
+	waitlistUsecase := usecase.NewWaitlistUsecase(waitlistRepo, baseDep.Logger)
+	transferUsecase := usecase.NewTransferUsecase(ticketOwnershipRepo, baseDep.Logger)
+	issuedTicketUsecase := usecase.NewIssuedTicketUsecase(transactionRepo, issuedTicketRepo, baseDep.Logger)
+	gateUsecase := usecase.NewGateUsecase(issuedTicketRepo, baseDep.Logger)
+	reportUsecase := usecase.NewReportUsecase(reportRepo, baseDep.Logger)
+	invoiceUsecase := usecase.NewInvoiceUsecase(transactionRepo, invoiceRepo, baseDep.Logger)
+	paymentNotificationUsecase := usecase.NewPaymentNotificationUsecase(transactionUsecase, issuedTicketUsecase, invoiceUsecase, paymentGateway, baseDep.Logger)
+	adminUsecase := usecase.NewAdminUsecase(adminRepo, transactionRepo, paymentShareRepo, issuedTicketRepo, paymentNotificationUsecase, paymentGateway, baseDep.Logger)
 	//=== usecase lists end ===//
 
+	//=== background jobs ===//
+	go expireSplitTransactions(transactionUsecase, baseDep.Logger)
+	go expireWaitlistHolds(waitlistUsecase, baseDep.Logger)
+
 	//=== handler lists start ===//
-	transactionHandler := handler.NewTransactionHandler(transactionUsecase, baseDep.Logger, cacher)
+	transactionHandler := handler.NewTransactionHandler(transactionUsecase, issuedTicketUsecase, invoiceUsecase, paymentNotificationUsecase, baseDep.Logger, cacher)
+	voucherHandler := handler.NewVoucherHandler(voucherUsecase, baseDep.Logger)
+	waitlistHandler := handler.NewWaitlistHandler(waitlistUsecase, baseDep.Logger)
+	transferHandler := handler.NewTransferHandler(transferUsecase, baseDep.Logger)
+	ticketHandler := handler.NewTicketHandler(issuedTicketUsecase, baseDep.Logger)
+	gateHandler := handler.NewGateHandler(gateUsecase, baseDep.Logger)
+	reportHandler := handler.NewReportHandler(reportUsecase, baseDep.Logger, cacher)
+	invoiceHandler := handler.NewInvoiceHandler(invoiceUsecase, baseDep.Logger)
+	adminHandler := handler.NewAdminHandler(adminUsecase, baseDep.Logger)
 	//=== handler lists end ===//
 
 	app := fiber.New()
@@ -69,12 +112,53 @@ func main() {
 
 	//=== transaction routes ===//
 	app.Post("/midtrans-notification", transactionHandler.MidtransNotification)
+	app.Get("/payment-methods", transactionHandler.GetPaymentMethods)
 	app.Group("/", middleware.Auth())
 	app.Post("/transactions", transactionHandler.CreateTransaction)
+	app.Post("/transactions/quote", transactionHandler.QuoteTransaction)
+	app.Get("/transactions/export", transactionHandler.ExportTransactions)
 	app.Get("/transactions/:transaction_id", transactionHandler.GetTransactionByTransactionID)
+	app.Get("/transactions/:transaction_id/tickets", ticketHandler.GetIssuedTickets)
+	app.Get("/transactions/:transaction_id/invoice", invoiceHandler.GetInvoice)
+	app.Get("/transactions/:transaction_id/history", transactionHandler.GetTransactionHistory)
+	app.Post("/transactions/:transaction_id/resend-payment-link", transactionHandler.ResendPaymentLink)
+	app.Post("/transactions/:transaction_id/resend-tickets", transactionHandler.ResendTickets)
 	app.Get("/transactions-list", transactionHandler.GetListTransaction)
+	app.Get("/orders/:order_id", transactionHandler.GetOrder)
 	app.Post("/midtrans/transaction-cancel/:order_id", transactionHandler.MidtransTransactionCancel)
 
+	//=== waitlist routes ===//
+	app.Post("/waitlist", waitlistHandler.JoinWaitlist)
+	app.Get("/waitlist", waitlistHandler.GetListWaitlist)
+	app.Delete("/waitlist/:waitlist_id", waitlistHandler.LeaveWaitlist)
+
+	//=== transfer routes ===//
+	app.Post("/transfers", transferHandler.CreateTransfer)
+	app.Post("/transfers/accept", transferHandler.AcceptTransfer)
+	app.Get("/transfers", transferHandler.GetListTransfer)
+	app.Delete("/transfers/:transfer_id", transferHandler.CancelTransfer)
+	app.Get("/tickets", ticketHandler.GetOwnedTickets)
+
+	//=== gate routes ===//
+	gate := app.Group("/gate", middleware.Scanner())
+	gate.Get("/tickets/:code", gateHandler.LookupTicket)
+	gate.Post("/check-in", gateHandler.CheckIn)
+	gate.Post("/check-in/sync", gateHandler.SyncCheckIns)
+	gate.Get("/attendance", gateHandler.GetAttendance)
+
+	//=== admin routes ===//
+	admin := app.Group("/admin", middleware.Admin())
+	admin.Post("/vouchers", voucherHandler.CreateVoucher)
+	admin.Get("/vouchers", voucherHandler.GetListVoucher)
+	admin.Get("/vouchers/:code", voucherHandler.GetVoucherByCode)
+	admin.Put("/vouchers/:code", voucherHandler.UpdateVoucher)
+	admin.Delete("/vouchers/:code", voucherHandler.DeactivateVoucher)
+	admin.Get("/reports/sales", reportHandler.GetSalesReport)
+	admin.Get("/transactions", adminHandler.SearchTransactions)
+	admin.Get("/transactions/:transaction_id", adminHandler.GetTransactionDetail)
+	admin.Post("/transactions/:transaction_id/resync", adminHandler.ResyncTransaction)
+	admin.Post("/transactions/:transaction_id/resend", adminHandler.ResendMessage)
+
 	//=== listen port ===//
 	if err := app.Listen(fmt.Sprintf(":%s", os.Getenv("APP_PORT"))); err != nil {
 		log.Fatal(err)
@@ -90,3 +174,35 @@ func loadEnv(logger config.Logger) {
 		}
 	}
 }
+
+// midtrans tidak kirim notifikasi untuk share yang tidak pernah dibuka, jadi deadline split dicek berkala
+func expireSplitTransactions(transactionUsecase usecase.TransactionExecutor, logger config.Logger) {
+	interval, err := time.ParseDuration(os.Getenv("SPLIT_SWEEP_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = 5 * time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := transactionUsecase.ExpireSplitTransactions(); err != nil {
+			logger.Error("Error when expiring split transactions", zap.Error(err))
+		}
+	}
+}
+
+// hold waitlist yang tidak dibeli sampai habis waktunya diteruskan ke antrian berikutnya
+func expireWaitlistHolds(waitlistUsecase usecase.WaitlistExecutor, logger config.Logger) {
+	interval, err := time.ParseDuration(os.Getenv("WAITLIST_SWEEP_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := waitlistUsecase.ExpireWaitlistHolds(); err != nil {
+			logger.Error("Error when expiring waitlist holds", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/helper"
+	"github.com/SyamSolution/transaction-service/internal/discount"
+	"github.com/SyamSolution/transaction-service/internal/gateway"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/repository"
+	"github.com/SyamSolution/transaction-service/internal/usecase"
+	"github.com/joho/godotenv"
+	"go.uber.org/zap"
+)
+
+const dateLayout = "2006-01-02"
+
+// reconcile dijalankan harian oleh scheduler, default-nya mengecek order kemarin
+func main() {
+	yesterday := time.Now().AddDate(0, 0, -1).Format(dateLayout)
+	fromFlag := flag.String("from", yesterday, "first day to reconcile (YYYY-MM-DD)")
+	toFlag := flag.String("to", "", "last day to reconcile (YYYY-MM-DD), defaults to -from")
+	format := flag.String("format", "csv", "report format, csv or json")
+	out := flag.String("out", "", "report file, defaults to stdout")
+	dryRun := flag.Bool("dry-run", false, "only report, do not fix anything")
+	flag.Parse()
+
+	baseDep := config.NewBaseDep()
+	loadEnv(baseDep.Logger)
+
+	from, err := time.ParseInLocation(dateLayout, *fromFlag, time.Local)
+	if err != nil {
+		baseDep.Logger.Error("Error when parsing -from", zap.Error(err))
+		os.Exit(1)
+	}
+	to := from
+	if *toFlag != "" {
+		if to, err = time.ParseInLocation(dateLayout, *toFlag, time.Local); err != nil {
+			baseDep.Logger.Error("Error when parsing -to", zap.Error(err))
+			os.Exit(1)
+		}
+	}
+	if *format != "csv" && *format != "json" {
+		baseDep.Logger.Error("Unknown report format", zap.String("format", *format))
+		os.Exit(1)
+	}
+
+	db, err := config.NewDbPool(baseDep.Logger)
+	if err != nil {
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	transactionRepo := repository.NewTransactionRepository(db, baseDep.Logger)
+	voucherRepo := repository.NewVoucherRepository(db, baseDep.Logger)
+	waitlistRepo := repository.NewWaitlistRepository(db, baseDep.Logger)
+	paymentShareRepo := repository.NewPaymentShareRepository(db, baseDep.Logger)
+	issuedTicketRepo := repository.NewIssuedTicketRepository(db, baseDep.Logger)
+	invoiceRepo := repository.NewInvoiceRepository(db, baseDep.Logger)
+
+	paymentGateway := gateway.NewMidtransGateway()
+	discountPolicy, err := discount.NewDiscountPolicy()
+	if err != nil {
+		baseDep.Logger.Error("Error when loading discount policy", zap.Error(err))
+		os.Exit(1)
+	}
+
+	// perbaikan status dijalankan lewat usecase yang sama dengan webhook
+	transactionUsecase := usecase.NewTransactionUsecase(transactionRepo, voucherRepo, waitlistRepo, paymentShareRepo, paymentGateway,
+		helper.NewOrderIDGenerator(), discountPolicy, baseDep.Logger)
+	issuedTicketUsecase := usecase.NewIssuedTicketUsecase(transactionRepo, issuedTicketRepo, baseDep.Logger)
+	invoiceUsecase := usecase.NewInvoiceUsecase(transactionRepo, invoiceRepo, baseDep.Logger)
+	paymentNotificationUsecase := usecase.NewPaymentNotificationUsecase(transactionUsecase, issuedTicketUsecase, invoiceUsecase, paymentGateway, baseDep.Logger)
+	reconcileUsecase := usecase.NewReconcileUsecase(transactionRepo, paymentShareRepo, paymentNotificationUsecase, paymentGateway, baseDep.Logger)
+
+	report, err := reconcileUsecase.Reconcile(from, to.AddDate(0, 0, 1), *dryRun)
+	if err != nil {
+		baseDep.Logger.Error("Error when reconciling transactions", zap.Error(err))
+		os.Exit(1)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			baseDep.Logger.Error("Error when creating report file", zap.Error(err))
+			os.Exit(1)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if *format == "json" {
+		err = writeJSON(w, report)
+	} else {
+		err = writeCSV(w, report)
+	}
+	if err != nil {
+		baseDep.Logger.Error("Error when writing report", zap.Error(err))
+		os.Exit(1)
+	}
+
+	baseDep.Logger.Info("Reconciliation finished", zap.Int("checked", report.Checked), zap.Int("matched", report.Matched),
+		zap.Int("skipped", report.Skipped), zap.Int("fixed", report.Fixed), zap.Int("discrepancies", len(report.Discrepancies)))
+}
+
+func loadEnv(logger config.Logger) {
+	_, err := os.Stat(".env")
+	if err == nil {
+		err = godotenv.Load()
+		if err != nil {
+			logger.Error("no .env files provided")
+		}
+	}
+}
+
+func writeJSON(w io.Writer, report model.ReconcileReport) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+func writeCSV(w io.Writer, report model.ReconcileReport) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"transaction_id", "order_id", "transaction_date", "local_status", "gateway_status",
+		"fraud_status", "local_amount", "gateway_amount", "action", "reason"}); err != nil {
+		return err
+	}
+
+	for _, d := range report.Discrepancies {
+		err := writer.Write([]string{strconv.Itoa(d.TransactionID), d.OrderID, d.TransactionDate.Format(time.RFC3339),
+			d.LocalStatus, d.GatewayStatus, d.FraudStatus, strconv.FormatInt(d.LocalAmount, 10),
+			strconv.FormatInt(d.GatewayAmount, 10), d.Action, d.Reason})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
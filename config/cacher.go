@@ -20,6 +20,7 @@ type Cacher interface {
 	SetNX(ctx context.Context, key string, value interface{}, duration time.Duration) error
 	Get(ctx context.Context, key string) (string, error)
 	Del(ctx context.Context, key string) error
+	Incr(ctx context.Context, key string, duration time.Duration) (int64, error)
 }
 
 func NewCacher(logger Logger) Cacher {
@@ -83,3 +84,19 @@ func (c *Cache) Del(ctx context.Context, key string) error {
 
 	return nil
 }
+
+// Incr counts up key and returns the new count, the key expires duration after the first count
+func (c *Cache) Incr(ctx context.Context, key string, duration time.Duration) (int64, error) {
+	fullKey := fmt.Sprintf("%s:%s", c.service, key)
+
+	// key dibuat dengan TTL lewat SET NX EX lalu INCR dalam satu MULTI, INCR tidak menghapus TTL
+	// jadi counter tidak pernah tertinggal tanpa expiry
+	pipe := c.db.TxPipeline()
+	pipe.SetNX(ctx, fullKey, 0, duration)
+	incr := pipe.Incr(ctx, fullKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	return incr.Val(), nil
+}
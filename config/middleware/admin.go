@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"os"
+
+	"github.com/SyamSolution/transaction-service/helper"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Admin only lets through tokens whose cognito:groups contains ADMIN_GROUP (default "admin")
+func Admin() fiber.Handler {
+	return requireGroup(adminGroup())
+}
+
+// Scanner lets through gate scanners, tokens in SCANNER_GROUP (default "scanner"), and admins
+func Scanner() fiber.Handler {
+	scannerGroup := os.Getenv("SCANNER_GROUP")
+	if scannerGroup == "" {
+		scannerGroup = "scanner"
+	}
+
+	return requireGroup(scannerGroup, adminGroup())
+}
+
+func adminGroup() string {
+	adminGroup := os.Getenv("ADMIN_GROUP")
+	if adminGroup == "" {
+		adminGroup = "admin"
+	}
+
+	return adminGroup
+}
+
+func requireGroup(allowed ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		groups, err := helper.VerifyToken(c.Get("Authorization"), "cognito:groups")
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"message": "Unauthorized",
+			})
+		}
+
+		list, _ := groups.([]interface{})
+		for _, group := range list {
+			for _, name := range allowed {
+				if group == name {
+					return c.Next()
+				}
+			}
+		}
+
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "Forbidden",
+		})
+	}
+}
@@ -0,0 +1,3 @@
+ALTER TABLE transaction
+    DROP COLUMN va_number,
+    DROP COLUMN qr_string;
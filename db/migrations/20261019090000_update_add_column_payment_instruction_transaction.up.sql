@@ -0,0 +1,3 @@
+ALTER TABLE transaction
+    ADD COLUMN va_number VARCHAR(50),
+    ADD COLUMN qr_string TEXT;
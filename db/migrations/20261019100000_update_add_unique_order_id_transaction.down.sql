@@ -0,0 +1,2 @@
+ALTER TABLE transaction
+    DROP INDEX uq_transaction_order_id;
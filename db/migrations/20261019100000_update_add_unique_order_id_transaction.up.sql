@@ -0,0 +1,2 @@
+ALTER TABLE transaction
+    ADD CONSTRAINT uq_transaction_order_id UNIQUE (order_id);
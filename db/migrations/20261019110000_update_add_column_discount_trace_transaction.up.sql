@@ -0,0 +1,2 @@
+ALTER TABLE transaction
+    ADD COLUMN discount_trace TEXT NULL AFTER discount;
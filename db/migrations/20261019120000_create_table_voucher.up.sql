@@ -0,0 +1,19 @@
+CREATE TABLE voucher (
+    voucher_id INT AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(50) NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    discount_type ENUM('percentage', 'fixed') NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    min_spend DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    valid_from TIMESTAMP NULL,
+    valid_until TIMESTAMP NULL,
+    max_redemption INT NOT NULL DEFAULT 0,
+    max_per_user INT NOT NULL DEFAULT 0,
+    redeemed_count INT NOT NULL DEFAULT 0,
+    continent VARCHAR(50) NOT NULL DEFAULT '',
+    ticket_type VARCHAR(50) NOT NULL DEFAULT '',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_voucher_code UNIQUE (code)
+);
@@ -0,0 +1,12 @@
+CREATE TABLE voucher_redemption (
+    voucher_redemption_id INT AUTO_INCREMENT PRIMARY KEY,
+    voucher_id INT NOT NULL,
+    order_id VARCHAR(50) NOT NULL,
+    email VARCHAR(100) NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    status ENUM('reserved', 'redeemed', 'released') DEFAULT 'reserved',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_voucher_redemption_order_id UNIQUE (order_id),
+    INDEX idx_voucher_redemption_voucher_email (voucher_id, email)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE transaction
+    DROP COLUMN voucher_code,
+    DROP COLUMN voucher_discount;
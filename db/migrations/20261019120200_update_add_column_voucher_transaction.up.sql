@@ -0,0 +1,3 @@
+ALTER TABLE transaction
+    ADD COLUMN voucher_code VARCHAR(50) NOT NULL DEFAULT '' AFTER discount_trace,
+    ADD COLUMN voucher_discount DECIMAL(10, 2) NOT NULL DEFAULT 0 AFTER voucher_code;
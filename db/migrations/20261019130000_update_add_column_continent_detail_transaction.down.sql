@@ -0,0 +1,2 @@
+ALTER TABLE detail_transaction
+    DROP COLUMN continent;
@@ -0,0 +1,6 @@
+ALTER TABLE detail_transaction
+    ADD COLUMN continent VARCHAR(50) NOT NULL DEFAULT '' AFTER ticket_type;
+
+UPDATE detail_transaction dt
+    JOIN transaction t ON t.transaction_id = dt.transaction_id
+SET dt.continent = COALESCE(t.continent, '');
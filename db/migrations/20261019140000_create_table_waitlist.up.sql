@@ -0,0 +1,14 @@
+CREATE TABLE waitlist (
+    waitlist_id INT AUTO_INCREMENT PRIMARY KEY,
+    ticket_id INT NOT NULL,
+    continent VARCHAR(50) NOT NULL DEFAULT '',
+    email VARCHAR(100) NOT NULL,
+    quantity INT NOT NULL,
+    status ENUM('waiting', 'notified', 'purchased', 'expired', 'left') DEFAULT 'waiting',
+    hold_until DATETIME NULL,
+    order_id VARCHAR(50) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_waitlist_ticket_status (ticket_id, status, created_at),
+    INDEX idx_waitlist_email (email)
+);
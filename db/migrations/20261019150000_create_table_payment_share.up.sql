@@ -0,0 +1,15 @@
+CREATE TABLE payment_share (
+    payment_share_id INT AUTO_INCREMENT PRIMARY KEY,
+    order_id VARCHAR(50) NOT NULL,
+    share_order_id VARCHAR(50) NOT NULL,
+    share_number INT NOT NULL,
+    amount BIGINT NOT NULL,
+    status ENUM('pending', 'paid', 'refunded', 'cancelled') DEFAULT 'pending',
+    token VARCHAR(255) NOT NULL DEFAULT '',
+    redirect_url VARCHAR(255) NOT NULL DEFAULT '',
+    paid_at DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_payment_share_share_order_id UNIQUE (share_order_id),
+    INDEX idx_payment_share_order_id (order_id)
+);
@@ -0,0 +1,4 @@
+ALTER TABLE transaction
+    DROP INDEX idx_transaction_split_deadline,
+    DROP COLUMN split_count,
+    DROP COLUMN split_deadline;
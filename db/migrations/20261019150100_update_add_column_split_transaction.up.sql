@@ -0,0 +1,4 @@
+ALTER TABLE transaction
+    ADD COLUMN split_count INT NOT NULL DEFAULT 0 AFTER voucher_discount,
+    ADD COLUMN split_deadline DATETIME NULL AFTER split_count,
+    ADD INDEX idx_transaction_split_deadline (payment_status, split_deadline);
@@ -0,0 +1,10 @@
+CREATE TABLE ticket_holder (
+    ticket_holder_id INT AUTO_INCREMENT PRIMARY KEY,
+    detail_transaction_id INT NOT NULL,
+    full_name VARCHAR(100) NOT NULL,
+    email VARCHAR(100) NOT NULL,
+    id_number VARCHAR(50) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_ticket_holder_detail_transaction_id UNIQUE (detail_transaction_id)
+);
@@ -0,0 +1,17 @@
+CREATE TABLE ticket_ownership (
+    ticket_ownership_id INT AUTO_INCREMENT PRIMARY KEY,
+    detail_transaction_id INT NOT NULL,
+    order_id VARCHAR(50) NOT NULL,
+    ticket_id INT NOT NULL,
+    email VARCHAR(100) NOT NULL,
+    quantity INT NOT NULL,
+    status ENUM('owned', 'pending', 'cancelled') DEFAULT 'owned',
+    transferred_from VARCHAR(100) NOT NULL DEFAULT '',
+    event_date DATETIME NULL,
+    expires_at DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_ticket_ownership_detail (detail_transaction_id, status),
+    INDEX idx_ticket_ownership_email (email),
+    INDEX idx_ticket_ownership_transferred_from (transferred_from)
+);
@@ -0,0 +1,17 @@
+CREATE TABLE issued_ticket (
+    issued_ticket_id INT AUTO_INCREMENT PRIMARY KEY,
+    detail_transaction_id INT NOT NULL,
+    order_id VARCHAR(50) NOT NULL,
+    ticket_id INT NOT NULL,
+    unit_number INT NOT NULL,
+    code VARCHAR(32) NOT NULL,
+    ticket_type VARCHAR(50) NOT NULL DEFAULT '',
+    holder_name VARCHAR(100) NOT NULL DEFAULT '',
+    status ENUM('issued', 'used') DEFAULT 'issued',
+    used_at DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_issued_ticket_code UNIQUE (code),
+    CONSTRAINT uq_issued_ticket_unit UNIQUE (detail_transaction_id, unit_number),
+    INDEX idx_issued_ticket_order_id (order_id)
+);
@@ -0,0 +1,4 @@
+ALTER TABLE issued_ticket
+    DROP INDEX idx_issued_ticket_ticket_status,
+    DROP COLUMN checked_in_by,
+    DROP COLUMN gate;
@@ -0,0 +1,4 @@
+ALTER TABLE issued_ticket
+    ADD COLUMN gate VARCHAR(50) NOT NULL DEFAULT '' AFTER used_at,
+    ADD COLUMN checked_in_by VARCHAR(100) NOT NULL DEFAULT '' AFTER gate,
+    ADD INDEX idx_issued_ticket_ticket_status (ticket_id, status);
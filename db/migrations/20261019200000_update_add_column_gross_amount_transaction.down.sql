@@ -0,0 +1,3 @@
+ALTER TABLE transaction
+    DROP INDEX idx_transaction_date,
+    DROP COLUMN gross_amount;
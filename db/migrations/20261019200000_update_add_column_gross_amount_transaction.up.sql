@@ -0,0 +1,3 @@
+ALTER TABLE transaction
+    ADD COLUMN gross_amount BIGINT NOT NULL DEFAULT 0 AFTER total_amount,
+    ADD INDEX idx_transaction_date (transaction_date, transaction_id);
@@ -0,0 +1,6 @@
+ALTER TABLE transaction
+    DROP INDEX idx_transaction_status_date;
+
+ALTER TABLE detail_transaction
+    DROP COLUMN net_amount,
+    DROP COLUMN price;
@@ -0,0 +1,13 @@
+ALTER TABLE detail_transaction
+    ADD COLUMN price DECIMAL(10, 2) NOT NULL DEFAULT 0 AFTER quantity,
+    ADD COLUMN net_amount DECIMAL(10, 2) NOT NULL DEFAULT 0 AFTER price;
+
+-- order dengan satu line bisa diisi pasti, order lama dengan beberapa line diisi di migrasi berikutnya
+UPDATE detail_transaction dt
+    JOIN transaction t ON t.transaction_id = dt.transaction_id
+    JOIN (SELECT transaction_id FROM detail_transaction GROUP BY transaction_id HAVING COUNT(*) = 1) single
+        ON single.transaction_id = dt.transaction_id
+SET dt.net_amount = COALESCE(t.total_amount, 0);
+
+ALTER TABLE transaction
+    ADD INDEX idx_transaction_status_date (payment_status, transaction_date);
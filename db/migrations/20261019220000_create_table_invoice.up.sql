@@ -0,0 +1,21 @@
+CREATE TABLE invoice_sequence (
+    invoice_year INT PRIMARY KEY,
+    last_number INT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE invoice (
+    invoice_id INT AUTO_INCREMENT PRIMARY KEY,
+    invoice_number VARCHAR(50) NOT NULL,
+    invoice_year INT NOT NULL,
+    sequence_number INT NOT NULL,
+    transaction_id INT NOT NULL,
+    order_id VARCHAR(50) NOT NULL,
+    issued_at DATETIME NOT NULL,
+    data JSON NULL,
+    pdf MEDIUMBLOB NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_invoice_number UNIQUE (invoice_number),
+    CONSTRAINT uq_invoice_sequence UNIQUE (invoice_year, sequence_number),
+    CONSTRAINT uq_invoice_transaction_id UNIQUE (transaction_id)
+);
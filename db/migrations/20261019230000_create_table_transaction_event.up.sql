@@ -0,0 +1,13 @@
+CREATE TABLE transaction_event (
+    transaction_event_id INT AUTO_INCREMENT PRIMARY KEY,
+    transaction_id INT NOT NULL,
+    order_id VARCHAR(50) NOT NULL,
+    actor VARCHAR(20) NOT NULL,
+    actor_id VARCHAR(255) NOT NULL DEFAULT '',
+    previous_status VARCHAR(20) NOT NULL DEFAULT '',
+    new_status VARCHAR(20) NOT NULL,
+    payload_reference VARCHAR(255) NOT NULL DEFAULT '',
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    INDEX idx_transaction_event_transaction (transaction_id, transaction_event_id)
+);
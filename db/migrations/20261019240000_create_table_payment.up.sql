@@ -0,0 +1,20 @@
+CREATE TABLE payment (
+    payment_id INT AUTO_INCREMENT PRIMARY KEY,
+    transaction_id INT NOT NULL,
+    order_id VARCHAR(50) NOT NULL,
+    token VARCHAR(255) NOT NULL DEFAULT '',
+    redirect_url VARCHAR(255) NOT NULL DEFAULT '',
+    va_number VARCHAR(50) NOT NULL DEFAULT '',
+    qr_string TEXT NULL,
+    gateway_transaction_id VARCHAR(100) NOT NULL DEFAULT '',
+    link_number INT NOT NULL DEFAULT 0,
+    expires_at DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_payment_order_id UNIQUE (order_id),
+    INDEX idx_payment_transaction_id (transaction_id)
+);
+
+INSERT INTO payment (transaction_id, order_id, va_number, qr_string, created_at, updated_at)
+SELECT transaction_id, order_id, COALESCE(va_number, ''), qr_string, created_at, updated_at
+FROM transaction WHERE va_number IS NOT NULL OR qr_string IS NOT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE waitlist
+    DROP INDEX uq_waitlist_active_entry,
+    DROP COLUMN active_entry;
@@ -0,0 +1,11 @@
+-- keep only the newest active entry per user and ticket before the constraint goes on
+UPDATE waitlist w
+    JOIN waitlist newer ON newer.email = w.email AND newer.ticket_id = w.ticket_id
+        AND newer.status IN ('waiting', 'notified') AND newer.waitlist_id > w.waitlist_id
+SET w.status = 'left'
+WHERE w.status IN ('waiting', 'notified');
+
+-- NULL for closed entries, so only one waiting or notified entry per (email, ticket_id)
+ALTER TABLE waitlist
+    ADD COLUMN active_entry TINYINT AS (IF(status IN ('waiting', 'notified'), 1, NULL)) STORED,
+    ADD CONSTRAINT uq_waitlist_active_entry UNIQUE (email, ticket_id, active_entry);
@@ -0,0 +1,3 @@
+UPDATE payment_share SET status = 'paid' WHERE status = 'refund_failed';
+ALTER TABLE payment_share
+    MODIFY COLUMN status ENUM('pending', 'paid', 'refunded', 'cancelled') DEFAULT 'pending';
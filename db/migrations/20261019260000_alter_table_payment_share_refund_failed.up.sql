@@ -0,0 +1,2 @@
+ALTER TABLE payment_share
+    MODIFY COLUMN status ENUM('pending', 'paid', 'refunded', 'cancelled', 'refund_failed') DEFAULT 'pending';
@@ -0,0 +1,3 @@
+ALTER TABLE issued_ticket
+    DROP INDEX idx_issued_ticket_owner_email,
+    DROP COLUMN owner_email;
@@ -0,0 +1,8 @@
+ALTER TABLE issued_ticket
+    ADD COLUMN owner_email VARCHAR(100) NOT NULL DEFAULT '' AFTER holder_name,
+    ADD INDEX idx_issued_ticket_owner_email (owner_email);
+
+-- ticket yang sudah terbit masih milik pembeli, transfer sebelumnya tidak memindahkan kode
+UPDATE issued_ticket it
+    JOIN transaction t ON t.order_id = it.order_id
+SET it.owner_email = t.email;
@@ -0,0 +1,3 @@
+UPDATE issued_ticket SET status = 'issued' WHERE status = 'void';
+ALTER TABLE issued_ticket
+    MODIFY COLUMN status ENUM('issued', 'used') DEFAULT 'issued';
@@ -0,0 +1,8 @@
+ALTER TABLE issued_ticket
+    MODIFY COLUMN status ENUM('issued', 'used', 'void') DEFAULT 'issued';
+
+-- ticket order yang sudah dibatalkan sebelum migrasi ini tidak boleh masuk gate
+UPDATE issued_ticket it
+    JOIN transaction t ON t.order_id = it.order_id
+SET it.status = 'void'
+WHERE it.status = 'issued' AND t.payment_status <> 'completed';
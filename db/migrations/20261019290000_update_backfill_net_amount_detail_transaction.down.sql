@@ -0,0 +1,4 @@
+UPDATE detail_transaction dt
+    JOIN (SELECT transaction_id FROM detail_transaction GROUP BY transaction_id HAVING COUNT(*) > 1 AND SUM(price) = 0) legacy
+        ON legacy.transaction_id = dt.transaction_id
+SET dt.net_amount = 0;
@@ -0,0 +1,19 @@
+-- order lama dengan beberapa line belum punya harga per line, total order dibagi menurut jumlah ticket
+UPDATE detail_transaction dt
+    JOIN transaction t ON t.transaction_id = dt.transaction_id
+    JOIN (SELECT transaction_id, SUM(COALESCE(quantity, 0)) AS total_quantity
+          FROM detail_transaction
+          GROUP BY transaction_id
+          HAVING COUNT(*) > 1 AND SUM(price) = 0 AND SUM(net_amount) = 0 AND SUM(COALESCE(quantity, 0)) > 0) legacy
+        ON legacy.transaction_id = dt.transaction_id
+SET dt.net_amount = ROUND(COALESCE(t.total_amount, 0) * COALESCE(dt.quantity, 0) / legacy.total_quantity, 2);
+
+-- sisa pembulatan masuk ke line terakhir supaya jumlahnya sama dengan total order
+UPDATE detail_transaction dt
+    JOIN transaction t ON t.transaction_id = dt.transaction_id
+    JOIN (SELECT transaction_id, SUM(net_amount) AS allocated, MAX(detail_transaction_id) AS last_line
+          FROM detail_transaction
+          GROUP BY transaction_id
+          HAVING COUNT(*) > 1 AND SUM(price) = 0) legacy
+        ON legacy.last_line = dt.detail_transaction_id
+SET dt.net_amount = dt.net_amount + COALESCE(t.total_amount, 0) - legacy.allocated;
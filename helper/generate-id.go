@@ -1,21 +1,147 @@
 package helper
 
 import (
-	"math/rand"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
 	"time"
 )
 
-func init() {
-	rand.Seed(time.Now().UnixNano())
+type OrderIDGenerator interface {
+	NewOrderID() string
 }
 
-func RandomOrderID(n int) string {
-	var letters = []rune("0123456789")
+// NewOrderIDGenerator picks the generator from ORDER_ID_GENERATOR (ulid or snowflake, default ulid).
+// Sandbox and production share one midtrans merchant, so non production ids get their own prefix.
+func NewOrderIDGenerator() OrderIDGenerator {
+	prefix := "ORDER-"
+	if os.Getenv("MIDTRANS_ENVIRONMENT") != "production" {
+		prefix = "ORDER-SBX-"
+	}
+	if p := os.Getenv("ORDER_ID_PREFIX"); p != "" {
+		prefix = p
+	}
+
+	if os.Getenv("ORDER_ID_GENERATOR") == "snowflake" {
+		node, _ := strconv.Atoi(os.Getenv("ORDER_ID_NODE"))
+		return NewSnowflakeOrderIDGenerator(prefix, node)
+	}
+
+	return NewULIDOrderIDGenerator(prefix)
+}
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+type ulidGenerator struct {
+	mu      sync.Mutex
+	prefix  string
+	now     func() time.Time
+	lastMs  uint64
+	lastRnd [10]byte
+}
+
+func NewULIDOrderIDGenerator(prefix string) OrderIDGenerator {
+	return &ulidGenerator{prefix: prefix, now: time.Now}
+}
+
+// NewOrderID returns prefix + a 26 char ULID. Ids created in the same millisecond increment the
+// random part, so they stay unique and sorted within one process.
+func (g *ulidGenerator) NewOrderID() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := uint64(g.now().UnixMilli())
+	if ms <= g.lastMs {
+		ms = g.lastMs
+		for i := len(g.lastRnd) - 1; i >= 0; i-- {
+			g.lastRnd[i]++
+			if g.lastRnd[i] != 0 {
+				break
+			}
+		}
+	} else {
+		if _, err := rand.Read(g.lastRnd[:]); err != nil {
+			panic(fmt.Sprintf("order id: reading random bytes: %v", err))
+		}
+		g.lastMs = ms
+	}
+
+	var id [16]byte
+	for i := 0; i < 6; i++ {
+		id[i] = byte(ms >> (40 - 8*uint(i)))
+	}
+	copy(id[6:], g.lastRnd[:])
+
+	return g.prefix + encodeCrockford(id)
+}
+
+// encodeCrockford encodes 128 bits as 26 base32 chars, most significant first
+func encodeCrockford(id [16]byte) string {
+	out := make([]byte, 26)
+	var acc uint32
+	var bits uint
+	pos := 25
+	for i := len(id) - 1; i >= 0; i-- {
+		acc |= uint32(id[i]) << bits
+		bits += 8
+		for bits >= 5 {
+			out[pos] = crockford[acc&31]
+			acc >>= 5
+			bits -= 5
+			pos--
+		}
+	}
+	if pos >= 0 {
+		out[pos] = crockford[acc&31]
+	}
+
+	return string(out)
+}
+
+const (
+	snowflakeEpoch    = 1704067200000 // 2024-01-01T00:00:00Z
+	snowflakeNodeBits = 10
+	snowflakeSeqBits  = 12
+)
+
+type snowflakeGenerator struct {
+	mu     sync.Mutex
+	prefix string
+	node   int64
+	now    func() time.Time
+	lastMs int64
+	seq    int64
+}
+
+func NewSnowflakeOrderIDGenerator(prefix string, node int) OrderIDGenerator {
+	return &snowflakeGenerator{
+		prefix: prefix,
+		node:   int64(node) & (1<<snowflakeNodeBits - 1),
+		now:    time.Now,
+	}
+}
+
+// NewOrderID returns prefix + a zero padded 41 bit time | 10 bit node | 12 bit sequence number,
+// padded so string order matches creation order
+func (g *snowflakeGenerator) NewOrderID() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
 
-	s := make([]rune, n)
-	for i := range s {
-		s[i] = letters[rand.Intn(len(letters))]
+	ms := g.now().UnixMilli() - snowflakeEpoch
+	if ms <= g.lastMs {
+		ms = g.lastMs
+		g.seq = (g.seq + 1) & (1<<snowflakeSeqBits - 1)
+		if g.seq == 0 {
+			// sequence exhausted for this millisecond, borrow the next one
+			ms++
+		}
+	} else {
+		g.seq = 0
 	}
+	g.lastMs = ms
 
-	return string(s)
+	id := ms<<(snowflakeNodeBits+snowflakeSeqBits) | g.node<<snowflakeSeqBits | g.seq
+	return fmt.Sprintf("%s%019d", g.prefix, id)
 }
@@ -0,0 +1,63 @@
+package helper
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestULIDOrderIDGenerator(t *testing.T) {
+	fixed := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	g := &ulidGenerator{prefix: "ORDER-", now: func() time.Time { return fixed }}
+
+	ids := make([]string, 1000)
+	seen := make(map[string]bool, len(ids))
+	for i := range ids {
+		ids[i] = g.NewOrderID()
+		assert.True(t, strings.HasPrefix(ids[i], "ORDER-"))
+		assert.Len(t, ids[i], len("ORDER-")+26)
+		assert.False(t, seen[ids[i]], "duplicate id %s", ids[i])
+		seen[ids[i]] = true
+	}
+	assert.True(t, sort.StringsAreSorted(ids), "ids in the same millisecond must stay sorted")
+
+	g.now = func() time.Time { return fixed.Add(time.Millisecond) }
+	assert.Greater(t, g.NewOrderID(), ids[len(ids)-1])
+}
+
+func TestSnowflakeOrderIDGenerator(t *testing.T) {
+	fixed := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	g := NewSnowflakeOrderIDGenerator("ORDER-SBX-", 7).(*snowflakeGenerator)
+	g.now = func() time.Time { return fixed }
+
+	ids := make([]string, 5000)
+	seen := make(map[string]bool, len(ids))
+	for i := range ids {
+		ids[i] = g.NewOrderID()
+		assert.True(t, strings.HasPrefix(ids[i], "ORDER-SBX-"))
+		assert.False(t, seen[ids[i]], "duplicate id %s", ids[i])
+		seen[ids[i]] = true
+	}
+	assert.True(t, sort.StringsAreSorted(ids))
+
+	// same clock on another node never collides
+	other := NewSnowflakeOrderIDGenerator("ORDER-SBX-", 8).(*snowflakeGenerator)
+	other.now = g.now
+	assert.False(t, seen[other.NewOrderID()])
+}
+
+func TestNewOrderIDGeneratorPrefix(t *testing.T) {
+	t.Setenv("MIDTRANS_ENVIRONMENT", "production")
+	t.Setenv("ORDER_ID_PREFIX", "")
+	assert.True(t, strings.HasPrefix(NewOrderIDGenerator().NewOrderID(), "ORDER-0"))
+
+	t.Setenv("MIDTRANS_ENVIRONMENT", "sandbox")
+	assert.True(t, strings.HasPrefix(NewOrderIDGenerator().NewOrderID(), "ORDER-SBX-"))
+
+	t.Setenv("ORDER_ID_PREFIX", "TEST-")
+	t.Setenv("ORDER_ID_GENERATOR", "snowflake")
+	assert.True(t, strings.HasPrefix(NewOrderIDGenerator().NewOrderID(), "TEST-"))
+}
@@ -0,0 +1,111 @@
+package helper
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/internal/model"
+)
+
+// ticket-management-service only serves one ticket per request, at most this many run at once
+const ticketEventConcurrency = 5
+
+type ticketEventEntry struct {
+	event     model.TicketEvent
+	expiresAt time.Time
+}
+
+// ticketEventCache keeps events in memory, they rarely change once a ticket is on sale
+type ticketEventCache struct {
+	mu      sync.Mutex
+	entries map[int]ticketEventEntry
+	fetch   func(ticketID int) (model.TicketEvent, error)
+	now     func() time.Time
+}
+
+var ticketEvents = &ticketEventCache{
+	entries: make(map[int]ticketEventEntry),
+	fetch:   GetTicketEventByTicketID,
+	now:     time.Now,
+}
+
+// ticketEventCacheTTL reads TICKET_EVENT_CACHE_TTL (e.g. "10m")
+func ticketEventCacheTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("TICKET_EVENT_CACHE_TTL"))
+	if err != nil || ttl <= 0 {
+		return 10 * time.Minute
+	}
+
+	return ttl
+}
+
+// GetTicketEventsByTicketIDs returns the event of every ticket, each ticket is requested once and only on a cache miss.
+// Tickets that could not be fetched are left out and the last error is returned with the rest.
+func GetTicketEventsByTicketIDs(ticketIDs []int) (map[int]model.TicketEvent, error) {
+	return ticketEvents.get(ticketIDs)
+}
+
+func (c *ticketEventCache) get(ticketIDs []int) (map[int]model.TicketEvent, error) {
+	events := make(map[int]model.TicketEvent, len(ticketIDs))
+	var missing []int
+
+	c.mu.Lock()
+	now := c.now()
+	for _, ticketID := range ticketIDs {
+		if _, ok := events[ticketID]; ok {
+			continue
+		}
+		if entry, ok := c.entries[ticketID]; ok && now.Before(entry.expiresAt) {
+			events[ticketID] = entry.event
+			continue
+		}
+		events[ticketID] = model.TicketEvent{}
+		missing = append(missing, ticketID)
+	}
+	c.mu.Unlock()
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		lastErr error
+	)
+	sem := make(chan struct{}, ticketEventConcurrency)
+	for _, ticketID := range missing {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(ticketID int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			event, err := c.fetch(ticketID)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				delete(events, ticketID)
+				lastErr = err
+				return
+			}
+			events[ticketID] = event
+		}(ticketID)
+	}
+	wg.Wait()
+
+	c.mu.Lock()
+	now = c.now()
+	// buang event yang sudah kadaluarsa supaya map tidak terus membesar
+	for ticketID, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, ticketID)
+		}
+	}
+	expiresAt := now.Add(ticketEventCacheTTL())
+	for _, ticketID := range missing {
+		if event, ok := events[ticketID]; ok {
+			c.entries[ticketID] = ticketEventEntry{event: event, expiresAt: expiresAt}
+		}
+	}
+	c.mu.Unlock()
+
+	return events, lastErr
+}
@@ -0,0 +1,49 @@
+package helper
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTicketEventCache(t *testing.T) {
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	var mu sync.Mutex
+	calls := make(map[int]int)
+	cache := &ticketEventCache{
+		entries: make(map[int]ticketEventEntry),
+		fetch: func(ticketID int) (model.TicketEvent, error) {
+			mu.Lock()
+			calls[ticketID]++
+			mu.Unlock()
+			if ticketID == 3 {
+				return model.TicketEvent{}, errors.New("ticket service down")
+			}
+			return model.TicketEvent{TicketID: ticketID, EventName: "Event"}, nil
+		},
+		now: func() time.Time { return now },
+	}
+
+	events, err := cache.get([]int{1, 2, 1, 3})
+	assert.Error(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, "Event", events[1].EventName)
+	assert.Equal(t, 1, calls[1], "a ticket repeated in one order is fetched once")
+
+	events, err = cache.get([]int{1, 2})
+	assert.Nil(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, 1, calls[1], "cached tickets are not fetched again")
+
+	cache.get([]int{3})
+	assert.Equal(t, 2, calls[3], "failures are not cached")
+
+	now = now.Add(ticketEventCacheTTL())
+	cache.get([]int{1})
+	assert.Equal(t, 2, calls[1], "expired tickets are fetched again")
+	assert.NotContains(t, cache.entries, 2, "expired tickets are evicted")
+}
@@ -0,0 +1,47 @@
+package discount
+
+import (
+	"fmt"
+
+	"github.com/SyamSolution/transaction-service/helper"
+	"github.com/SyamSolution/transaction-service/internal/model"
+)
+
+const gruleRuleName = "grule:continent_sold_out"
+
+type grulePolicy struct {
+	checkDiscount func(discount model.Discount) (float32, error)
+}
+
+// NewGrulePolicy only knows the sold out continent rule, the amount itself comes from the grule service
+func NewGrulePolicy() DiscountPolicy {
+	return &grulePolicy{checkDiscount: helper.CheckDiscount}
+}
+
+func (p *grulePolicy) Evaluate(input model.DiscountInput) (model.DiscountResult, error) {
+	continent, ok := continentSoldOut(input)
+	if !ok {
+		return model.DiscountResult{Trace: []model.DiscountTrace{{
+			Rule:   gruleRuleName,
+			Reason: "no previously bought continent is sold out",
+		}}}, nil
+	}
+
+	percent, err := p.checkDiscount(model.Discount{
+		IsContinentSoldOut: true,
+		IsContinentDiff:    true,
+	})
+	if err != nil {
+		return model.DiscountResult{}, err
+	}
+
+	return model.DiscountResult{
+		Percent: percent,
+		Trace: []model.DiscountTrace{{
+			Rule:    gruleRuleName,
+			Matched: true,
+			Percent: percent,
+			Reason:  fmt.Sprintf("continent %s is sold out, amount from grule service", continent),
+		}},
+	}, nil
+}
@@ -0,0 +1,57 @@
+package discount
+
+import (
+	"os"
+	"strings"
+
+	"github.com/SyamSolution/transaction-service/internal/model"
+)
+
+const defaultRulesFile = "config/discount_rules.yaml"
+
+type DiscountPolicy interface {
+	Evaluate(input model.DiscountInput) (model.DiscountResult, error)
+}
+
+// NewDiscountPolicy picks the policy from DISCOUNT_POLICY, "local" evaluates the rules in
+// DISCOUNT_RULES_FILE inside the service, anything else keeps asking the grule service
+func NewDiscountPolicy() (DiscountPolicy, error) {
+	if os.Getenv("DISCOUNT_POLICY") != "local" {
+		return NewGrulePolicy(), nil
+	}
+
+	path := os.Getenv("DISCOUNT_RULES_FILE")
+	if path == "" {
+		path = defaultRulesFile
+	}
+	rules, err := LoadRuleSet(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLocalPolicy(rules), nil
+}
+
+// continentSoldOut returns a continent the user bought before that is now sold out and is not
+// in the order, the original reason for the discount
+func continentSoldOut(input model.DiscountInput) (string, bool) {
+	for _, cs := range input.SoldOutContinents {
+		for _, continent := range input.PreviousContinents {
+			if continent == cs && !containsFold(input.Continents, continent) {
+				return continent, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,250 @@
+package discount
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	CombineFirst = "first"
+	CombineSum   = "sum"
+)
+
+// RuleSet is the declarative discount configuration. Rules run by priority (lowest first, ties
+// by name) so the same input always gives the same result and trace.
+type RuleSet struct {
+	// Combine is "first" (default), only the first matching rule applies, or "sum"
+	Combine    string  `yaml:"combine"`
+	MaxPercent float32 `yaml:"max_percent"`
+	Rules      []Rule  `yaml:"rules"`
+}
+
+type Rule struct {
+	Name     string    `yaml:"name"`
+	Priority int       `yaml:"priority"`
+	Percent  float32   `yaml:"percent"`
+	When     Condition `yaml:"when"`
+}
+
+// Condition fields are ANDed, an empty field is not checked
+type Condition struct {
+	ContinentSoldOut bool `yaml:"continent_sold_out"`
+	FirstPurchase    bool `yaml:"first_purchase"`
+	MinQuantity      int  `yaml:"min_quantity"`
+	// Continents matches when any line of the order is in one of them
+	Continents []string `yaml:"continents"`
+	// From and Until are RFC3339, Days are weekday names in server time
+	From  string   `yaml:"from"`
+	Until string   `yaml:"until"`
+	Days  []string `yaml:"days"`
+
+	from  time.Time
+	until time.Time
+}
+
+func LoadRuleSet(path string) (RuleSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return RuleSet{}, err
+	}
+
+	return ParseRuleSet(data)
+}
+
+// ParseRuleSet reads YAML, JSON works as well since it is valid YAML
+func ParseRuleSet(data []byte) (RuleSet, error) {
+	var rs RuleSet
+	if err := yaml.Unmarshal(data, &rs); err != nil {
+		return RuleSet{}, fmt.Errorf("discount rules: %w", err)
+	}
+	if err := rs.prepare(); err != nil {
+		return RuleSet{}, fmt.Errorf("discount rules: %w", err)
+	}
+
+	return rs, nil
+}
+
+func (rs *RuleSet) prepare() error {
+	switch rs.Combine {
+	case "":
+		rs.Combine = CombineFirst
+	case CombineFirst, CombineSum:
+	default:
+		return fmt.Errorf("unknown combine %q", rs.Combine)
+	}
+	if rs.MaxPercent == 0 {
+		rs.MaxPercent = 100
+	}
+	if rs.MaxPercent < 0 || rs.MaxPercent > 100 {
+		return fmt.Errorf("max_percent must be between 0 and 100")
+	}
+
+	names := make(map[string]bool, len(rs.Rules))
+	for i := range rs.Rules {
+		r := &rs.Rules[i]
+		if r.Name == "" {
+			return fmt.Errorf("rule %d has no name", i)
+		}
+		if names[r.Name] {
+			return fmt.Errorf("duplicate rule %q", r.Name)
+		}
+		names[r.Name] = true
+		if r.Percent <= 0 || r.Percent > 100 {
+			return fmt.Errorf("rule %q: percent must be between 0 and 100", r.Name)
+		}
+
+		var err error
+		if r.When.From != "" {
+			if r.When.from, err = time.Parse(time.RFC3339, r.When.From); err != nil {
+				return fmt.Errorf("rule %q: from: %w", r.Name, err)
+			}
+		}
+		if r.When.Until != "" {
+			if r.When.until, err = time.Parse(time.RFC3339, r.When.Until); err != nil {
+				return fmt.Errorf("rule %q: until: %w", r.Name, err)
+			}
+		}
+		for _, day := range r.When.Days {
+			if !isWeekday(day) {
+				return fmt.Errorf("rule %q: unknown day %q", r.Name, day)
+			}
+		}
+	}
+
+	sort.SliceStable(rs.Rules, func(i, j int) bool {
+		if rs.Rules[i].Priority != rs.Rules[j].Priority {
+			return rs.Rules[i].Priority < rs.Rules[j].Priority
+		}
+		return rs.Rules[i].Name < rs.Rules[j].Name
+	})
+
+	return nil
+}
+
+func isWeekday(day string) bool {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(d.String(), day) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// match returns why the condition failed, or what made it pass
+func (c Condition) match(input model.DiscountInput) (bool, string) {
+	var passed []string
+
+	if c.ContinentSoldOut {
+		continent, ok := continentSoldOut(input)
+		if !ok {
+			return false, "no previously bought continent is sold out"
+		}
+		passed = append(passed, fmt.Sprintf("continent %s is sold out", continent))
+	}
+	if c.FirstPurchase {
+		if len(input.PreviousContinents) > 0 {
+			return false, "user has bought before"
+		}
+		passed = append(passed, "first purchase")
+	}
+	if c.MinQuantity > 0 {
+		if input.TotalTicket < c.MinQuantity {
+			return false, fmt.Sprintf("quantity %d is below %d", input.TotalTicket, c.MinQuantity)
+		}
+		passed = append(passed, fmt.Sprintf("quantity %d >= %d", input.TotalTicket, c.MinQuantity))
+	}
+	if len(c.Continents) > 0 {
+		found := ""
+		for _, continent := range input.Continents {
+			if containsFold(c.Continents, continent) {
+				found = continent
+				break
+			}
+		}
+		if found == "" {
+			return false, fmt.Sprintf("continents %s are not listed", strings.Join(input.Continents, ", "))
+		}
+		passed = append(passed, fmt.Sprintf("continent %s", found))
+	}
+	if !c.from.IsZero() && input.Now.Before(c.from) {
+		return false, fmt.Sprintf("starts at %s", c.From)
+	}
+	if !c.until.IsZero() && !input.Now.Before(c.until) {
+		return false, fmt.Sprintf("ended at %s", c.Until)
+	}
+	if !c.from.IsZero() || !c.until.IsZero() {
+		passed = append(passed, "inside time window")
+	}
+	if len(c.Days) > 0 {
+		today := input.Now.Weekday().String()
+		found := false
+		for _, day := range c.Days {
+			if strings.EqualFold(day, today) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false, fmt.Sprintf("not valid on %s", today)
+		}
+		passed = append(passed, fmt.Sprintf("valid on %s", today))
+	}
+
+	if len(passed) == 0 {
+		return true, "always"
+	}
+
+	return true, strings.Join(passed, ", ")
+}
+
+type localPolicy struct {
+	rules RuleSet
+}
+
+// NewLocalPolicy expects a RuleSet from LoadRuleSet or ParseRuleSet
+func NewLocalPolicy(rules RuleSet) DiscountPolicy {
+	return &localPolicy{rules: rules}
+}
+
+func (p *localPolicy) Evaluate(input model.DiscountInput) (model.DiscountResult, error) {
+	var result model.DiscountResult
+	applied := ""
+
+	for _, rule := range p.rules.Rules {
+		if applied != "" && p.rules.Combine == CombineFirst {
+			result.Trace = append(result.Trace, model.DiscountTrace{
+				Rule:   rule.Name,
+				Reason: fmt.Sprintf("skipped, %s already applied", applied),
+			})
+			continue
+		}
+
+		matched, reason := rule.When.match(input)
+		trace := model.DiscountTrace{Rule: rule.Name, Matched: matched, Reason: reason}
+		if matched {
+			trace.Percent = rule.Percent
+			result.Percent += rule.Percent
+			applied = rule.Name
+		}
+		result.Trace = append(result.Trace, trace)
+	}
+
+	if result.Percent > p.rules.MaxPercent {
+		result.Percent = p.rules.MaxPercent
+		result.Trace = append(result.Trace, model.DiscountTrace{
+			Rule:    "max_percent",
+			Matched: true,
+			Percent: p.rules.MaxPercent,
+			Reason:  fmt.Sprintf("capped at %g%%", p.rules.MaxPercent),
+		})
+	}
+
+	return result, nil
+}
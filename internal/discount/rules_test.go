@@ -0,0 +1,168 @@
+package discount
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+const testRules = `
+combine: first
+rules:
+  - name: first_purchase
+    priority: 30
+    percent: 5
+    when:
+      first_purchase: true
+  - name: continent_sold_out
+    priority: 10
+    percent: 20
+    when:
+      continent_sold_out: true
+  - name: bulk_quantity
+    priority: 20
+    percent: 10
+    when:
+      min_quantity: 5
+  - name: weekend
+    priority: 20
+    percent: 3
+    when:
+      days: [saturday, sunday]
+      from: "2026-10-01T00:00:00+07:00"
+      until: "2026-11-01T00:00:00+07:00"
+`
+
+func TestParseRuleSet(t *testing.T) {
+	rs, err := ParseRuleSet([]byte(testRules))
+	assert.NoError(t, err)
+	assert.Equal(t, CombineFirst, rs.Combine)
+	assert.Equal(t, float32(100), rs.MaxPercent)
+
+	var names []string
+	for _, r := range rs.Rules {
+		names = append(names, r.Name)
+	}
+	assert.Equal(t, []string{"continent_sold_out", "bulk_quantity", "weekend", "first_purchase"}, names)
+
+	_, err = ParseRuleSet([]byte(`{"rules": [{"name": "json", "percent": 10}]}`))
+	assert.NoError(t, err)
+
+	for _, invalid := range []string{
+		`combine: best`,
+		`rules: [{percent: 10}]`,
+		`rules: [{name: a, percent: 10}, {name: a, percent: 5}]`,
+		`rules: [{name: a, percent: 0}]`,
+		`rules: [{name: a, percent: 10, when: {days: [someday]}}]`,
+		`rules: [{name: a, percent: 10, when: {from: tomorrow}}]`,
+	} {
+		_, err := ParseRuleSet([]byte(invalid))
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestLocalPolicy(t *testing.T) {
+	rs, err := ParseRuleSet([]byte(testRules))
+	assert.NoError(t, err)
+	policy := NewLocalPolicy(rs)
+
+	saturday := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	monday := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	t.Run("sold out continent wins over later rules", func(t *testing.T) {
+		result, err := policy.Evaluate(model.DiscountInput{
+			Continents:         []string{"Asia"},
+			SoldOutContinents:  []string{"Europe"},
+			PreviousContinents: []string{"Europe"},
+			TotalTicket:        6,
+			Now:                monday,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, float32(20), result.Percent)
+		assert.Len(t, result.Trace, 4)
+		assert.True(t, result.Trace[0].Matched)
+		assert.Equal(t, "skipped, continent_sold_out already applied", result.Trace[1].Reason)
+	})
+
+	t.Run("continent in the cart does not count as sold out", func(t *testing.T) {
+		result, err := policy.Evaluate(model.DiscountInput{
+			Continents:         []string{"Asia", "Europe"},
+			SoldOutContinents:  []string{"Europe"},
+			PreviousContinents: []string{"Europe"},
+			TotalTicket:        1,
+			Now:                monday,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, float32(0), result.Percent)
+		for _, trace := range result.Trace {
+			assert.False(t, trace.Matched, trace.Rule)
+		}
+	})
+
+	t.Run("time window and days", func(t *testing.T) {
+		result, err := policy.Evaluate(model.DiscountInput{
+			Continents:         []string{"Asia"},
+			PreviousContinents: []string{"Asia"},
+			TotalTicket:        1,
+			Now:                saturday,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, float32(3), result.Percent)
+		assert.Equal(t, "weekend", result.Trace[2].Rule)
+		assert.Equal(t, "inside time window, valid on Saturday", result.Trace[2].Reason)
+	})
+
+	t.Run("first purchase", func(t *testing.T) {
+		result, err := policy.Evaluate(model.DiscountInput{Continents: []string{"Asia"}, TotalTicket: 1, Now: monday})
+		assert.NoError(t, err)
+		assert.Equal(t, float32(5), result.Percent)
+	})
+}
+
+func TestLocalPolicySum(t *testing.T) {
+	rs, err := ParseRuleSet([]byte(`
+combine: sum
+max_percent: 12
+rules:
+  - {name: bulk, percent: 10, when: {min_quantity: 2}}
+  - {name: first, percent: 5, when: {first_purchase: true}}
+`))
+	assert.NoError(t, err)
+
+	result, err := NewLocalPolicy(rs).Evaluate(model.DiscountInput{TotalTicket: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, float32(12), result.Percent)
+	assert.Equal(t, "max_percent", result.Trace[len(result.Trace)-1].Rule)
+}
+
+func TestGrulePolicy(t *testing.T) {
+	called := 0
+	policy := &grulePolicy{checkDiscount: func(d model.Discount) (float32, error) {
+		called++
+		assert.True(t, d.IsContinentSoldOut)
+		assert.True(t, d.IsContinentDiff)
+		return 20, nil
+	}}
+
+	result, err := policy.Evaluate(model.DiscountInput{Continents: []string{"Asia"}, PreviousContinents: []string{"Asia"}})
+	assert.NoError(t, err)
+	assert.Equal(t, float32(0), result.Percent)
+	assert.Equal(t, 0, called)
+
+	result, err = policy.Evaluate(model.DiscountInput{
+		Continents:         []string{"Asia"},
+		SoldOutContinents:  []string{"Europe"},
+		PreviousContinents: []string{"Europe", "Asia"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, float32(20), result.Percent)
+	assert.Equal(t, 1, called)
+	assert.True(t, result.Trace[0].Matched)
+}
+
+func TestDefaultRulesFile(t *testing.T) {
+	_, err := LoadRuleSet("../../" + defaultRulesFile)
+	assert.NoError(t, err)
+}
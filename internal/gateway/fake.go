@@ -0,0 +1,266 @@
+package gateway
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/midtrans/midtrans-go/snap"
+)
+
+type fakeOrder struct {
+	transactionID     string
+	grossAmount       int64
+	paymentType       string
+	transactionStatus string
+	fraudStatus       string
+}
+
+// FakeGateway is an in-process stand-in for midtrans. Orders are kept in memory and every
+// status change is pushed to Notify with the same payload shape midtrans posts to the webhook.
+type FakeGateway struct {
+	mu     sync.Mutex
+	orders map[string]*fakeOrder
+	seq    int
+
+	Notify func(payload map[string]interface{})
+}
+
+func NewFakeGateway(notify func(payload map[string]interface{})) *FakeGateway {
+	return &FakeGateway{
+		orders: make(map[string]*fakeOrder),
+		Notify: notify,
+	}
+}
+
+func fakeError(statusCode int, message string) error {
+	return &midtrans.Error{
+		Message:    message,
+		StatusCode: statusCode,
+	}
+}
+
+func (f *FakeGateway) CreateCharge(req *snap.Request) (*snap.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	orderID := req.TransactionDetails.OrderID
+	if _, exists := f.orders[orderID]; exists {
+		return nil, fakeError(http.StatusNotAcceptable, "transaction_details.order_id has already been taken")
+	}
+
+	f.seq++
+	f.orders[orderID] = &fakeOrder{
+		transactionID: fmt.Sprintf("fake-%d", f.seq),
+		grossAmount:   req.TransactionDetails.GrossAmt,
+	}
+
+	token := fmt.Sprintf("fake-token-%d", f.seq)
+	return &snap.Response{
+		Token:       token,
+		RedirectURL: "https://fake.gateway.local/snap/v2/vtweb/" + token,
+		StatusCode:  "201",
+	}, nil
+}
+
+func (f *FakeGateway) DirectCharge(req *coreapi.ChargeReq) (*coreapi.ChargeResponse, error) {
+	f.mu.Lock()
+	orderID := req.TransactionDetails.OrderID
+	if _, exists := f.orders[orderID]; exists {
+		f.mu.Unlock()
+		return nil, fakeError(http.StatusNotAcceptable, "transaction_details.order_id has already been taken")
+	}
+	f.seq++
+	f.orders[orderID] = &fakeOrder{
+		transactionID:     fmt.Sprintf("fake-%d", f.seq),
+		grossAmount:       req.TransactionDetails.GrossAmt,
+		paymentType:       string(req.PaymentType),
+		transactionStatus: "pending",
+	}
+	seq := f.seq
+	f.mu.Unlock()
+
+	// no state change, only emits the pending notification midtrans sends after a charge
+	resp, err := f.charge(orderID, func(o *fakeOrder) error { return nil })
+	if err != nil {
+		return nil, err
+	}
+
+	resp.StatusCode = "201"
+	switch req.PaymentType {
+	case coreapi.PaymentTypeBankTransfer:
+		resp.VaNumbers = []coreapi.VANumber{{
+			Bank:     string(req.BankTransfer.Bank),
+			VANumber: fmt.Sprintf("8800%08d", seq),
+		}}
+	case coreapi.PaymentTypeQris:
+		resp.QRString = fmt.Sprintf("00020101021226620014COM.FAKE.WWW01189360091800%08d5204599953033605802ID6304FAKE", seq)
+	}
+
+	return resp, nil
+}
+
+// Pay simulates the customer picking a payment channel in snap, after which the order exists
+// on the gateway side as pending
+func (f *FakeGateway) Pay(orderID, paymentType string) error {
+	return f.transition(orderID, func(o *fakeOrder) error {
+		if o.transactionStatus != "" {
+			return fakeError(http.StatusPreconditionFailed, "transaction already paid")
+		}
+		o.paymentType = paymentType
+		o.transactionStatus = "pending"
+		return nil
+	})
+}
+
+// SetStatus forces any midtrans transaction_status/fraud_status pair, e.g. "settlement" or
+// "capture"/"challenge", and emits the matching notification
+func (f *FakeGateway) SetStatus(orderID, transactionStatus, fraudStatus string) error {
+	return f.transition(orderID, func(o *fakeOrder) error {
+		o.transactionStatus = transactionStatus
+		o.fraudStatus = fraudStatus
+		return nil
+	})
+}
+
+func (f *FakeGateway) CheckStatus(orderID string) (*coreapi.TransactionStatusResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	o, ok := f.orders[orderID]
+	if !ok || o.transactionStatus == "" {
+		return nil, fakeError(http.StatusNotFound, "Transaction doesn't exist.")
+	}
+
+	return &coreapi.TransactionStatusResponse{
+		StatusCode:        "200",
+		OrderID:           orderID,
+		TransactionID:     o.transactionID,
+		TransactionStatus: o.transactionStatus,
+		FraudStatus:       o.fraudStatus,
+		PaymentType:       o.paymentType,
+		GrossAmount:       strconv.FormatInt(o.grossAmount, 10) + ".00",
+		Currency:          "IDR",
+	}, nil
+}
+
+func (f *FakeGateway) Cancel(orderID string) (*coreapi.CancelResponse, error) {
+	return f.charge(orderID, func(o *fakeOrder) error {
+		if o.transactionStatus != "pending" && !(o.transactionStatus == "capture" && o.fraudStatus == "challenge") {
+			return fakeError(http.StatusPreconditionFailed, "Merchant cannot modify the status of the transaction")
+		}
+		o.transactionStatus = "cancel"
+		return nil
+	})
+}
+
+func (f *FakeGateway) Refund(orderID string, req *coreapi.RefundReq) (*coreapi.RefundResponse, error) {
+	resp, err := f.charge(orderID, func(o *fakeOrder) error {
+		if o.transactionStatus != "settlement" {
+			return fakeError(http.StatusPreconditionFailed, "Merchant cannot modify the status of the transaction")
+		}
+		o.transactionStatus = "refund"
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &coreapi.RefundResponse{
+		StatusCode:        resp.StatusCode,
+		StatusMessage:     "Success, refund request is approved",
+		TransactionID:     resp.TransactionID,
+		OrderID:           orderID,
+		GrossAmount:       resp.GrossAmount,
+		Currency:          "IDR",
+		PaymentType:       resp.PaymentType,
+		TransactionStatus: resp.TransactionStatus,
+		RefundKey:         req.RefundKey,
+		RefundAmount:      strconv.FormatInt(req.Amount, 10),
+	}, nil
+}
+
+func (f *FakeGateway) Approve(orderID string) (*coreapi.ApproveResponse, error) {
+	return f.charge(orderID, func(o *fakeOrder) error {
+		if o.transactionStatus != "capture" || o.fraudStatus != "challenge" {
+			return fakeError(http.StatusPreconditionFailed, "Merchant cannot modify the status of the transaction")
+		}
+		o.fraudStatus = "accept"
+		return nil
+	})
+}
+
+func (f *FakeGateway) Deny(orderID string) (*coreapi.DenyResponse, error) {
+	return f.charge(orderID, func(o *fakeOrder) error {
+		if o.transactionStatus != "capture" || o.fraudStatus != "challenge" {
+			return fakeError(http.StatusPreconditionFailed, "Merchant cannot modify the status of the transaction")
+		}
+		o.transactionStatus = "deny"
+		o.fraudStatus = "deny"
+		return nil
+	})
+}
+
+func (f *FakeGateway) charge(orderID string, apply func(o *fakeOrder) error) (*coreapi.ChargeResponse, error) {
+	var resp *coreapi.ChargeResponse
+	err := f.transition(orderID, func(o *fakeOrder) error {
+		if o.transactionStatus == "" {
+			return fakeError(http.StatusNotFound, "Transaction doesn't exist.")
+		}
+		if err := apply(o); err != nil {
+			return err
+		}
+		resp = &coreapi.ChargeResponse{
+			StatusCode:        "200",
+			TransactionID:     o.transactionID,
+			OrderID:           orderID,
+			GrossAmount:       strconv.FormatInt(o.grossAmount, 10) + ".00",
+			PaymentType:       o.paymentType,
+			TransactionTime:   time.Now().Format("2006-01-02 15:04:05"),
+			TransactionStatus: o.transactionStatus,
+			FraudStatus:       o.fraudStatus,
+			Currency:          "IDR",
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (f *FakeGateway) transition(orderID string, apply func(o *fakeOrder) error) error {
+	f.mu.Lock()
+	o, ok := f.orders[orderID]
+	if !ok {
+		f.mu.Unlock()
+		return fakeError(http.StatusNotFound, "Transaction doesn't exist.")
+	}
+	if err := apply(o); err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	payload := map[string]interface{}{
+		"order_id":           orderID,
+		"transaction_id":     o.transactionID,
+		"transaction_status": o.transactionStatus,
+		"fraud_status":       o.fraudStatus,
+		"payment_type":       o.paymentType,
+		"gross_amount":       strconv.FormatInt(o.grossAmount, 10) + ".00",
+		"status_code":        "200",
+		"transaction_time":   time.Now().Format("2006-01-02 15:04:05"),
+	}
+	f.mu.Unlock()
+
+	// notify outside the lock, the webhook handler usually calls CheckStatus right away
+	if f.Notify != nil {
+		f.Notify(payload)
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package gateway
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/midtrans/midtrans-go/snap"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeGateway(t *testing.T) {
+	var notifications []map[string]interface{}
+	f := NewFakeGateway(func(payload map[string]interface{}) {
+		notifications = append(notifications, payload)
+	})
+
+	req := &snap.Request{
+		TransactionDetails: midtrans.TransactionDetails{OrderID: "ORDER-1", GrossAmt: 160450},
+	}
+
+	t.Run("create charge returns snap token and rejects duplicate order id", func(t *testing.T) {
+		resp, err := f.CreateCharge(req)
+		assert.Nil(t, err)
+		assert.NotEmpty(t, resp.Token)
+		assert.NotEmpty(t, resp.RedirectURL)
+
+		_, err = f.CreateCharge(req)
+		assert.Equal(t, http.StatusNotAcceptable, StatusCode(err))
+	})
+
+	t.Run("order does not exist on gateway until paid", func(t *testing.T) {
+		_, err := f.CheckStatus("ORDER-1")
+		assert.Equal(t, http.StatusNotFound, StatusCode(err))
+	})
+
+	t.Run("settlement emits notification and blocks cancel", func(t *testing.T) {
+		assert.Nil(t, f.Pay("ORDER-1", "qris"))
+		assert.Nil(t, f.SetStatus("ORDER-1", "settlement", "accept"))
+
+		last := notifications[len(notifications)-1]
+		assert.Equal(t, "ORDER-1", last["order_id"])
+		assert.Equal(t, "settlement", last["transaction_status"])
+
+		status, err := f.CheckStatus("ORDER-1")
+		assert.Nil(t, err)
+		assert.Equal(t, "160450.00", status.GrossAmount)
+
+		_, err = f.Cancel("ORDER-1")
+		assert.Equal(t, http.StatusPreconditionFailed, StatusCode(err))
+	})
+
+	t.Run("refund settled order", func(t *testing.T) {
+		resp, err := f.Refund("ORDER-1", &coreapi.RefundReq{RefundKey: "refund-1", Amount: 160450})
+		assert.Nil(t, err)
+		assert.Equal(t, "refund", resp.TransactionStatus)
+	})
+
+	t.Run("approve and deny only apply to challenged capture", func(t *testing.T) {
+		_, err := f.CreateCharge(&snap.Request{
+			TransactionDetails: midtrans.TransactionDetails{OrderID: "ORDER-2", GrossAmt: 1000},
+		})
+		assert.Nil(t, err)
+		assert.Nil(t, f.Pay("ORDER-2", "credit_card"))
+
+		_, err = f.Approve("ORDER-2")
+		assert.Equal(t, http.StatusPreconditionFailed, StatusCode(err))
+
+		assert.Nil(t, f.SetStatus("ORDER-2", "capture", "challenge"))
+		resp, err := f.Approve("ORDER-2")
+		assert.Nil(t, err)
+		assert.Equal(t, "accept", resp.FraudStatus)
+	})
+}
+
+func TestFakeGatewayDirectCharge(t *testing.T) {
+	f := NewFakeGateway(nil)
+
+	details := midtrans.TransactionDetails{OrderID: "ORDER-VA", GrossAmt: 160450}
+	req, _ := NewChargeRequest("bca_va", details, nil)
+	resp, err := f.DirectCharge(req)
+	assert.Nil(t, err)
+	assert.Equal(t, "pending", resp.TransactionStatus)
+	assert.NotEmpty(t, resp.VaNumbers[0].VANumber)
+
+	details.OrderID = "ORDER-QR"
+	req, _ = NewChargeRequest("qris", details, nil)
+	resp, err = f.DirectCharge(req)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, resp.QRString)
+
+	_, err = f.Cancel("ORDER-QR")
+	assert.Nil(t, err)
+}
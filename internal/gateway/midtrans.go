@@ -0,0 +1,89 @@
+package gateway
+
+import (
+	"os"
+
+	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/midtrans/midtrans-go/snap"
+)
+
+type midtransGateway struct {
+	snap snap.Client
+	core coreapi.Client
+}
+
+func NewMidtransGateway() PaymentGateway {
+	serverKey := os.Getenv("MIDTRANS_SERVER_KEY")
+	env := Environment()
+
+	g := &midtransGateway{}
+	g.snap.New(serverKey, env)
+	g.core.New(serverKey, env)
+
+	return g
+}
+
+// the midtrans client returns a typed *midtrans.Error, so every method checks it before
+// returning to avoid handing out a non-nil error interface holding a nil pointer
+func (g *midtransGateway) CreateCharge(req *snap.Request) (*snap.Response, error) {
+	resp, err := g.snap.CreateTransaction(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *midtransGateway) DirectCharge(req *coreapi.ChargeReq) (*coreapi.ChargeResponse, error) {
+	resp, err := g.core.ChargeTransaction(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *midtransGateway) CheckStatus(orderID string) (*coreapi.TransactionStatusResponse, error) {
+	resp, err := g.core.CheckTransaction(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *midtransGateway) Cancel(orderID string) (*coreapi.CancelResponse, error) {
+	resp, err := g.core.CancelTransaction(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *midtransGateway) Refund(orderID string, req *coreapi.RefundReq) (*coreapi.RefundResponse, error) {
+	resp, err := g.core.RefundTransaction(orderID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *midtransGateway) Approve(orderID string) (*coreapi.ApproveResponse, error) {
+	resp, err := g.core.ApproveTransaction(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (g *midtransGateway) Deny(orderID string) (*coreapi.DenyResponse, error) {
+	resp, err := g.core.DenyTransaction(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,37 @@
+package gateway
+
+import (
+	"os"
+
+	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/midtrans/midtrans-go/snap"
+)
+
+type PaymentGateway interface {
+	CreateCharge(req *snap.Request) (*snap.Response, error)
+	DirectCharge(req *coreapi.ChargeReq) (*coreapi.ChargeResponse, error)
+	CheckStatus(orderID string) (*coreapi.TransactionStatusResponse, error)
+	Cancel(orderID string) (*coreapi.CancelResponse, error)
+	Refund(orderID string, req *coreapi.RefundReq) (*coreapi.RefundResponse, error)
+	Approve(orderID string) (*coreapi.ApproveResponse, error)
+	Deny(orderID string) (*coreapi.DenyResponse, error)
+}
+
+// Environment reads MIDTRANS_ENVIRONMENT, anything other than "production" falls back to sandbox
+func Environment() midtrans.EnvironmentType {
+	if os.Getenv("MIDTRANS_ENVIRONMENT") == "production" {
+		return midtrans.Production
+	}
+
+	return midtrans.Sandbox
+}
+
+// StatusCode returns the HTTP status code reported by the gateway, or 0 when err did not come from it
+func StatusCode(err error) int {
+	if e, ok := err.(*midtrans.Error); ok {
+		return e.GetStatusCode()
+	}
+
+	return 0
+}
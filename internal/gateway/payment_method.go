@@ -0,0 +1,131 @@
+package gateway
+
+import (
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/midtrans/midtrans-go/snap"
+)
+
+const (
+	PaymentTypeBankTransfer = "bank_transfer"
+	PaymentTypeEWallet      = "e_wallet"
+	PaymentTypeCreditCard   = "credit_card"
+	PaymentTypeQris         = "qris"
+)
+
+type paymentChannel struct {
+	method model.PaymentMethod
+	snap   []snap.SnapPaymentType
+	// charge fills the channel specific part of a core api request, nil when the channel is snap only
+	charge func(req *coreapi.ChargeReq)
+}
+
+func bankTransfer(bank midtrans.Bank) func(req *coreapi.ChargeReq) {
+	return func(req *coreapi.ChargeReq) {
+		req.PaymentType = coreapi.PaymentTypeBankTransfer
+		req.BankTransfer = &coreapi.BankTransferDetails{Bank: bank}
+	}
+}
+
+var paymentChannels = []paymentChannel{
+	{
+		method: model.PaymentMethod{Code: "bca_va", Name: "BCA Virtual Account", Type: PaymentTypeBankTransfer},
+		snap:   []snap.SnapPaymentType{snap.PaymentTypeBCAVA},
+		charge: bankTransfer(midtrans.BankBca),
+	},
+	{
+		method: model.PaymentMethod{Code: "bni_va", Name: "BNI Virtual Account", Type: PaymentTypeBankTransfer},
+		snap:   []snap.SnapPaymentType{snap.PaymentTypeBNIVA},
+		charge: bankTransfer(midtrans.BankBni),
+	},
+	{
+		method: model.PaymentMethod{Code: "bri_va", Name: "BRI Virtual Account", Type: PaymentTypeBankTransfer},
+		snap:   []snap.SnapPaymentType{snap.PaymentTypeBRIVA},
+		charge: bankTransfer(midtrans.BankBri),
+	},
+	{
+		method: model.PaymentMethod{Code: "permata_va", Name: "Permata Virtual Account", Type: PaymentTypeBankTransfer},
+		snap:   []snap.SnapPaymentType{snap.PaymentTypePermataVA},
+		charge: bankTransfer(midtrans.BankPermata),
+	},
+	{
+		method: model.PaymentMethod{Code: "mandiri_bill", Name: "Mandiri Bill Payment", Type: PaymentTypeBankTransfer},
+		snap:   []snap.SnapPaymentType{snap.PaymentTypeEChannel},
+	},
+	{
+		method: model.PaymentMethod{Code: "gopay", Name: "GoPay", Type: PaymentTypeEWallet},
+		snap:   []snap.SnapPaymentType{snap.PaymentTypeGopay},
+	},
+	{
+		method: model.PaymentMethod{Code: "shopeepay", Name: "ShopeePay", Type: PaymentTypeEWallet},
+		snap:   []snap.SnapPaymentType{snap.PaymentTypeShopeepay},
+	},
+	{
+		method: model.PaymentMethod{Code: "credit_card", Name: "Credit / Debit Card", Type: PaymentTypeCreditCard},
+		snap:   []snap.SnapPaymentType{snap.PaymentTypeCreditCard},
+	},
+	{
+		method: model.PaymentMethod{Code: "qris", Name: "QRIS", Type: PaymentTypeQris},
+		snap:   []snap.SnapPaymentType{"other_qris"},
+		charge: func(req *coreapi.ChargeReq) {
+			req.PaymentType = coreapi.PaymentTypeQris
+			req.Qris = &coreapi.QrisDetails{}
+		},
+	},
+}
+
+func findPaymentChannel(code string) (paymentChannel, bool) {
+	for _, pc := range paymentChannels {
+		if pc.method.Code == code {
+			return pc, true
+		}
+	}
+
+	return paymentChannel{}, false
+}
+
+func PaymentMethods() []model.PaymentMethod {
+	methods := make([]model.PaymentMethod, 0, len(paymentChannels))
+	for _, pc := range paymentChannels {
+		method := pc.method
+		method.DirectCharge = pc.charge != nil
+		methods = append(methods, method)
+	}
+
+	return methods
+}
+
+func FindPaymentMethod(code string) (model.PaymentMethod, bool) {
+	pc, ok := findPaymentChannel(code)
+	if !ok {
+		return model.PaymentMethod{}, false
+	}
+
+	method := pc.method
+	method.DirectCharge = pc.charge != nil
+	return method, true
+}
+
+// EnabledPayments returns the snap channels to show for a payment method code
+func EnabledPayments(code string) []snap.SnapPaymentType {
+	pc, _ := findPaymentChannel(code)
+	return pc.snap
+}
+
+// NewChargeRequest builds a core api direct charge for the payment method, ok is false when
+// the method can only be paid through snap
+func NewChargeRequest(code string, details midtrans.TransactionDetails, customer *midtrans.CustomerDetails) (*coreapi.ChargeReq, bool) {
+	pc, found := findPaymentChannel(code)
+	if !found || pc.charge == nil {
+		return nil, false
+	}
+
+	req := &coreapi.ChargeReq{
+		TransactionDetails: details,
+		CustomerDetails:    customer,
+	}
+	pc.charge(req)
+
+	return req, true
+}
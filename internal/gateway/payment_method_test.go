@@ -0,0 +1,58 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/midtrans/midtrans-go/snap"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindPaymentMethod(t *testing.T) {
+	t.Run("known method", func(t *testing.T) {
+		method, ok := FindPaymentMethod("bca_va")
+		assert.True(t, ok)
+		assert.Equal(t, PaymentTypeBankTransfer, method.Type)
+		assert.True(t, method.DirectCharge)
+	})
+
+	t.Run("snap only method", func(t *testing.T) {
+		method, ok := FindPaymentMethod("credit_card")
+		assert.True(t, ok)
+		assert.False(t, method.DirectCharge)
+	})
+
+	t.Run("unknown method", func(t *testing.T) {
+		_, ok := FindPaymentMethod("cash")
+		assert.False(t, ok)
+	})
+}
+
+func TestEnabledPayments(t *testing.T) {
+	assert.Equal(t, []snap.SnapPaymentType{snap.PaymentTypeGopay}, EnabledPayments("gopay"))
+	assert.Nil(t, EnabledPayments("cash"))
+}
+
+func TestNewChargeRequest(t *testing.T) {
+	details := midtrans.TransactionDetails{OrderID: "ORDER-1", GrossAmt: 160450}
+
+	t.Run("bank transfer", func(t *testing.T) {
+		req, ok := NewChargeRequest("bni_va", details, nil)
+		assert.True(t, ok)
+		assert.Equal(t, coreapi.PaymentTypeBankTransfer, req.PaymentType)
+		assert.Equal(t, midtrans.BankBni, req.BankTransfer.Bank)
+		assert.Equal(t, details, req.TransactionDetails)
+	})
+
+	t.Run("qris", func(t *testing.T) {
+		req, ok := NewChargeRequest("qris", details, nil)
+		assert.True(t, ok)
+		assert.Equal(t, coreapi.PaymentTypeQris, req.PaymentType)
+	})
+
+	t.Run("snap only", func(t *testing.T) {
+		_, ok := NewChargeRequest("shopeepay", details, nil)
+		assert.False(t, ok)
+	})
+}
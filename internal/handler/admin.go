@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/usecase"
+	"github.com/SyamSolution/transaction-service/internal/util"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type admin struct {
+	adminUsecase usecase.AdminExecutor
+	logger       config.Logger
+	validate     *validator.Validate
+}
+
+type AdminHandler interface {
+	SearchTransactions(c *fiber.Ctx) error
+	GetTransactionDetail(c *fiber.Ctx) error
+	ResyncTransaction(c *fiber.Ctx) error
+	ResendMessage(c *fiber.Ctx) error
+}
+
+func NewAdminHandler(adminUsecase usecase.AdminExecutor, logger config.Logger) AdminHandler {
+	return &admin{adminUsecase: adminUsecase, logger: logger, validate: validator.New()}
+}
+
+// adminError writes the response of a failed admin action
+func (h *admin) adminError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, usecase.ErrTransactionNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusNotFound,
+				Message: "Transaction not found",
+			},
+		})
+	case errors.Is(err, usecase.ErrTransactionNotCompleted):
+		return c.Status(fiber.StatusConflict).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusConflict,
+				Message: err.Error(),
+			},
+		})
+	}
+
+	h.logger.Error(message, zap.Error(err))
+	return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
+		Meta: model.Meta{
+			Code:    fiber.StatusInternalServerError,
+			Message: util.ERROR_BASE_MSG,
+		},
+	})
+}
+
+func invalidTransactionIDResponse(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+		Meta: model.Meta{
+			Code:    fiber.StatusBadRequest,
+			Message: util.ERROR_INVALID_PARAM_MSG,
+		},
+	})
+}
+
+func (h *admin) SearchTransactions(c *fiber.Ctx) error {
+	var request model.AdminTransactionSearchRequest
+	if err := c.QueryParser(&request); err != nil {
+		h.logger.Error("Error when parsing request", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: util.ERROR_NOT_FOUND_MSG,
+			},
+		})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(util.ValidatorErrorResponse(util.ValidateStruct(err)))
+	}
+
+	transactions, err := h.adminUsecase.SearchTransactions(request, c.Locals("email").(string))
+	if err != nil {
+		if errors.Is(err, usecase.ErrAdminSearch) {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+				Meta: model.Meta{
+					Code:    fiber.StatusBadRequest,
+					Message: util.ERROR_INVALID_PARAM_MSG,
+				},
+				Errors: []*model.ErrorFieldResponse{
+					{
+						Field:      "to",
+						ErrMessage: err.Error(),
+						Tag:        "range",
+					},
+				},
+			})
+		}
+		return h.adminError(c, err, "Error when searching transactions")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: transactions,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "List transaction retrieved successfully",
+		},
+	})
+}
+
+func (h *admin) GetTransactionDetail(c *fiber.Ctx) error {
+	transactionID, err := c.ParamsInt("transaction_id")
+	if err != nil {
+		return invalidTransactionIDResponse(c)
+	}
+
+	detail, err := h.adminUsecase.GetTransactionDetail(transactionID, c.Locals("email").(string))
+	if err != nil {
+		return h.adminError(c, err, "Error when getting transaction detail")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: detail,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Transaction retrieved successfully",
+		},
+	})
+}
+
+func (h *admin) ResyncTransaction(c *fiber.Ctx) error {
+	transactionID, err := c.ParamsInt("transaction_id")
+	if err != nil {
+		return invalidTransactionIDResponse(c)
+	}
+
+	result, err := h.adminUsecase.ResyncTransaction(transactionID, c.Locals("email").(string))
+	if err != nil {
+		return h.adminError(c, err, "Error when resyncing transaction")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: result,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Transaction resynced successfully",
+		},
+	})
+}
+
+func (h *admin) ResendMessage(c *fiber.Ctx) error {
+	transactionID, err := c.ParamsInt("transaction_id")
+	if err != nil {
+		return invalidTransactionIDResponse(c)
+	}
+
+	var request model.AdminResendRequest
+	if err := c.BodyParser(&request); err != nil {
+		h.logger.Error("Error when parsing request", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: util.ERROR_NOT_FOUND_MSG,
+			},
+		})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(util.ValidatorErrorResponse(util.ValidateStruct(err)))
+	}
+
+	if err := h.adminUsecase.ResendMessage(transactionID, request.Message, c.Locals("email").(string)); err != nil {
+		return h.adminError(c, err, "Error when resending message")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseWithoutData{
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Message resent successfully",
+		},
+	})
+}
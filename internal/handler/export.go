@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/usecase"
+	"github.com/SyamSolution/transaction-service/internal/util"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jung-kurt/gofpdf"
+	"go.uber.org/zap"
+)
+
+func (h *transaction) ExportTransactions(c *fiber.Ctx) error {
+	email := c.Locals("email").(string)
+
+	var request model.TransactionExportRequest
+	if err := c.QueryParser(&request); err != nil {
+		h.logger.Error("Error when parsing request", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: util.ERROR_NOT_FOUND_MSG,
+			},
+		})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(util.ValidatorErrorResponse(util.ValidateStruct(err)))
+	}
+
+	exports, err := h.transactionUsecase.ExportTransactions(email, request.From, request.To)
+	if err != nil {
+		if errors.Is(err, usecase.ErrExportRange) {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+				Meta: model.Meta{
+					Code:    fiber.StatusBadRequest,
+					Message: util.ERROR_INVALID_PARAM_MSG,
+				},
+				Errors: []*model.ErrorFieldResponse{
+					{
+						Field:      "to",
+						ErrMessage: err.Error(),
+						Tag:        "range",
+					},
+				},
+			})
+		}
+		h.logger.Error("Error when exporting transactions", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusInternalServerError,
+				Message: util.ERROR_BASE_MSG,
+			},
+		})
+	}
+
+	render := func(w io.Writer) error { return transactionExportCSV(w, exports) }
+	contentType := "text/csv"
+	if request.Format == "pdf" {
+		render = func(w io.Writer) error { return transactionExportPDF(w, exports, email, request.From, request.To) }
+		contentType = "application/pdf"
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="transactions-%s-%s.%s"`, request.From, request.To,
+		request.Format))
+	// status sudah terkirim saat menulis, error di tengah jalan hanya bisa dicatat
+	c.Status(fiber.StatusOK).Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := render(w); err != nil {
+			h.logger.Error("Error when writing transaction export", zap.Error(err))
+		}
+		if err := w.Flush(); err != nil {
+			h.logger.Error("Error when flushing transaction export", zap.Error(err))
+		}
+	})
+
+	return nil
+}
+
+func formatAmount(amount float32) string {
+	return strconv.FormatFloat(float64(amount), 'f', 2, 32)
+}
+
+// transactionExportCSV writes one row per line, the order columns are repeated on every line of the order
+func transactionExportCSV(w io.Writer, exports []model.TransactionExport) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"order_id", "transaction_date", "payment_status", "payment_method", "ticket_type", "continent",
+		"country_name", "city", "quantity", "price", "net_amount", "discount_percent", "voucher_code", "voucher_discount",
+		"total_amount", "total_idr"}); err != nil {
+		return err
+	}
+
+	for _, export := range exports {
+		for _, line := range export.Lines {
+			err := writer.Write([]string{export.OrderID, export.TransactionDate.Format("2006-01-02 15:04:05"), export.PaymentStatus,
+				export.PaymentMethod, line.TicketType, line.Continent, line.CountryName, line.City, strconv.Itoa(line.Quantity),
+				formatAmount(line.Price), formatAmount(line.NetAmount), formatAmount(export.Discount), export.VoucherCode,
+				formatAmount(export.VoucherDiscount), formatAmount(export.TotalAmount), strconv.FormatInt(export.TotalIDR, 10)})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// transactionExportPDF renders the history with the core fonts of gofpdf, so no font files or binaries are needed
+func transactionExportPDF(w io.Writer, exports []model.TransactionExport, email, from, to string) error {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.CellFormat(0, 5, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 8, "Transaction History", "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(0, 6, tr(email), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 6, fmt.Sprintf("Period %s to %s", from, to), "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+
+	if len(exports) == 0 {
+		pdf.CellFormat(0, 6, "No transactions in this period.", "", 1, "L", false, 0, "")
+		return pdf.Output(w)
+	}
+
+	widths := []float64{45, 65, 15, 25, 30}
+	var totalIDR int64
+	for _, export := range exports {
+		pdf.SetFont("Helvetica", "B", 11)
+		pdf.CellFormat(0, 7, tr(fmt.Sprintf("%s  -  %s  -  %s  -  %s", export.OrderID, export.TransactionDate.Format("02 Jan 2006 15:04"),
+			export.PaymentStatus, export.PaymentMethod)), "", 1, "L", false, 0, "")
+
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.SetFillColor(230, 230, 230)
+		for i, header := range []string{"Ticket", "Location", "Qty", "Price", "Net"} {
+			align := "L"
+			if i >= 2 {
+				align = "R"
+			}
+			pdf.CellFormat(widths[i], 6, header, "1", 0, align, true, 0, "")
+		}
+		pdf.Ln(-1)
+
+		pdf.SetFont("Helvetica", "", 9)
+		for _, line := range export.Lines {
+			pdf.CellFormat(widths[0], 6, tr(line.TicketType), "1", 0, "L", false, 0, "")
+			pdf.CellFormat(widths[1], 6, tr(fmt.Sprintf("%s, %s", line.City, line.CountryName)), "1", 0, "L", false, 0, "")
+			pdf.CellFormat(widths[2], 6, strconv.Itoa(line.Quantity), "1", 0, "R", false, 0, "")
+			pdf.CellFormat(widths[3], 6, formatAmount(line.Price), "1", 0, "R", false, 0, "")
+			pdf.CellFormat(widths[4], 6, formatAmount(line.NetAmount), "1", 1, "R", false, 0, "")
+		}
+
+		summary := [][2]string{}
+		if export.Discount > 0 {
+			summary = append(summary, [2]string{"Discount", formatAmount(export.Discount) + "%"})
+		}
+		if export.VoucherCode != "" {
+			summary = append(summary, [2]string{tr("Voucher " + export.VoucherCode), "-" + formatAmount(export.VoucherDiscount)})
+		}
+		summary = append(summary, [2]string{"Total", formatAmount(export.TotalAmount)},
+			[2]string{"Total (IDR)", strconv.FormatInt(export.TotalIDR, 10)})
+		for _, row := range summary {
+			pdf.CellFormat(widths[0]+widths[1]+widths[2]+widths[3], 6, row[0], "", 0, "R", false, 0, "")
+			pdf.CellFormat(widths[4], 6, row[1], "", 1, "R", false, 0, "")
+		}
+		pdf.Ln(4)
+
+		if export.PaymentStatus == "completed" {
+			totalIDR += export.TotalIDR
+		}
+	}
+
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(0, 7, fmt.Sprintf("%d orders, paid total IDR %d", len(exports), totalIDR), "T", 1, "R", false, 0, "")
+
+	return pdf.Output(w)
+}
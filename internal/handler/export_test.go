@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionExport(t *testing.T) {
+	exports := []model.TransactionExport{
+		{
+			OrderID:         "ORDER-1",
+			TransactionDate: time.Date(2026, 10, 2, 9, 30, 0, 0, time.UTC),
+			PaymentMethod:   "bca_va",
+			PaymentStatus:   "completed",
+			Discount:        10,
+			VoucherCode:     "HEMAT",
+			VoucherDiscount: 5,
+			TotalAmount:     175,
+			TotalIDR:        2807875,
+			Lines: []model.TransactionExportLine{
+				{TicketType: "VIP", Continent: "asia", CountryName: "Indonesia", City: "Jakarta", Quantity: 1, Price: 100, NetAmount: 87.5},
+				{TicketType: "CAT 1", Continent: "asia", CountryName: "Indonesia", City: "Jakarta", Quantity: 2, Price: 50, NetAmount: 87.5},
+			},
+		},
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := transactionExportCSV(&buf, exports)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "order_id,transaction_date,payment_status,payment_method,ticket_type,continent,country_name,city,quantity,"+
+			"price,net_amount,discount_percent,voucher_code,voucher_discount,total_amount,total_idr\n"+
+			"ORDER-1,2026-10-02 09:30:00,completed,bca_va,VIP,asia,Indonesia,Jakarta,1,100.00,87.50,10.00,HEMAT,5.00,175.00,2807875\n"+
+			"ORDER-1,2026-10-02 09:30:00,completed,bca_va,CAT 1,asia,Indonesia,Jakarta,2,50.00,87.50,10.00,HEMAT,5.00,175.00,2807875\n",
+			buf.String())
+	})
+
+	t.Run("pdf", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := transactionExportPDF(&buf, exports, "ani@example.com", "2026-10-01", "2026-10-31")
+
+		assert.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")))
+
+		buf.Reset()
+		err = transactionExportPDF(&buf, nil, "ani@example.com", "2026-10-01", "2026-10-31")
+
+		assert.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")))
+	})
+}
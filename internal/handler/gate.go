@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/usecase"
+	"github.com/SyamSolution/transaction-service/internal/util"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type gate struct {
+	gateUsecase usecase.GateExecutor
+	logger      config.Logger
+	validate    *validator.Validate
+}
+
+type GateHandler interface {
+	LookupTicket(c *fiber.Ctx) error
+	CheckIn(c *fiber.Ctx) error
+	SyncCheckIns(c *fiber.Ctx) error
+	GetAttendance(c *fiber.Ctx) error
+}
+
+func NewGateHandler(gateUsecase usecase.GateExecutor, logger config.Logger) GateHandler {
+	return &gate{gateUsecase: gateUsecase, logger: logger, validate: validator.New()}
+}
+
+func (h *gate) internalError(c *fiber.Ctx, err error) error {
+	h.logger.Error("Error when handling gate request", zap.Error(err))
+	return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
+		Meta: model.Meta{
+			Code:    fiber.StatusInternalServerError,
+			Message: util.ERROR_BASE_MSG,
+		},
+	})
+}
+
+func (h *gate) LookupTicket(c *fiber.Ctx) error {
+	response, err := h.gateUsecase.LookupTicket(c.Params("code"))
+	if err != nil {
+		if errors.Is(err, usecase.ErrIssuedTicketNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(model.ResponseWithoutData{
+				Meta: model.Meta{
+					Code:    fiber.StatusNotFound,
+					Message: "Ticket not found",
+				},
+			})
+		}
+		return h.internalError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: response,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Ticket retrieved successfully",
+		},
+	})
+}
+
+func (h *gate) CheckIn(c *fiber.Ctx) error {
+	var request model.CheckInRequest
+	if err := c.BodyParser(&request); err != nil {
+		h.logger.Error("Error when parsing request", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: util.ERROR_NOT_FOUND_MSG,
+			},
+		})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(util.ValidatorErrorResponse(util.ValidateStruct(err)))
+	}
+
+	issued, err := h.gateUsecase.CheckIn(request, c.Locals("email").(string))
+	switch {
+	case err == nil:
+		return c.Status(fiber.StatusOK).JSON(model.Response{
+			Data: issued,
+			Meta: model.Meta{
+				Code:    fiber.StatusOK,
+				Message: "Ticket checked in successfully",
+			},
+		})
+	case errors.Is(err, usecase.ErrTicketAlreadyUsed):
+		return c.Status(fiber.StatusConflict).JSON(model.Response{
+			Data: issued,
+			Meta: model.Meta{
+				Code:    fiber.StatusConflict,
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, usecase.ErrTicketVoid):
+		return c.Status(fiber.StatusConflict).JSON(model.Response{
+			Data: issued,
+			Meta: model.Meta{
+				Code:    fiber.StatusConflict,
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, usecase.ErrIssuedTicketNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusNotFound,
+				Message: "Ticket not found",
+			},
+		})
+	case errors.Is(err, usecase.ErrTicketInvalid):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: util.ERROR_INVALID_PARAM_MSG,
+			},
+			Errors: []*model.ErrorFieldResponse{
+				{
+					Field:      "qr_payload",
+					ErrMessage: err.Error(),
+					Tag:        "qr_payload",
+				},
+			},
+		})
+	default:
+		return h.internalError(c, err)
+	}
+}
+
+func (h *gate) SyncCheckIns(c *fiber.Ctx) error {
+	var request model.SyncCheckInRequest
+	if err := c.BodyParser(&request); err != nil {
+		h.logger.Error("Error when parsing request", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: util.ERROR_NOT_FOUND_MSG,
+			},
+		})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(util.ValidatorErrorResponse(util.ValidateStruct(err)))
+	}
+
+	results, err := h.gateUsecase.SyncCheckIns(request, c.Locals("email").(string))
+	if err != nil {
+		return h.internalError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: results,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Check-ins synced successfully",
+		},
+	})
+}
+
+func (h *gate) GetAttendance(c *fiber.Ctx) error {
+	var ticketIDs []int
+	for _, param := range strings.Split(c.Query("ticket_ids"), ",") {
+		ticketID, err := strconv.Atoi(strings.TrimSpace(param))
+		if err != nil || ticketID <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+				Meta: model.Meta{
+					Code:    fiber.StatusBadRequest,
+					Message: util.ERROR_INVALID_PARAM_MSG,
+				},
+				Errors: []*model.ErrorFieldResponse{
+					{
+						Field:      "ticket_ids",
+						ErrMessage: "ticket_ids must be a comma separated list of ticket ids",
+						Tag:        "ticket_ids",
+					},
+				},
+			})
+		}
+		ticketIDs = append(ticketIDs, ticketID)
+	}
+
+	attendances, err := h.gateUsecase.GetAttendance(ticketIDs)
+	if err != nil {
+		return h.internalError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: attendances,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Attendance retrieved successfully",
+		},
+	})
+}
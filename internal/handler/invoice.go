@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/usecase"
+	"github.com/SyamSolution/transaction-service/internal/util"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type invoice struct {
+	invoiceUsecase usecase.InvoiceExecutor
+	logger         config.Logger
+}
+
+type InvoiceHandler interface {
+	GetInvoice(c *fiber.Ctx) error
+}
+
+func NewInvoiceHandler(invoiceUsecase usecase.InvoiceExecutor, logger config.Logger) InvoiceHandler {
+	return &invoice{invoiceUsecase: invoiceUsecase, logger: logger}
+}
+
+func (h *invoice) GetInvoice(c *fiber.Ctx) error {
+	transactionID, err := c.ParamsInt("transaction_id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: util.ERROR_INVALID_PARAM_MSG,
+			},
+		})
+	}
+
+	format := c.Query("format", "json")
+	if format != "json" && format != "pdf" {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: util.ERROR_INVALID_PARAM_MSG,
+			},
+			Errors: []*model.ErrorFieldResponse{{Field: "format", ErrMessage: "format must be json or pdf", Tag: "oneof"}},
+		})
+	}
+
+	result, pdf, err := h.invoiceUsecase.GetInvoice(transactionID, c.Locals("email").(string))
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrTransactionNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(model.ResponseWithoutData{
+				Meta: model.Meta{
+					Code:    fiber.StatusNotFound,
+					Message: "Transaction not found",
+				},
+			})
+		case errors.Is(err, usecase.ErrInvoiceNotAvailable):
+			return c.Status(fiber.StatusConflict).JSON(model.ResponseWithoutData{
+				Meta: model.Meta{
+					Code:    fiber.StatusConflict,
+					Message: err.Error(),
+				},
+			})
+		}
+		h.logger.Error("Error when getting invoice", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusInternalServerError,
+				Message: util.ERROR_BASE_MSG,
+			},
+		})
+	}
+
+	if format == "pdf" {
+		c.Set(fiber.HeaderContentType, "application/pdf")
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="invoice-%s.pdf"`, result.OrderID))
+		return c.Status(fiber.StatusOK).Send(pdf)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: result,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Invoice retrieved successfully",
+		},
+	})
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/usecase"
+	"github.com/SyamSolution/transaction-service/internal/util"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+type report struct {
+	reportUsecase usecase.ReportExecutor
+	logger        config.Logger
+	cacher        config.Cacher
+	validate      *validator.Validate
+}
+
+type ReportHandler interface {
+	GetSalesReport(c *fiber.Ctx) error
+}
+
+func NewReportHandler(reportUsecase usecase.ReportExecutor, logger config.Logger, cacher config.Cacher) ReportHandler {
+	return &report{reportUsecase: reportUsecase, logger: logger, cacher: cacher, validate: validator.New()}
+}
+
+// reportCacheTTL reads REPORT_CACHE_TTL (e.g. "10m")
+func reportCacheTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("REPORT_CACHE_TTL"))
+	if err != nil || ttl <= 0 {
+		return 10 * time.Minute
+	}
+
+	return ttl
+}
+
+func (h *report) GetSalesReport(c *fiber.Ctx) error {
+	var request model.SalesReportRequest
+	if err := c.QueryParser(&request); err != nil {
+		h.logger.Error("Error when parsing request", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: util.ERROR_NOT_FOUND_MSG,
+			},
+		})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(util.ValidatorErrorResponse(util.ValidateStruct(err)))
+	}
+
+	salesReport, err := h.salesReport(c, request)
+	if err != nil {
+		if errors.Is(err, usecase.ErrReportRange) {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+				Meta: model.Meta{
+					Code:    fiber.StatusBadRequest,
+					Message: util.ERROR_INVALID_PARAM_MSG,
+				},
+				Errors: []*model.ErrorFieldResponse{
+					{
+						Field:      "to",
+						ErrMessage: err.Error(),
+						Tag:        "range",
+					},
+				},
+			})
+		}
+		h.logger.Error("Error when getting sales report", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusInternalServerError,
+				Message: util.ERROR_BASE_MSG,
+			},
+		})
+	}
+
+	if request.Format == "csv" {
+		body, err := salesReportCSV(salesReport)
+		if err != nil {
+			h.logger.Error("Error when writing sales report csv", zap.Error(err))
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
+				Meta: model.Meta{
+					Code:    fiber.StatusInternalServerError,
+					Message: util.ERROR_BASE_MSG,
+				},
+			})
+		}
+		c.Set(fiber.HeaderContentType, "text/csv")
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="sales-%s-%s-%s.csv"`,
+			salesReport.GroupBy, salesReport.From, salesReport.To))
+		return c.Status(fiber.StatusOK).Send(body)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: salesReport,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Sales report retrieved successfully",
+		},
+	})
+}
+
+// salesReport serves the report from redis when the same filter was asked recently
+func (h *report) salesReport(c *fiber.Ctx, request model.SalesReportRequest) (model.SalesReport, error) {
+	var salesReport model.SalesReport
+	key := "report-sales-" + strings.Join([]string{request.GroupBy, request.From, request.To, request.Status, request.Currency}, "-")
+
+	cached, err := h.cacher.Get(c.Context(), key)
+	if err != nil && !errors.Is(err, redis.Nil) {
+		h.logger.Error("Error when getting data from cache", zap.Error(err))
+	}
+	if cached != "" {
+		if err = json.Unmarshal([]byte(cached), &salesReport); err == nil {
+			return salesReport, nil
+		}
+		h.logger.Error("Error when unmarshalling cached sales report", zap.Error(err))
+	}
+
+	salesReport, err = h.reportUsecase.GetSalesReport(request)
+	if err != nil {
+		return salesReport, err
+	}
+
+	reportJson, _ := json.Marshal(salesReport)
+	if err = h.cacher.Set(c.Context(), key, reportJson, reportCacheTTL()); err != nil {
+		h.logger.Error("Error when setting data to cache", zap.Error(err))
+	}
+
+	return salesReport, nil
+}
+
+func salesReportCSV(salesReport model.SalesReport) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write([]string{salesReport.GroupBy, "transactions", "tickets", "gross_amount", "discount", "net_amount",
+		"currency"}); err != nil {
+		return nil, err
+	}
+
+	lines := make([]model.SalesReportLine, 0, len(salesReport.Rows)+1)
+	lines = append(append(lines, salesReport.Rows...), salesReport.Total)
+	for _, line := range lines {
+		err := writer.Write([]string{line.Key, strconv.Itoa(line.Transactions), strconv.Itoa(line.Tickets),
+			strconv.FormatFloat(line.GrossAmount, 'f', 2, 64), strconv.FormatFloat(line.Discount, 'f', 2, 64),
+			strconv.FormatFloat(line.NetAmount, 'f', 2, 64), salesReport.Currency})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
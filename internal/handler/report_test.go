@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSalesReportCSV(t *testing.T) {
+	body, err := salesReportCSV(model.SalesReport{
+		GroupBy:  model.ReportGroupContinent,
+		Currency: "USD",
+		Rows:     []model.SalesReportLine{{Key: "asia", Transactions: 2, Tickets: 3, GrossAmount: 300, Discount: 30, NetAmount: 270}},
+		Total:    model.SalesReportLine{Key: "total", Transactions: 2, Tickets: 3, GrossAmount: 300, Discount: 30, NetAmount: 270},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "continent,transactions,tickets,gross_amount,discount,net_amount,currency\n"+
+		"asia,2,3,300.00,30.00,270.00,USD\n"+
+		"total,2,3,300.00,30.00,270.00,USD\n", string(body))
+}
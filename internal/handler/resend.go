@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/usecase"
+	"github.com/SyamSolution/transaction-service/internal/util"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// resendRateLimit reads RESEND_RATE_LIMIT, how many resends of one kind a user gets per window
+func resendRateLimit() int64 {
+	limit, err := strconv.ParseInt(os.Getenv("RESEND_RATE_LIMIT"), 10, 64)
+	if err != nil || limit <= 0 {
+		return 3
+	}
+
+	return limit
+}
+
+// resendRateWindow reads RESEND_RATE_WINDOW (e.g. "1h")
+func resendRateWindow() time.Duration {
+	window, err := time.ParseDuration(os.Getenv("RESEND_RATE_WINDOW"))
+	if err != nil || window <= 0 {
+		return time.Hour
+	}
+
+	return window
+}
+
+// resendLimited counts the request against the user's limit for action. Kalau redis error, request tetap dilayani.
+func (h *transaction) resendLimited(c *fiber.Ctx, action string) bool {
+	key := fmt.Sprintf("resend-%s-%s", action, c.Locals("email").(string))
+	count, err := h.cacher.Incr(c.Context(), key, resendRateWindow())
+	if err != nil {
+		h.logger.Error("Error when counting resend requests", zap.Error(err))
+		return false
+	}
+
+	return count > resendRateLimit()
+}
+
+func (h *transaction) ResendPaymentLink(c *fiber.Ctx) error {
+	transactionID, err := c.ParamsInt("transaction_id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: util.ERROR_INVALID_PARAM_MSG,
+			},
+		})
+	}
+
+	if h.resendLimited(c, "payment-link") {
+		return c.Status(fiber.StatusTooManyRequests).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusTooManyRequests,
+				Message: "Too many requests, try again later",
+			},
+		})
+	}
+
+	link, err := h.transactionUsecase.ResendPaymentLink(transactionID, c.Locals("email").(string))
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrTransactionNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(model.ResponseWithoutData{
+				Meta: model.Meta{
+					Code:    fiber.StatusNotFound,
+					Message: "Transaction not found",
+				},
+			})
+		case errors.Is(err, usecase.ErrTransactionNotPending), errors.Is(err, usecase.ErrPaymentWindowClosed),
+			errors.Is(err, usecase.ErrPaymentLinkUnavailable):
+			return c.Status(fiber.StatusConflict).JSON(model.ResponseWithoutData{
+				Meta: model.Meta{
+					Code:    fiber.StatusConflict,
+					Message: err.Error(),
+				},
+			})
+		}
+		h.logger.Error("Error when resending payment link", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusInternalServerError,
+				Message: util.ERROR_BASE_MSG,
+			},
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: link,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Payment link sent successfully",
+		},
+	})
+}
+
+func (h *transaction) ResendTickets(c *fiber.Ctx) error {
+	transactionID, err := c.ParamsInt("transaction_id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: util.ERROR_INVALID_PARAM_MSG,
+			},
+		})
+	}
+
+	if h.resendLimited(c, "tickets") {
+		return c.Status(fiber.StatusTooManyRequests).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusTooManyRequests,
+				Message: "Too many requests, try again later",
+			},
+		})
+	}
+
+	if err := h.issuedTicketUsecase.ResendTickets(transactionID, c.Locals("email").(string)); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrTransactionNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(model.ResponseWithoutData{
+				Meta: model.Meta{
+					Code:    fiber.StatusNotFound,
+					Message: "Transaction not found",
+				},
+			})
+		case errors.Is(err, usecase.ErrTransactionNotCompleted):
+			return c.Status(fiber.StatusConflict).JSON(model.ResponseWithoutData{
+				Meta: model.Meta{
+					Code:    fiber.StatusConflict,
+					Message: err.Error(),
+				},
+			})
+		}
+		h.logger.Error("Error when resending tickets", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusInternalServerError,
+				Message: util.ERROR_BASE_MSG,
+			},
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseWithoutData{
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Tickets sent successfully",
+		},
+	})
+}
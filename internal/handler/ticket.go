@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/usecase"
+	"github.com/SyamSolution/transaction-service/internal/util"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type ticket struct {
+	issuedTicketUsecase usecase.IssuedTicketExecutor
+	logger              config.Logger
+}
+
+type TicketHandler interface {
+	GetIssuedTickets(c *fiber.Ctx) error
+	GetOwnedTickets(c *fiber.Ctx) error
+}
+
+func NewTicketHandler(issuedTicketUsecase usecase.IssuedTicketExecutor, logger config.Logger) TicketHandler {
+	return &ticket{issuedTicketUsecase: issuedTicketUsecase, logger: logger}
+}
+
+func (h *ticket) GetIssuedTickets(c *fiber.Ctx) error {
+	transactionID, err := c.ParamsInt("transaction_id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: util.ERROR_INVALID_PARAM_MSG,
+			},
+		})
+	}
+
+	tickets, err := h.issuedTicketUsecase.GetIssuedTickets(transactionID, c.Locals("email").(string))
+	if err != nil {
+		if errors.Is(err, usecase.ErrTransactionNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(model.ResponseWithoutData{
+				Meta: model.Meta{
+					Code:    fiber.StatusNotFound,
+					Message: "Transaction not found",
+				},
+			})
+		}
+		h.logger.Error("Error when getting issued tickets", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusInternalServerError,
+				Message: util.ERROR_BASE_MSG,
+			},
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: tickets,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "List ticket retrieved successfully",
+		},
+	})
+}
+
+// GetOwnedTickets lists the tickets of the logged in user, received transfers included
+func (h *ticket) GetOwnedTickets(c *fiber.Ctx) error {
+	tickets, err := h.issuedTicketUsecase.GetOwnedTickets(c.Locals("email").(string))
+	if err != nil {
+		h.logger.Error("Error when getting owned tickets", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusInternalServerError,
+				Message: util.ERROR_BASE_MSG,
+			},
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: tickets,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "List ticket retrieved successfully",
+		},
+	})
+}
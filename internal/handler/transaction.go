@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -10,33 +11,47 @@ import (
 	"time"
 
 	"github.com/SyamSolution/transaction-service/config"
-	"github.com/SyamSolution/transaction-service/helper"
 	"github.com/SyamSolution/transaction-service/internal/model"
 	"github.com/SyamSolution/transaction-service/internal/usecase"
 	"github.com/SyamSolution/transaction-service/internal/util"
+	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
-	"github.com/midtrans/midtrans-go"
-	"github.com/midtrans/midtrans-go/coreapi"
 
 	"go.uber.org/zap"
 )
 
 type transaction struct {
-	transactionUsecase usecase.TransactionExecutor
-	logger             config.Logger
-	cacher             config.Cacher
+	transactionUsecase         usecase.TransactionExecutor
+	issuedTicketUsecase        usecase.IssuedTicketExecutor
+	invoiceUsecase             usecase.InvoiceExecutor
+	paymentNotificationUsecase usecase.PaymentNotificationExecutor
+	logger                     config.Logger
+	cacher                     config.Cacher
+	validate                   *validator.Validate
 }
 
+var errUnauthorized = errors.New("unauthorized")
+
 type TransactionHandler interface {
 	CreateTransaction(c *fiber.Ctx) error
+	QuoteTransaction(c *fiber.Ctx) error
 	GetTransactionByTransactionID(c *fiber.Ctx) error
+	GetOrder(c *fiber.Ctx) error
+	GetTransactionHistory(c *fiber.Ctx) error
 	MidtransNotification(ctx *fiber.Ctx) error
 	GetListTransaction(c *fiber.Ctx) error
+	ExportTransactions(c *fiber.Ctx) error
 	MidtransTransactionCancel(c *fiber.Ctx) error
+	GetPaymentMethods(c *fiber.Ctx) error
+	ResendPaymentLink(c *fiber.Ctx) error
+	ResendTickets(c *fiber.Ctx) error
 }
 
-func NewTransactionHandler(transactionUsecase usecase.TransactionExecutor, logger config.Logger, cacher config.Cacher) TransactionHandler {
-	return &transaction{transactionUsecase: transactionUsecase, logger: logger, cacher: cacher}
+func NewTransactionHandler(transactionUsecase usecase.TransactionExecutor, issuedTicketUsecase usecase.IssuedTicketExecutor,
+	invoiceUsecase usecase.InvoiceExecutor, paymentNotificationUsecase usecase.PaymentNotificationExecutor, logger config.Logger,
+	cacher config.Cacher) TransactionHandler {
+	return &transaction{transactionUsecase: transactionUsecase, issuedTicketUsecase: issuedTicketUsecase, invoiceUsecase: invoiceUsecase,
+		paymentNotificationUsecase: paymentNotificationUsecase, logger: logger, cacher: cacher, validate: validator.New()}
 }
 
 func (h *transaction) CreateTransaction(c *fiber.Ctx) error {
@@ -51,131 +66,52 @@ func (h *transaction) CreateTransaction(c *fiber.Ctx) error {
 		})
 	}
 
-	//cek redis kalau ada
-	var user model.User
-	cacheDataUser, err := h.cacher.Get(c.Context(), "user-"+c.Locals("email").(string))
+	user, err := h.currentUser(c)
 	if err != nil {
-		h.logger.Error("Error when getting data from cache", zap.Error(err))
+		return h.userError(c, err)
 	}
-	if cacheDataUser == "" {
-		client := &http.Client{}
-		req, err := http.NewRequest("GET", os.Getenv("USER_SERVICE_URL"), nil)
-		if err != nil {
-			h.logger.Error("Error when creating new request", zap.Error(err))
-			return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
-				Meta: model.Meta{
-					Code:    fiber.StatusInternalServerError,
-					Message: util.ERROR_BASE_MSG,
-				},
-			})
-		}
-
-		req.Header.Set("Authorization", c.Get("Authorization"))
 
-		resp, err := client.Do(req)
-		if err != nil {
-			h.logger.Error("Error when sending request to user service", zap.Error(err))
-			return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
-				Meta: model.Meta{
-					Code:    fiber.StatusInternalServerError,
-					Message: util.ERROR_BASE_MSG,
-				},
-			})
-		}
-		defer resp.Body.Close()
-
-		body, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			h.logger.Error("Error when reading response body", zap.Error(err))
-			return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
-				Meta: model.Meta{
-					Code:    fiber.StatusInternalServerError,
-					Message: util.ERROR_BASE_MSG,
-				},
-			})
-		}
+	createResp, err := h.transactionUsecase.CreateTransaction(request, user)
+	if err != nil {
+		return h.orderError(c, err, "Error when creating transaction")
+	}
 
-		var respUser model.ResponseUser
-		err = json.Unmarshal(body, &respUser)
-		if err != nil {
-			h.logger.Error("Error when unmarshalling response body", zap.Error(err))
-			return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
-				Meta: model.Meta{
-					Code:    fiber.StatusInternalServerError,
-					Message: util.ERROR_BASE_MSG,
-				},
-			})
-		}
+	return c.Status(fiber.StatusCreated).JSON(model.Response{
+		Data: createResp,
+		Meta: model.Meta{
+			Code:    fiber.StatusCreated,
+			Message: "Transaction created successfully",
+		},
+	})
+}
 
-		if respUser.Data.User == (model.User{}) {
-			return c.Status(fiber.StatusUnauthorized).JSON(model.ResponseWithoutData{
-				Meta: model.Meta{
-					Code:    fiber.StatusUnauthorized,
-					Message: "Unauthorized",
-				},
-			})
-		}
-		user = respUser.Data.User
+func (h *transaction) QuoteTransaction(c *fiber.Ctx) error {
+	var request model.TransactionRequest
+	if err := c.BodyParser(&request); err != nil {
+		h.logger.Error("Error when parsing request", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: util.ERROR_NOT_FOUND_MSG,
+			},
+		})
+	}
 
-		userJson, _ := json.Marshal(respUser.Data.User)
-		err = h.cacher.Set(c.Context(), "user-"+c.Locals("email").(string), userJson, time.Hour*24*30)
-		if err != nil {
-			h.logger.Error("Error when setting data to cache", zap.Error(err))
-			return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
-				Meta: model.Meta{
-					Code:    fiber.StatusInternalServerError,
-					Message: util.ERROR_BASE_MSG,
-				},
-			})
-		}
-	} else {
-		err = json.Unmarshal([]byte(cacheDataUser), &user)
-		if err != nil {
-			h.logger.Error("Error when unmarshalling response body", zap.Error(err))
-			return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
-				Meta: model.Meta{
-					Code:    fiber.StatusInternalServerError,
-					Message: util.ERROR_BASE_MSG,
-				},
-			})
-		}
+	user, err := h.currentUser(c)
+	if err != nil {
+		return h.userError(c, err)
 	}
 
-	snapResp, discount, total, err := h.transactionUsecase.CreateTransaction(request, user)
+	quote, err := h.transactionUsecase.QuoteTransaction(request, user)
 	if err != nil {
-		if strings.Contains(err.Error(), "not eligible") {
-			return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
-				Meta: model.Meta{
-					Code:    fiber.StatusBadRequest,
-					Message: "Not eligible to buy right now",
-				},
-			})
-		} else {
-			h.logger.Error("Error when creating transaction", zap.Error(err))
-			return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
-				Meta: model.Meta{
-					Code:    fiber.StatusInternalServerError,
-					Message: util.ERROR_BASE_MSG,
-				},
-			})
-		}
+		return h.orderError(c, err, "Error when quoting transaction")
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(model.Response{
-		Data: struct {
-			Token            string  `json:"token"`
-			RedirectURL      string  `json:"redirect_url"`
-			Discount         float32 `json:"discount"`
-			TotalTransaction float32 `json:"total_transaction"`
-		}{
-			Token:            snapResp.Token,
-			RedirectURL:      snapResp.RedirectURL,
-			Discount:         discount,
-			TotalTransaction: total,
-		},
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: quote,
 		Meta: model.Meta{
-			Code:    fiber.StatusCreated,
-			Message: "Transaction created successfully",
+			Code:    fiber.StatusOK,
+			Message: "Transaction quoted successfully",
 		},
 	})
 }
@@ -216,6 +152,74 @@ func (h *transaction) GetTransactionByTransactionID(c *fiber.Ctx) error {
 	})
 }
 
+func (h *transaction) GetOrder(c *fiber.Ctx) error {
+	transaction, err := h.transactionUsecase.GetOrder(c.Params("order_id"), c.Locals("email").(string))
+	if err != nil {
+		if errors.Is(err, usecase.ErrTransactionNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(model.ResponseWithoutData{
+				Meta: model.Meta{
+					Code:    fiber.StatusNotFound,
+					Message: "Transaction not found",
+				},
+			})
+		}
+		h.logger.Error("Error when getting order", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusInternalServerError,
+				Message: util.ERROR_BASE_MSG,
+			},
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: transaction,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Transaction retrieved successfully",
+		},
+	})
+}
+
+func (h *transaction) GetTransactionHistory(c *fiber.Ctx) error {
+	transactionID, err := c.ParamsInt("transaction_id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: util.ERROR_INVALID_PARAM_MSG,
+			},
+		})
+	}
+
+	events, err := h.transactionUsecase.GetTransactionHistory(transactionID, c.Locals("email").(string))
+	if err != nil {
+		if errors.Is(err, usecase.ErrTransactionNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(model.ResponseWithoutData{
+				Meta: model.Meta{
+					Code:    fiber.StatusNotFound,
+					Message: "Transaction not found",
+				},
+			})
+		}
+		h.logger.Error("Error when getting transaction history", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusInternalServerError,
+				Message: util.ERROR_BASE_MSG,
+			},
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: events,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Transaction history retrieved successfully",
+		},
+	})
+}
+
 func (h *transaction) GetListTransaction(c *fiber.Ctx) error {
 	email := c.Locals("email").(string)
 
@@ -252,9 +256,6 @@ func (h *transaction) GetListTransaction(c *fiber.Ctx) error {
 }
 
 func (h *transaction) MidtransNotification(ctx *fiber.Ctx) error {
-	c := coreapi.Client{}
-	c.New(os.Getenv("MIDTRANS_SERVER_KEY"), midtrans.Sandbox)
-
 	// 1. Initialize empty map
 	var notificationPayload map[string]interface{}
 
@@ -276,159 +277,21 @@ func (h *transaction) MidtransNotification(ctx *fiber.Ctx) error {
 	}
 
 	// 4. Check transaction status using the orderId
-	transactionStatusResp, err := c.CheckTransaction(orderId)
+	transactionStatusResp, err := h.transactionUsecase.GetPaymentStatus(orderId)
 	if err != nil {
 		// Return an error response if the transaction status check fails
 		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.GetMessage(),
-		})
-	}
-
-	transactionOrder, errors := h.transactionUsecase.GetTransactionByOrderID(orderId)
-	if err != nil {
-		h.logger.Error("Error when getting transaction by order ID", zap.Error(err))
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": errors.Error(),
+			"error": err.Error(),
 		})
 	}
 
 	// 5. Set transaction status based on the response from the transaction status check
 	if transactionStatusResp != nil {
-		switch transactionStatusResp.TransactionStatus {
-		case "capture":
-			if transactionStatusResp.FraudStatus == "challenge" {
-				// TODO: Update your database to set the transaction status to 'challenge'
-			} else if transactionStatusResp.FraudStatus == "accept" {
-				// TODO: Update your database to set the transaction status to 'success'
-				for _, dt := range transactionOrder.DetailTransactionResponse {
-					message := model.MessageOrderTicket{
-						TicketID: dt.TicketID,
-						Order:    dt.Quantity,
-					}
-
-					jsonString, err := json.Marshal(message)
-					if err != nil {
-						fmt.Println("Error:", err)
-						h.logger.Error("Error when proceesing message", zap.Error(err))
-					
-					}
-					
-					if err = helper.ProduceMessageSqs(os.Getenv("SQS_TICKET_SUCCESS_URL"), string(jsonString), "Update Ticket Success"); err != nil {
-						h.logger.Error("Error when producing message", zap.Error(err))
-					}
-				}
-
-				err := h.transactionUsecase.UpdateTransactionStatus(orderId, "completed", transactionOrder.Email)
-				if err != nil {
-					h.logger.Error("Error when updating transaction status", zap.Error(err))
-					return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-						"error": err.Error(),
-					})
-				}
-			}
-		case "settlement":
-			// TODO: Update your database to set the transaction status to 'success'
-			ticketEvent, err := helper.GetTicketEventByTicketID(transactionOrder.DetailTransactionResponse[0].TicketID)
-			if err != nil {
-				h.logger.Error("Error when getting ticket event by ticket ID", zap.Error(err))
-				return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-					"error": err.Error(),
-				})
-			}
-
-			emailPDF := model.EmailPDFMessage{
-				Email:          transactionOrder.Email,
-				OrderId:        orderId,
-				EventName:      ticketEvent.EventName,
-				Price:          transactionOrder.TotalAmount,
-				NumberOfTicket: transactionOrder.TotalTicket,
-				EventDate:      ticketEvent.Date.Format("2006-01-02"),
-				EventTime:      ticketEvent.Date.Format("15:04:05"),
-				Venue:          ticketEvent.CountryPlace,
-				CustomerName:   transactionOrder.FullName,
-				PurchaseDate:   transactionOrder.CreatedAt.Format("2006-01-02 15:04:05"),
-			}
-			for _, dt := range transactionOrder.DetailTransactionResponse {
-				emailPDF.DetailTickets = append(emailPDF.DetailTickets, model.DetailTicket{
-					TicketType:  dt.TicketType,
-					TotalTicket: dt.Quantity,
-				})
-			}
-			jsonString, err := json.Marshal(emailPDF)
-			if err != nil {
-				fmt.Println("Error:", err)
-				h.logger.Error("Error when proceesing message", zap.Error(err))
-			
-			}
-			
-			if err = helper.ProduceMessageSqs(os.Getenv("SQS_MAIL_URL"), string(jsonString), "Send Email PDF Success"); err != nil {
-				h.logger.Error("Error when producing message", zap.Error(err))
-			}
-
-			for _, dt := range transactionOrder.DetailTransactionResponse {
-				message := model.MessageOrderTicket{
-					TicketID: dt.TicketID,
-					Order:    dt.Quantity,
-				}
-
-				jsonString, err := json.Marshal(message)
-				if err != nil {
-					fmt.Println("Error:", err)
-					h.logger.Error("Error when proceesing message", zap.Error(err))
-				
-				}
-				
-				if err = helper.ProduceMessageSqs(os.Getenv("SQS_TICKET_SUCCESS_URL"), string(jsonString), "Update Ticket Success"); err != nil {
-					h.logger.Error("Error when producing message", zap.Error(err))
-				}
-			}
-
-			err = h.transactionUsecase.UpdateTransactionStatus(orderId, "completed", transactionOrder.Email)
-			if err != nil {
-				h.logger.Error("Error when updating transaction status", zap.Error(err))
-				return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-					"error": err.Error(),
-				})
-			}
-		case "deny":
-			// TODO: Handle 'deny' appropriately
-		case "cancel", "expire":
-			// TODO: Update your database to set the transaction status to 'failure'
-			// kirim message SQS ke ticket-management-service balikin ticket
-			for _, dt := range transactionOrder.DetailTransactionResponse {
-				message := model.MessageOrderTicket{
-					TicketID: dt.TicketID,
-					Order:    dt.Quantity,
-				}
-
-				jsonString, err := json.Marshal(message)
-				if err != nil {
-					fmt.Println("Error:", err)
-					h.logger.Error("Error when proceesing message", zap.Error(err))
-				
-				}
-				
-				if err = helper.ProduceMessageSqs(os.Getenv("SQS_TICKET_FAILED_URL"), string(jsonString), "Update Ticket Failed"); err != nil {
-					h.logger.Error("Error when producing message", zap.Error(err))
-				}
-			}
-
-			err := h.transactionUsecase.UpdateTransactionStatus(orderId, "cancelled", transactionOrder.Email)
-			if err != nil {
-				h.logger.Error("Error when updating transaction status", zap.Error(err))
-				return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-					"error": err.Error(),
-				})
-			}
-		case "pending":
-			// TODO: Update your database to set the transaction status to 'pending'
-			err := h.transactionUsecase.UpdateTransactionStatus(orderId, "pending", transactionOrder.Email)
-			if err != nil {
-				h.logger.Error("Error when updating transaction status", zap.Error(err))
-				return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-					"error": err.Error(),
-				})
-			}
+		event := model.TransactionEvent{Actor: model.TransactionActorWebhook}
+		if err := h.paymentNotificationUsecase.ApplyPaymentStatus(orderId, transactionStatusResp, event); err != nil {
+			return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": err.Error(),
+			})
 		}
 	}
 
@@ -439,25 +302,243 @@ func (h *transaction) MidtransNotification(ctx *fiber.Ctx) error {
 }
 
 func (h *transaction) MidtransTransactionCancel(c *fiber.Ctx) error {
+	email := c.Locals("email").(string)
 	orderId := c.Params("order_id")
 
-	var core = coreapi.Client{}
-	core.New(os.Getenv("MIDTRANS_SERVER_KEY"), midtrans.Sandbox)
-	_, err := core.CancelTransaction(orderId)
+	err := h.transactionUsecase.CancelTransaction(orderId, email)
 	if err != nil {
-		h.logger.Error("Error when cancelling transaction", zap.Error(err))
+		switch {
+		case errors.Is(err, usecase.ErrTransactionNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(model.ResponseWithoutData{
+				Meta: model.Meta{
+					Code:    fiber.StatusNotFound,
+					Message: "Transaction not found",
+				},
+			})
+		case errors.Is(err, usecase.ErrTransactionAlreadyPaid):
+			return c.Status(fiber.StatusConflict).JSON(model.ResponseWithoutData{
+				Meta: model.Meta{
+					Code:    fiber.StatusConflict,
+					Message: "Transaction has already been paid and can no longer be cancelled",
+				},
+			})
+		case errors.Is(err, usecase.ErrTransactionNotPending):
+			return c.Status(fiber.StatusConflict).JSON(model.ResponseWithoutData{
+				Meta: model.Meta{
+					Code:    fiber.StatusConflict,
+					Message: "Only pending transaction can be cancelled",
+				},
+			})
+		default:
+			h.logger.Error("Error when cancelling transaction", zap.Error(err))
+			return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
+				Meta: model.Meta{
+					Code:    fiber.StatusInternalServerError,
+					Message: "Error when cancelling transaction",
+				},
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseWithoutData{
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Transaction cancelled successfully",
+		},
+	})
+}
+
+func (h *transaction) GetPaymentMethods(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: h.transactionUsecase.GetPaymentMethods(),
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Payment methods retrieved successfully",
+		},
+	})
+}
+
+// orderError maps the errors of the pricing pipeline, shared by create and quote
+func (h *transaction) orderError(c *fiber.Ctx, err error, message string) error {
+	var lineErrors usecase.LineItemErrors
+	if strings.Contains(err.Error(), "not eligible") {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: "Not eligible to buy right now",
+			},
+		})
+	} else if errors.Is(err, usecase.ErrInvalidPaymentMethod) || errors.Is(err, usecase.ErrDirectChargeUnsupported) {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: util.ERROR_INVALID_PARAM_MSG,
+			},
+			Errors: []*model.ErrorFieldResponse{
+				{
+					Field:      "payment_method",
+					ErrMessage: err.Error(),
+					Tag:        "payment_method",
+				},
+			},
+		})
+	} else if errors.As(err, &lineErrors) {
+		errorFields := make([]*model.ErrorFieldResponse, 0, len(lineErrors))
+		for _, lineErr := range lineErrors {
+			errorFields = append(errorFields, &model.ErrorFieldResponse{
+				Field:      fmt.Sprintf("detail_ticket[%d].%s", lineErr.Line, lineErr.Field),
+				ErrMessage: lineErr.Message,
+				Tag:        lineErr.Tag,
+			})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: util.ERROR_INVALID_PARAM_MSG,
+			},
+			Errors: errorFields,
+		})
+	} else if errors.Is(err, usecase.ErrTotalTicketMismatch) {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: util.ERROR_INVALID_PARAM_MSG,
+			},
+			Errors: []*model.ErrorFieldResponse{
+				{
+					Field:      "total_ticket",
+					ErrMessage: err.Error(),
+					Tag:        "total_ticket",
+				},
+			},
+		})
+	} else if errors.Is(err, usecase.ErrInvalidSplitCount) || errors.Is(err, usecase.ErrSplitDirectCharge) {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: util.ERROR_INVALID_PARAM_MSG,
+			},
+			Errors: []*model.ErrorFieldResponse{
+				{
+					Field:      "split_count",
+					ErrMessage: err.Error(),
+					Tag:        "split_count",
+				},
+			},
+		})
+	} else if errors.Is(err, usecase.ErrQuoteInvalid) || errors.Is(err, usecase.ErrQuoteExpired) || errors.Is(err, usecase.ErrQuoteMismatch) {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: util.ERROR_INVALID_PARAM_MSG,
+			},
+			Errors: []*model.ErrorFieldResponse{
+				{
+					Field:      "quote_id",
+					ErrMessage: err.Error(),
+					Tag:        "quote_id",
+				},
+			},
+		})
+	} else if isVoucherError(err) {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: util.ERROR_INVALID_PARAM_MSG,
+			},
+			Errors: []*model.ErrorFieldResponse{
+				{
+					Field:      "voucher_code",
+					ErrMessage: err.Error(),
+					Tag:        "voucher_code",
+				},
+			},
+		})
+	} else {
+		h.logger.Error(message, zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
 			Meta: model.Meta{
 				Code:    fiber.StatusInternalServerError,
-				Message: "Error when cancelling transaction",
+				Message: util.ERROR_BASE_MSG,
 			},
 		})
 	}
+}
 
-	return c.Status(fiber.StatusOK).JSON(model.ResponseWithoutData{
+// currentUser loads the profile of the logged in user from redis, or from user-service on a miss
+func (h *transaction) currentUser(c *fiber.Ctx) (model.User, error) {
+	//cek redis kalau ada
+	var user model.User
+	cacheDataUser, err := h.cacher.Get(c.Context(), "user-"+c.Locals("email").(string))
+	if err != nil {
+		h.logger.Error("Error when getting data from cache", zap.Error(err))
+	}
+	if cacheDataUser == "" {
+		client := &http.Client{}
+		req, err := http.NewRequest("GET", os.Getenv("USER_SERVICE_URL"), nil)
+		if err != nil {
+			h.logger.Error("Error when creating new request", zap.Error(err))
+			return user, err
+		}
+
+		req.Header.Set("Authorization", c.Get("Authorization"))
+
+		resp, err := client.Do(req)
+		if err != nil {
+			h.logger.Error("Error when sending request to user service", zap.Error(err))
+			return user, err
+		}
+		defer resp.Body.Close()
+
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			h.logger.Error("Error when reading response body", zap.Error(err))
+			return user, err
+		}
+
+		var respUser model.ResponseUser
+		err = json.Unmarshal(body, &respUser)
+		if err != nil {
+			h.logger.Error("Error when unmarshalling response body", zap.Error(err))
+			return user, err
+		}
+
+		if respUser.Data.User == (model.User{}) {
+			return user, errUnauthorized
+		}
+		user = respUser.Data.User
+
+		userJson, _ := json.Marshal(respUser.Data.User)
+		err = h.cacher.Set(c.Context(), "user-"+c.Locals("email").(string), userJson, time.Hour*24*30)
+		if err != nil {
+			h.logger.Error("Error when setting data to cache", zap.Error(err))
+			return user, err
+		}
+	} else {
+		err = json.Unmarshal([]byte(cacheDataUser), &user)
+		if err != nil {
+			h.logger.Error("Error when unmarshalling response body", zap.Error(err))
+			return user, err
+		}
+	}
+
+	return user, nil
+}
+
+func (h *transaction) userError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, errUnauthorized) {
+		return c.Status(fiber.StatusUnauthorized).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusUnauthorized,
+				Message: "Unauthorized",
+			},
+		})
+	}
+
+	return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
 		Meta: model.Meta{
-			Code:    fiber.StatusOK,
-			Message: "Transaction cancelled successfully",
+			Code:    fiber.StatusInternalServerError,
+			Message: util.ERROR_BASE_MSG,
 		},
 	})
 }
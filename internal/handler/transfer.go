@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/usecase"
+	"github.com/SyamSolution/transaction-service/internal/util"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type transfer struct {
+	transferUsecase usecase.TransferExecutor
+	logger          config.Logger
+	validate        *validator.Validate
+}
+
+type TransferHandler interface {
+	CreateTransfer(c *fiber.Ctx) error
+	AcceptTransfer(c *fiber.Ctx) error
+	CancelTransfer(c *fiber.Ctx) error
+	GetListTransfer(c *fiber.Ctx) error
+}
+
+func NewTransferHandler(transferUsecase usecase.TransferExecutor, logger config.Logger) TransferHandler {
+	return &transfer{transferUsecase: transferUsecase, logger: logger, validate: validator.New()}
+}
+
+func (h *transfer) transferError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrTransferLineNotFound), errors.Is(err, usecase.ErrTransferNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusNotFound,
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, usecase.ErrTransferNotAllowed), errors.Is(err, usecase.ErrTransferClosed):
+		return c.Status(fiber.StatusConflict).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusConflict,
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, usecase.ErrTransferToSelf):
+		return h.fieldError(c, "recipient_email", err)
+	case errors.Is(err, usecase.ErrTransferQuantity):
+		return h.fieldError(c, "quantity", err)
+	case errors.Is(err, usecase.ErrTransferInvalid), errors.Is(err, usecase.ErrTransferExpired):
+		return h.fieldError(c, "token", err)
+	default:
+		h.logger.Error("Error when transferring ticket", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusInternalServerError,
+				Message: util.ERROR_BASE_MSG,
+			},
+		})
+	}
+}
+
+func (h *transfer) fieldError(c *fiber.Ctx, field string, err error) error {
+	return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+		Meta: model.Meta{
+			Code:    fiber.StatusBadRequest,
+			Message: util.ERROR_INVALID_PARAM_MSG,
+		},
+		Errors: []*model.ErrorFieldResponse{
+			{
+				Field:      field,
+				ErrMessage: err.Error(),
+				Tag:        field,
+			},
+		},
+	})
+}
+
+func (h *transfer) CreateTransfer(c *fiber.Ctx) error {
+	var request model.TransferRequest
+	if err := c.BodyParser(&request); err != nil {
+		h.logger.Error("Error when parsing request", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: util.ERROR_NOT_FOUND_MSG,
+			},
+		})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(util.ValidatorErrorResponse(util.ValidateStruct(err)))
+	}
+
+	response, err := h.transferUsecase.CreateTransfer(request, c.Locals("email").(string))
+	if err != nil {
+		return h.transferError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.Response{
+		Data: response,
+		Meta: model.Meta{
+			Code:    fiber.StatusCreated,
+			Message: "Ticket transfer created successfully",
+		},
+	})
+}
+
+func (h *transfer) AcceptTransfer(c *fiber.Ctx) error {
+	var request model.AcceptTransferRequest
+	if err := c.BodyParser(&request); err != nil {
+		h.logger.Error("Error when parsing request", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: util.ERROR_NOT_FOUND_MSG,
+			},
+		})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(util.ValidatorErrorResponse(util.ValidateStruct(err)))
+	}
+
+	ownership, err := h.transferUsecase.AcceptTransfer(request.Token, c.Locals("email").(string))
+	if err != nil {
+		return h.transferError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: ownership,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Ticket transfer accepted successfully",
+		},
+	})
+}
+
+func (h *transfer) CancelTransfer(c *fiber.Ctx) error {
+	transferID, err := strconv.Atoi(c.Params("transfer_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: util.ERROR_INVALID_PARAM_MSG,
+			},
+		})
+	}
+
+	if err := h.transferUsecase.CancelTransfer(transferID, c.Locals("email").(string)); err != nil {
+		return h.transferError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseWithoutData{
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Ticket transfer cancelled successfully",
+		},
+	})
+}
+
+func (h *transfer) GetListTransfer(c *fiber.Ctx) error {
+	transfers, err := h.transferUsecase.GetListTransfer(c.Locals("email").(string))
+	if err != nil {
+		return h.transferError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: transfers,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "List ticket transfer retrieved successfully",
+		},
+	})
+}
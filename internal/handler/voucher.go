@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/usecase"
+	"github.com/SyamSolution/transaction-service/internal/util"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type voucher struct {
+	voucherUsecase usecase.VoucherExecutor
+	logger         config.Logger
+	validate       *validator.Validate
+}
+
+type VoucherHandler interface {
+	CreateVoucher(c *fiber.Ctx) error
+	UpdateVoucher(c *fiber.Ctx) error
+	GetVoucherByCode(c *fiber.Ctx) error
+	GetListVoucher(c *fiber.Ctx) error
+	DeactivateVoucher(c *fiber.Ctx) error
+}
+
+func NewVoucherHandler(voucherUsecase usecase.VoucherExecutor, logger config.Logger) VoucherHandler {
+	return &voucher{voucherUsecase: voucherUsecase, logger: logger, validate: validator.New()}
+}
+
+func isVoucherError(err error) bool {
+	return errors.Is(err, usecase.ErrVoucherNotFound) || errors.Is(err, usecase.ErrVoucherInactive) ||
+		errors.Is(err, usecase.ErrVoucherNotApplicable) || errors.Is(err, usecase.ErrVoucherMinSpend) ||
+		errors.Is(err, usecase.ErrVoucherExhausted) || errors.Is(err, usecase.ErrVoucherUserLimit)
+}
+
+// parseRequest writes the 400 response itself, ok is false when the handler should stop
+func (h *voucher) parseRequest(c *fiber.Ctx) (request model.VoucherRequest, ok bool, err error) {
+	if err := c.BodyParser(&request); err != nil {
+		h.logger.Error("Error when parsing request", zap.Error(err))
+		return request, false, c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: util.ERROR_NOT_FOUND_MSG,
+			},
+		})
+	}
+	if c.Params("code") != "" {
+		request.Code = c.Params("code")
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return request, false, c.Status(fiber.StatusBadRequest).JSON(util.ValidatorErrorResponse(util.ValidateStruct(err)))
+	}
+
+	return request, true, nil
+}
+
+func (h *voucher) voucherError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrVoucherNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusNotFound,
+				Message: "Voucher not found",
+			},
+		})
+	case errors.Is(err, usecase.ErrVoucherCodeTaken):
+		return c.Status(fiber.StatusConflict).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusConflict,
+				Message: "Voucher code already exists",
+			},
+		})
+	case errors.Is(err, usecase.ErrInvalidVoucher):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: err.Error(),
+			},
+		})
+	default:
+		h.logger.Error("Error when managing voucher", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusInternalServerError,
+				Message: util.ERROR_BASE_MSG,
+			},
+		})
+	}
+}
+
+func (h *voucher) CreateVoucher(c *fiber.Ctx) error {
+	request, ok, err := h.parseRequest(c)
+	if !ok {
+		return err
+	}
+
+	voucher, err := h.voucherUsecase.CreateVoucher(request)
+	if err != nil {
+		return h.voucherError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.Response{
+		Data: voucher,
+		Meta: model.Meta{
+			Code:    fiber.StatusCreated,
+			Message: "Voucher created successfully",
+		},
+	})
+}
+
+func (h *voucher) UpdateVoucher(c *fiber.Ctx) error {
+	request, ok, err := h.parseRequest(c)
+	if !ok {
+		return err
+	}
+
+	voucher, err := h.voucherUsecase.UpdateVoucher(c.Params("code"), request)
+	if err != nil {
+		return h.voucherError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: voucher,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Voucher updated successfully",
+		},
+	})
+}
+
+func (h *voucher) GetVoucherByCode(c *fiber.Ctx) error {
+	voucher, err := h.voucherUsecase.GetVoucherByCode(c.Params("code"))
+	if err != nil {
+		return h.voucherError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: voucher,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Voucher retrieved successfully",
+		},
+	})
+}
+
+func (h *voucher) GetListVoucher(c *fiber.Ctx) error {
+	vouchers, err := h.voucherUsecase.GetListVoucher()
+	if err != nil {
+		return h.voucherError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: vouchers,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "List voucher retrieved successfully",
+		},
+	})
+}
+
+func (h *voucher) DeactivateVoucher(c *fiber.Ctx) error {
+	if err := h.voucherUsecase.DeactivateVoucher(c.Params("code")); err != nil {
+		return h.voucherError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseWithoutData{
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Voucher deactivated successfully",
+		},
+	})
+}
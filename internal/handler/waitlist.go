@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/usecase"
+	"github.com/SyamSolution/transaction-service/internal/util"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type waitlist struct {
+	waitlistUsecase usecase.WaitlistExecutor
+	logger          config.Logger
+	validate        *validator.Validate
+}
+
+type WaitlistHandler interface {
+	JoinWaitlist(c *fiber.Ctx) error
+	GetListWaitlist(c *fiber.Ctx) error
+	LeaveWaitlist(c *fiber.Ctx) error
+}
+
+func NewWaitlistHandler(waitlistUsecase usecase.WaitlistExecutor, logger config.Logger) WaitlistHandler {
+	return &waitlist{waitlistUsecase: waitlistUsecase, logger: logger, validate: validator.New()}
+}
+
+func (h *waitlist) waitlistError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrWaitlistNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusNotFound,
+				Message: "Waitlist entry not found",
+			},
+		})
+	case errors.Is(err, usecase.ErrWaitlistAlreadyJoined):
+		return c.Status(fiber.StatusConflict).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusConflict,
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, usecase.ErrWaitlistTicketNotFound), errors.Is(err, usecase.ErrTicketAvailable):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: util.ERROR_INVALID_PARAM_MSG,
+			},
+			Errors: []*model.ErrorFieldResponse{
+				{
+					Field:      "ticket_id",
+					ErrMessage: err.Error(),
+					Tag:        "ticket_id",
+				},
+			},
+		})
+	default:
+		h.logger.Error("Error when managing waitlist", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusInternalServerError,
+				Message: util.ERROR_BASE_MSG,
+			},
+		})
+	}
+}
+
+func (h *waitlist) JoinWaitlist(c *fiber.Ctx) error {
+	var request model.WaitlistRequest
+	if err := c.BodyParser(&request); err != nil {
+		h.logger.Error("Error when parsing request", zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: util.ERROR_NOT_FOUND_MSG,
+			},
+		})
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(util.ValidatorErrorResponse(util.ValidateStruct(err)))
+	}
+
+	waitlist, err := h.waitlistUsecase.JoinWaitlist(request, c.Locals("email").(string))
+	if err != nil {
+		return h.waitlistError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.Response{
+		Data: waitlist,
+		Meta: model.Meta{
+			Code:    fiber.StatusCreated,
+			Message: "Joined waitlist successfully",
+		},
+	})
+}
+
+func (h *waitlist) GetListWaitlist(c *fiber.Ctx) error {
+	waitlists, err := h.waitlistUsecase.GetListWaitlist(c.Locals("email").(string))
+	if err != nil {
+		return h.waitlistError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.Response{
+		Data: waitlists,
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "List waitlist retrieved successfully",
+		},
+	})
+}
+
+func (h *waitlist) LeaveWaitlist(c *fiber.Ctx) error {
+	waitlistID, err := strconv.Atoi(c.Params("waitlist_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseWithoutData{
+			Meta: model.Meta{
+				Code:    fiber.StatusBadRequest,
+				Message: util.ERROR_INVALID_PARAM_MSG,
+			},
+		})
+	}
+
+	if err := h.waitlistUsecase.LeaveWaitlist(waitlistID, c.Locals("email").(string)); err != nil {
+		return h.waitlistError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseWithoutData{
+		Meta: model.Meta{
+			Code:    fiber.StatusOK,
+			Message: "Left waitlist successfully",
+		},
+	})
+}
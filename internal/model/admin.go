@@ -0,0 +1,67 @@
+package model
+
+import "time"
+
+// messages an admin can send again for a transaction
+const (
+	AdminResendConfirmation = "confirmation"
+	AdminResendTickets      = "tickets"
+)
+
+type AdminTransactionSearchRequest struct {
+	OrderID      string  `query:"order_id"`
+	Email        string  `query:"email"`
+	MobileNumber string  `query:"mobile_number"`
+	Status       string  `query:"status" validate:"omitempty,oneof=pending completed cancelled"`
+	Continent    string  `query:"continent"`
+	From         string  `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To           string  `query:"to" validate:"omitempty,datetime=2006-01-02"`
+	MinAmount    float64 `query:"min_amount" validate:"gte=0"`
+	MaxAmount    float64 `query:"max_amount" validate:"gte=0"`
+	Page         int     `query:"page" validate:"gte=0"`
+	Limit        int     `query:"limit" validate:"gte=0,lte=100"`
+}
+
+// AdminTransactionFilter is the parsed search, zero values are not filtered on
+type AdminTransactionFilter struct {
+	OrderID      string
+	Email        string
+	MobileNumber string
+	Status       string
+	Continent    string
+	From         time.Time
+	To           time.Time
+	MinAmount    float64
+	MaxAmount    float64
+	Offset       int
+	Limit        int
+}
+
+type AdminTransactionList struct {
+	Transactions []Transaction `json:"transactions"`
+	Page         int           `json:"page"`
+	Limit        int           `json:"limit"`
+	Total        int           `json:"total"`
+}
+
+type AdminTransactionDetail struct {
+	Transaction   Transaction         `json:"transaction"`
+	Lines         []DetailTransaction `json:"lines"`
+	PaymentShares []PaymentShare      `json:"payment_shares,omitempty"`
+	Payment       *Payment            `json:"payment,omitempty"`
+	Events        []TransactionEvent  `json:"events"`
+}
+
+type AdminResyncResult struct {
+	OrderID string `json:"order_id"`
+	// GatewayStatus is midtrans' transaction_status, not_found when the order was never charged
+	GatewayStatus  string `json:"gateway_status"`
+	PreviousStatus string `json:"previous_status"`
+	CurrentStatus  string `json:"current_status"`
+	// Applied lists the midtrans order ids whose status was applied, the order or the shares of a split order
+	Applied []string `json:"applied"`
+}
+
+type AdminResendRequest struct {
+	Message string `json:"message" validate:"required,oneof=confirmation tickets"`
+}
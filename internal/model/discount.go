@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type Discount struct {
 	IsContinentSoldOut bool    `json:"isContinentSoldOut"`
 	IsContinentDiff    bool    `json:"isContinentDiff"`
@@ -10,3 +12,27 @@ type ResponseDiscount struct {
 	Data float32 `json:"data"`
 	Meta Meta    `json:"meta"`
 }
+
+// DiscountInput is everything a discount policy may look at for one order
+type DiscountInput struct {
+	Email string
+	// Continents are the distinct continents of the order lines
+	Continents         []string
+	SoldOutContinents  []string
+	PreviousContinents []string
+	TotalTicket        int
+	Subtotal           float32
+	Now                time.Time
+}
+
+type DiscountTrace struct {
+	Rule    string  `json:"rule"`
+	Matched bool    `json:"matched"`
+	Percent float32 `json:"percent"`
+	Reason  string  `json:"reason"`
+}
+
+type DiscountResult struct {
+	Percent float32         `json:"percent"`
+	Trace   []DiscountTrace `json:"trace"`
+}
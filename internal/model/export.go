@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+type TransactionExportRequest struct {
+	Format string `query:"format" validate:"required,oneof=csv pdf"`
+	From   string `query:"from" validate:"required,datetime=2006-01-02"`
+	To     string `query:"to" validate:"required,datetime=2006-01-02"`
+}
+
+// TransactionExport is one order of the purchase history with its lines
+type TransactionExport struct {
+	TransactionID   int
+	OrderID         string
+	TransactionDate time.Time
+	PaymentMethod   string
+	PaymentStatus   string
+	Discount        float32
+	VoucherCode     string
+	VoucherDiscount float32
+	TotalAmount     float32
+	TotalIDR        int64
+	Lines           []TransactionExportLine
+}
+
+type TransactionExportLine struct {
+	TicketType  string
+	Continent   string
+	CountryName string
+	City        string
+	Quantity    int
+	Price       float32
+	NetAmount   float32
+}
@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+type InvoiceSeller struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	TaxID   string `json:"tax_id"`
+	Email   string `json:"email"`
+}
+
+type InvoiceBuyer struct {
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	MobileNumber string `json:"mobile_number"`
+}
+
+// InvoiceLine amounts are in IDR, discount and voucher lines are negative
+type InvoiceLine struct {
+	Description string `json:"description"`
+	Quantity    int64  `json:"quantity"`
+	UnitPrice   int64  `json:"unit_price"`
+	Amount      int64  `json:"amount"`
+}
+
+type InvoiceTax struct {
+	Name   string  `json:"name"`
+	Rate   float64 `json:"rate"`
+	Amount int64   `json:"amount"`
+}
+
+// Invoice is the document as stored, prices include tax so Taxes break Total down instead of adding to it
+type Invoice struct {
+	InvoiceNumber string        `json:"invoice_number"`
+	TransactionID int           `json:"transaction_id"`
+	OrderID       string        `json:"order_id"`
+	IssuedAt      time.Time     `json:"issued_at"`
+	PaymentMethod string        `json:"payment_method"`
+	Currency      string        `json:"currency"`
+	Seller        InvoiceSeller `json:"seller"`
+	Buyer         InvoiceBuyer  `json:"buyer"`
+	Lines         []InvoiceLine `json:"lines"`
+	Subtotal      int64         `json:"subtotal"`
+	Discount      int64         `json:"discount"`
+	TaxBase       int64         `json:"tax_base"`
+	Taxes         []InvoiceTax  `json:"taxes"`
+	Total         int64         `json:"total"`
+}
+
+// InvoiceRecord is a row of the invoice table, Data and PDF are empty until the document is rendered
+type InvoiceRecord struct {
+	InvoiceID      int
+	InvoiceNumber  string
+	InvoiceYear    int
+	SequenceNumber int
+	TransactionID  int
+	OrderID        string
+	IssuedAt       time.Time
+	Data           []byte
+	PDF            []byte
+}
@@ -0,0 +1,90 @@
+package model
+
+import "time"
+
+const (
+	IssuedTicketIssued = "issued"
+	IssuedTicketUsed   = "used"
+	// IssuedTicketVoid is a ticket of an order that was cancelled after it was issued
+	IssuedTicketVoid = "void"
+)
+
+// IssuedTicket is one admission of an order line, UnitNumber counts from 1 up to the line quantity.
+// OwnerEmail is the buyer until the unit is transferred. QRPayload is signed from the code and is not stored.
+type IssuedTicket struct {
+	IssuedTicketID      int        `json:"issued_ticket_id"`
+	DetailTransactionID int        `json:"detail_transaction_id"`
+	OrderID             string     `json:"order_id"`
+	TicketID            int        `json:"ticket_id"`
+	UnitNumber          int        `json:"unit_number"`
+	Code                string     `json:"code"`
+	TicketType          string     `json:"ticket_type"`
+	HolderName          string     `json:"holder_name"`
+	OwnerEmail          string     `json:"owner_email"`
+	Status              string     `json:"status"`
+	UsedAt              *time.Time `json:"used_at"`
+	Gate                string     `json:"gate"`
+	CheckedInBy         string     `json:"checked_in_by"`
+	QRPayload           string     `json:"qr_payload"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+const (
+	CheckInCheckedIn = "checked_in"
+	CheckInDuplicate = "duplicate"
+	CheckInConflict  = "conflict"
+	CheckInNotFound  = "not_found"
+	CheckInInvalid   = "invalid"
+	CheckInVoid      = "void"
+)
+
+// CheckInRequest identifies the ticket by its code, typed in by staff, or by the scanned QR payload
+type CheckInRequest struct {
+	Code      string `json:"code" validate:"required_without=QRPayload"`
+	QRPayload string `json:"qr_payload" validate:"required_without=Code"`
+	Gate      string `json:"gate" validate:"max=50"`
+}
+
+// OfflineScan is a check-in done by a handheld scanner without connection, synced later
+type OfflineScan struct {
+	Code      string    `json:"code" validate:"required_without=QRPayload"`
+	QRPayload string    `json:"qr_payload" validate:"required_without=Code"`
+	ScannedAt time.Time `json:"scanned_at"`
+}
+
+type SyncCheckInRequest struct {
+	Gate  string        `json:"gate" validate:"max=50"`
+	Scans []OfflineScan `json:"scans" validate:"required,min=1,max=500,dive"`
+}
+
+// CheckInResult is the outcome of one synced scan. A conflict carries the check-in already recorded.
+type CheckInResult struct {
+	Index  int           `json:"index"`
+	Code   string        `json:"code"`
+	Status string        `json:"status"`
+	Ticket *IssuedTicket `json:"ticket,omitempty"`
+}
+
+type GateTicketResponse struct {
+	Ticket    IssuedTicket `json:"ticket"`
+	EventName string       `json:"event_name"`
+	EventDate time.Time    `json:"event_date"`
+	Venue     string       `json:"venue"`
+}
+
+type TicketAttendance struct {
+	TicketID   int    `json:"ticket_id"`
+	TicketType string `json:"ticket_type"`
+	Issued     int    `json:"issued"`
+	CheckedIn  int    `json:"checked_in"`
+}
+
+type EventAttendance struct {
+	EventName string             `json:"event_name"`
+	EventDate time.Time          `json:"event_date"`
+	Venue     string             `json:"venue"`
+	Issued    int                `json:"issued"`
+	CheckedIn int                `json:"checked_in"`
+	Tickets   []TicketAttendance `json:"tickets"`
+}
@@ -1,13 +1,16 @@
 package model
 
+import "time"
+
 type Message struct {
-	OrderID      string  `json:"order_id"`
-	Email        string  `json:"email"`
-	URL          string  `json:"url"`
-	Name         string  `json:"name"`
-	Date         string  `json:"date"`
-	DeadlineDate string  `json:"deadline_date"`
-	Total        float32 `json:"total"`
+	OrderID      string                 `json:"order_id"`
+	Email        string                 `json:"email"`
+	URL          string                 `json:"url"`
+	Name         string                 `json:"name"`
+	Date         string                 `json:"date"`
+	DeadlineDate string                 `json:"deadline_date"`
+	Total        float32                `json:"total"`
+	Shares       []PaymentShareResponse `json:"shares,omitempty"`
 }
 
 type CompleteTransactionMessage struct {
@@ -18,3 +21,24 @@ type MessageOrderTicket struct {
 	TicketID int `json:"ticket_id"`
 	Order    int `json:"order"`
 }
+
+type MessageWaitlistHold struct {
+	WaitlistID int       `json:"waitlist_id"`
+	Email      string    `json:"email"`
+	TicketID   int       `json:"ticket_id"`
+	Continent  string    `json:"continent"`
+	Quantity   int       `json:"quantity"`
+	HoldUntil  time.Time `json:"hold_until"`
+}
+
+type MessageTicketTransfer struct {
+	TransferID int       `json:"transfer_id"`
+	OrderID    string    `json:"order_id"`
+	TicketID   int       `json:"ticket_id"`
+	Quantity   int       `json:"quantity"`
+	FromEmail  string    `json:"from_email"`
+	ToEmail    string    `json:"to_email"`
+	Status     string    `json:"status"`
+	Token      string    `json:"token,omitempty"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
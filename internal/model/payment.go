@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// Payment is how the customer pays an order that is not split: the snap link, or the VA/QR of a direct charge.
+// GatewayTransactionID is the midtrans transaction_id, known once a payment channel has been chosen. LinkNumber counts
+// the snap links made again for the order, the n-th one is paid under the midtrans order_id <order_id>-R<n>.
+type Payment struct {
+	PaymentID            int        `json:"payment_id"`
+	TransactionID        int        `json:"transaction_id"`
+	OrderID              string     `json:"order_id"`
+	Token                string     `json:"token"`
+	RedirectURL          string     `json:"redirect_url"`
+	VANumber             string     `json:"va_number"`
+	QRString             string     `json:"qr_string"`
+	GatewayTransactionID string     `json:"gateway_transaction_id"`
+	LinkNumber           int        `json:"link_number"`
+	ExpiresAt            *time.Time `json:"expires_at"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
+
+type PaymentResponse struct {
+	Token                string     `json:"token,omitempty"`
+	RedirectURL          string     `json:"redirect_url,omitempty"`
+	VANumber             string     `json:"va_number,omitempty"`
+	QRString             string     `json:"qr_string,omitempty"`
+	GatewayTransactionID string     `json:"gateway_transaction_id,omitempty"`
+	ExpiresAt            *time.Time `json:"expires_at,omitempty"`
+}
+
+type PaymentLinkResponse struct {
+	OrderID     string                 `json:"order_id"`
+	Token       string                 `json:"token,omitempty"`
+	RedirectURL string                 `json:"redirect_url,omitempty"`
+	VANumber    string                 `json:"va_number,omitempty"`
+	QRString    string                 `json:"qr_string,omitempty"`
+	ExpiresAt   *time.Time             `json:"expires_at,omitempty"`
+	Shares      []PaymentShareResponse `json:"shares,omitempty"`
+}
@@ -0,0 +1,8 @@
+package model
+
+type PaymentMethod struct {
+	Code         string `json:"code"`
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	DirectCharge bool   `json:"direct_charge"`
+}
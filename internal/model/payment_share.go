@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+const (
+	PaymentSharePending   = "pending"
+	PaymentSharePaid      = "paid"
+	PaymentShareRefunded  = "refunded"
+	PaymentShareCancelled = "cancelled"
+	// PaymentShareRefundFailed is a paid share of a failed order the gateway did not refund yet
+	PaymentShareRefundFailed = "refund_failed"
+)
+
+// PaymentShare is one part of a split order, paid through its own midtrans order id
+type PaymentShare struct {
+	PaymentShareID int        `json:"payment_share_id"`
+	OrderID        string     `json:"order_id"`
+	ShareOrderID   string     `json:"share_order_id"`
+	ShareNumber    int        `json:"share_number"`
+	Amount         int64      `json:"amount"`
+	Status         string     `json:"status"`
+	Token          string     `json:"token"`
+	RedirectURL    string     `json:"redirect_url"`
+	PaidAt         *time.Time `json:"paid_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type PaymentShareResponse struct {
+	ShareOrderID string `json:"share_order_id"`
+	ShareNumber  int    `json:"share_number"`
+	Amount       int64  `json:"amount"`
+	Status       string `json:"status"`
+	Token        string `json:"token,omitempty"`
+	RedirectURL  string `json:"redirect_url,omitempty"`
+}
+
+// PaymentShareResult tells the webhook what a share notification did to the order
+type PaymentShareResult struct {
+	OrderID string
+	AllPaid bool
+}
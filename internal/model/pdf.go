@@ -12,9 +12,11 @@ type EmailPDFMessage struct {
 	CustomerName   string         `json:"customer_name"`
 	PurchaseDate   string         `json:"purchase_date"`
 	DetailTickets  []DetailTicket `json:"detail_tickets"`
+	Tickets        []IssuedTicket `json:"tickets"`
 }
 
 type DetailTicket struct {
 	TicketType  string `json:"ticket_type"`
 	TotalTicket int    `json:"total_ticket"`
+	HolderName  string `json:"holder_name,omitempty"`
 }
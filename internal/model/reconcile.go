@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+const (
+	ReconcileFixed     = "fixed"
+	ReconcileWouldFix  = "would_fix"
+	ReconcileFixFailed = "fix_failed"
+	ReconcileManual    = "manual"
+)
+
+// ReconcileDiscrepancy is one order whose status or amount differs from midtrans
+type ReconcileDiscrepancy struct {
+	TransactionID   int       `json:"transaction_id"`
+	OrderID         string    `json:"order_id"`
+	TransactionDate time.Time `json:"transaction_date"`
+	LocalStatus     string    `json:"local_status"`
+	GatewayStatus   string    `json:"gateway_status"`
+	FraudStatus     string    `json:"fraud_status,omitempty"`
+	LocalAmount     int64     `json:"local_amount"`
+	GatewayAmount   int64     `json:"gateway_amount"`
+	Action          string    `json:"action"`
+	Reason          string    `json:"reason"`
+}
+
+type ReconcileReport struct {
+	From          time.Time              `json:"from"`
+	To            time.Time              `json:"to"`
+	DryRun        bool                   `json:"dry_run"`
+	Checked       int                    `json:"checked"`
+	Matched       int                    `json:"matched"`
+	Skipped       int                    `json:"skipped"`
+	Fixed         int                    `json:"fixed"`
+	Discrepancies []ReconcileDiscrepancy `json:"discrepancies"`
+}
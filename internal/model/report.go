@@ -0,0 +1,48 @@
+package model
+
+const (
+	ReportGroupEvent         = "event"
+	ReportGroupContinent     = "continent"
+	ReportGroupTicketType    = "ticket_type"
+	ReportGroupPaymentMethod = "payment_method"
+	ReportGroupDay           = "day"
+)
+
+type SalesReportRequest struct {
+	GroupBy string `query:"group_by" validate:"required,oneof=event continent ticket_type payment_method day"`
+	From    string `query:"from" validate:"required,datetime=2006-01-02"`
+	To      string `query:"to" validate:"required,datetime=2006-01-02"`
+	// Status defaults to completed, all includes every status
+	Status string `query:"status" validate:"omitempty,oneof=all pending completed cancelled"`
+	// Currency defaults to USD, the currency tickets are priced in
+	Currency string `query:"currency" validate:"omitempty,oneof=USD IDR"`
+	Format   string `query:"format" validate:"omitempty,oneof=json csv"`
+}
+
+// SalesReportRow is one group as the repository sums it, in the ticket price currency
+type SalesReportRow struct {
+	Key          string
+	Transactions int
+	Tickets      int
+	GrossAmount  float64
+	NetAmount    float64
+}
+
+type SalesReportLine struct {
+	Key          string  `json:"key"`
+	Transactions int     `json:"transactions"`
+	Tickets      int     `json:"tickets"`
+	GrossAmount  float64 `json:"gross_amount"`
+	Discount     float64 `json:"discount"`
+	NetAmount    float64 `json:"net_amount"`
+}
+
+type SalesReport struct {
+	GroupBy  string            `json:"group_by"`
+	From     string            `json:"from"`
+	To       string            `json:"to"`
+	Status   string            `json:"status"`
+	Currency string            `json:"currency"`
+	Rows     []SalesReportLine `json:"rows"`
+	Total    SalesReportLine   `json:"total"`
+}
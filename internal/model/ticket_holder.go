@@ -0,0 +1,8 @@
+package model
+
+// TicketHolder is who attends with the tickets of one line when it is not the buyer, e.g. a gift
+type TicketHolder struct {
+	FullName string `json:"full_name"`
+	Email    string `json:"email"`
+	IDNumber string `json:"id_number"`
+}
@@ -0,0 +1,57 @@
+package model
+
+import "time"
+
+const (
+	OwnershipOwned     = "owned"
+	OwnershipPending   = "pending"
+	OwnershipCancelled = "cancelled"
+)
+
+// TicketOwnership is who holds Quantity tickets of one order line. A pending row is a transfer offered
+// by TransferredFrom that the recipient has not accepted yet, it is void after ExpiresAt.
+type TicketOwnership struct {
+	TicketOwnershipID   int        `json:"ticket_ownership_id"`
+	DetailTransactionID int        `json:"detail_transaction_id"`
+	OrderID             string     `json:"order_id"`
+	TicketID            int        `json:"ticket_id"`
+	Email               string     `json:"email"`
+	Quantity            int        `json:"quantity"`
+	Status              string     `json:"status"`
+	TransferredFrom     string     `json:"transferred_from"`
+	EventDate           *time.Time `json:"event_date"`
+	ExpiresAt           *time.Time `json:"expires_at"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// TicketLine is an order line with the order fields needed to transfer its tickets
+type TicketLine struct {
+	DetailTransactionID int    `json:"detail_transaction_id"`
+	TicketID            int    `json:"ticket_id"`
+	Quantity            int    `json:"quantity"`
+	OrderID             string `json:"order_id"`
+	BuyerEmail          string `json:"buyer_email"`
+	PaymentStatus       string `json:"payment_status"`
+}
+
+type TransferRequest struct {
+	DetailTransactionID int    `json:"detail_transaction_id" validate:"required,gt=0"`
+	Quantity            int    `json:"quantity" validate:"required,gt=0"`
+	RecipientEmail      string `json:"recipient_email" validate:"required,email"`
+}
+
+type AcceptTransferRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type TransferResponse struct {
+	TransferID          int       `json:"transfer_id"`
+	DetailTransactionID int       `json:"detail_transaction_id"`
+	TicketID            int       `json:"ticket_id"`
+	RecipientEmail      string    `json:"recipient_email"`
+	Quantity            int       `json:"quantity"`
+	Status              string    `json:"status"`
+	Token               string    `json:"token"`
+	ExpiresAt           time.Time `json:"expires_at"`
+}
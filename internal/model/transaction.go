@@ -3,58 +3,117 @@ package model
 import "time"
 
 type Transaction struct {
-	TransactionID   int       `json:"transaction_id"`
-	UserID          int       `json:"user_id"`
-	OrderID         string    `json:"order_id"`
-	TransactionDate time.Time `json:"transaction_date"`
-	Continent       string    `json:"continent"`
-	PaymentMethod   string    `json:"payment_method"`
-	TotalAmount     float32   `json:"total_amount"`
-	TotalTicket     int       `json:"total_ticket"`
-	Discount        float32   `json:"discount"`
-	FullName        string    `json:"full_name"`
-	MobileNumber    string    `json:"mobile_number"`
-	Email           string    `json:"email"`
-	PaymentStatus   string    `json:"payment_status"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	TransactionID   int        `json:"transaction_id"`
+	UserID          int        `json:"user_id"`
+	OrderID         string     `json:"order_id"`
+	TransactionDate time.Time  `json:"transaction_date"`
+	Continent       string     `json:"continent"`
+	PaymentMethod   string     `json:"payment_method"`
+	TotalAmount     float32    `json:"total_amount"`
+	GrossAmount     int64      `json:"gross_amount"`
+	TotalTicket     int        `json:"total_ticket"`
+	Discount        float32    `json:"discount"`
+	DiscountTrace   string     `json:"discount_trace"`
+	VoucherCode     string     `json:"voucher_code"`
+	VoucherDiscount float32    `json:"voucher_discount"`
+	SplitCount      int        `json:"split_count"`
+	SplitDeadline   *time.Time `json:"split_deadline"`
+	FullName        string     `json:"full_name"`
+	MobileNumber    string     `json:"mobile_number"`
+	Email           string     `json:"email"`
+	PaymentStatus   string     `json:"payment_status"`
+	VANumber        string     `json:"va_number"`
+	QRString        string     `json:"qr_string"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
 }
 
 type DetailTransaction struct {
-	DetailTransactionID int       `json:"detail_transaction_id"`
-	TransactionID       int       `json:"transaction_id"`
-	TicketID            int       `json:"ticket_id"`
-	TicketType          string    `json:"ticket_type"`
-	CountryName         string    `json:"country_name"`
-	City                string    `json:"city"`
-	Quantity            int       `json:"quantity"`
-	CreatedAt           time.Time `json:"created_at"`
-	UpdatedAt           time.Time `json:"updated_at"`
+	DetailTransactionID int           `json:"detail_transaction_id"`
+	TransactionID       int           `json:"transaction_id"`
+	TicketID            int           `json:"ticket_id"`
+	TicketType          string        `json:"ticket_type"`
+	Continent           string        `json:"continent"`
+	CountryName         string        `json:"country_name"`
+	City                string        `json:"city"`
+	Quantity            int           `json:"quantity"`
+	Price               float32       `json:"price"`
+	NetAmount           float32       `json:"net_amount"`
+	Holder              *TicketHolder `json:"holder"`
+	CreatedAt           time.Time     `json:"created_at"`
+	UpdatedAt           time.Time     `json:"updated_at"`
 }
 
 type DetailTransactionRequest struct {
 	TicketID    int    `json:"ticket_id"`
 	TicketType  string `json:"ticket_type"`
+	Continent   string `json:"continent"`
 	CountryName string `json:"country_name"`
 	City        string `json:"city"`
 	Quantity    int    `json:"quantity"`
+	// Holder is optional, without it the tickets are for the buyer
+	Holder *TicketHolder `json:"holder"`
 }
 
 type TransactionRequest struct {
-	PaymentMethod string                     `json:"payment_method"`
+	PaymentMethod string `json:"payment_method"`
+	DirectCharge  bool   `json:"direct_charge"`
+	VoucherCode   string `json:"voucher_code"`
+	QuoteID       string `json:"quote_id"`
+	// SplitCount above 1 splits the payment into that many shares
+	SplitCount    int                        `json:"split_count"`
 	TotalTicket   int                        `json:"total_ticket"`
 	DetailTicket  []DetailTransactionRequest `json:"detail_ticket"`
 	PaymentStatus string                     `json:"payment_status"`
 	Continent     string                     `json:"continent"`
 }
 
+type CreateTransactionResponse struct {
+	OrderID          string                 `json:"order_id"`
+	Token            string                 `json:"token"`
+	RedirectURL      string                 `json:"redirect_url"`
+	PaymentMethod    string                 `json:"payment_method"`
+	VANumber         string                 `json:"va_number,omitempty"`
+	QRString         string                 `json:"qr_string,omitempty"`
+	Discount         float32                `json:"discount"`
+	VoucherCode      string                 `json:"voucher_code,omitempty"`
+	VoucherDiscount  float32                `json:"voucher_discount,omitempty"`
+	TotalTransaction float32                `json:"total_transaction"`
+	SplitDeadline    *time.Time             `json:"split_deadline,omitempty"`
+	Shares           []PaymentShareResponse `json:"shares,omitempty"`
+}
+
+type QuoteItem struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+	PriceIDR int64  `json:"price_idr"`
+}
+
+type QuoteResponse struct {
+	QuoteID         string      `json:"quote_id"`
+	ExpiresAt       time.Time   `json:"expires_at"`
+	Subtotal        float32     `json:"subtotal"`
+	Discount        float32     `json:"discount"`
+	VoucherCode     string      `json:"voucher_code,omitempty"`
+	VoucherDiscount float32     `json:"voucher_discount,omitempty"`
+	TotalAmount     float32     `json:"total_amount"`
+	TotalIDR        int64       `json:"total_idr"`
+	Items           []QuoteItem `json:"items"`
+}
+
 type DetailTransactionResponse struct {
-	DetailTransactionID int    `json:"detail_transaction_id"`
-	TicketID            int    `json:"ticket_id"`
-	TicketType          string `json:"ticket_type"`
-	CountryName         string `json:"country_name"`
-	City                string `json:"city"`
-	Quantity            int    `json:"quantity"`
+	DetailTransactionID int           `json:"detail_transaction_id"`
+	TicketID            int           `json:"ticket_id"`
+	TicketType          string        `json:"ticket_type"`
+	Continent           string        `json:"continent"`
+	CountryName         string        `json:"country_name"`
+	City                string        `json:"city"`
+	Quantity            int           `json:"quantity"`
+	Holder              *TicketHolder `json:"holder,omitempty"`
+	EventName           string        `json:"event_name,omitempty"`
+	Venue               string        `json:"venue,omitempty"`
+	EventDate           *time.Time    `json:"event_date,omitempty"`
 }
 
 type TransactionResponse struct {
@@ -69,6 +128,8 @@ type TransactionResponse struct {
 	Status                    string                      `json:"status"`
 	Continent                 string                      `json:"continent"`
 	DetailTransactionResponse []DetailTransactionResponse `json:"detail_transaction"`
+	PaymentShares             []PaymentShareResponse      `json:"payment_shares,omitempty"`
+	Payment                   *PaymentResponse            `json:"payment,omitempty"`
 	CreatedAt                 time.Time                   `json:"created_at"`
 }
 
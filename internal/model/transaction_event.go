@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// who changed the payment status of a transaction
+const (
+	TransactionActorUser      = "user"
+	TransactionActorWebhook   = "webhook"
+	TransactionActorSweeper   = "sweeper"
+	TransactionActorAdmin     = "admin"
+	TransactionActorReconcile = "reconcile"
+)
+
+type TransactionEvent struct {
+	TransactionEventID int       `json:"transaction_event_id"`
+	TransactionID      int       `json:"transaction_id"`
+	OrderID            string    `json:"order_id"`
+	Actor              string    `json:"actor"`
+	ActorID            string    `json:"actor_id,omitempty"`
+	PreviousStatus     string    `json:"previous_status"`
+	NewStatus          string    `json:"new_status"`
+	PayloadReference   string    `json:"payload_reference,omitempty"`
+	Note               string    `json:"note,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+}
@@ -0,0 +1,58 @@
+package model
+
+import "time"
+
+const (
+	VoucherTypePercentage = "percentage"
+	VoucherTypeFixed      = "fixed"
+
+	VoucherRedemptionReserved = "reserved"
+	VoucherRedemptionRedeemed = "redeemed"
+	VoucherRedemptionReleased = "released"
+)
+
+type Voucher struct {
+	VoucherID     int        `json:"voucher_id"`
+	Code          string     `json:"code"`
+	Description   string     `json:"description"`
+	DiscountType  string     `json:"discount_type"`
+	Amount        float32    `json:"amount"`
+	MinSpend      float32    `json:"min_spend"`
+	ValidFrom     *time.Time `json:"valid_from"`
+	ValidUntil    *time.Time `json:"valid_until"`
+	MaxRedemption int        `json:"max_redemption"`
+	MaxPerUser    int        `json:"max_per_user"`
+	RedeemedCount int        `json:"redeemed_count"`
+	Continent     string     `json:"continent"`
+	TicketType    string     `json:"ticket_type"`
+	IsActive      bool       `json:"is_active"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// VoucherRequest is used by the admin endpoints, max_redemption and max_per_user 0 means unlimited
+type VoucherRequest struct {
+	Code          string     `json:"code" validate:"required,max=50"`
+	Description   string     `json:"description" validate:"max=255"`
+	DiscountType  string     `json:"discount_type" validate:"required,oneof=percentage fixed"`
+	Amount        float32    `json:"amount" validate:"required,gt=0"`
+	MinSpend      float32    `json:"min_spend" validate:"gte=0"`
+	ValidFrom     *time.Time `json:"valid_from"`
+	ValidUntil    *time.Time `json:"valid_until"`
+	MaxRedemption int        `json:"max_redemption" validate:"gte=0"`
+	MaxPerUser    int        `json:"max_per_user" validate:"gte=0"`
+	Continent     string     `json:"continent"`
+	TicketType    string     `json:"ticket_type"`
+	IsActive      *bool      `json:"is_active"`
+}
+
+type VoucherRedemption struct {
+	VoucherRedemptionID int       `json:"voucher_redemption_id"`
+	VoucherID           int       `json:"voucher_id"`
+	OrderID             string    `json:"order_id"`
+	Email               string    `json:"email"`
+	Amount              float32   `json:"amount"`
+	Status              string    `json:"status"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+const (
+	WaitlistWaiting   = "waiting"
+	WaitlistNotified  = "notified"
+	WaitlistPurchased = "purchased"
+	WaitlistExpired   = "expired"
+	WaitlistLeft      = "left"
+)
+
+// Waitlist is one user waiting for a sold out ticket. A notified entry holds Quantity tickets
+// for the user until HoldUntil.
+type Waitlist struct {
+	WaitlistID int        `json:"waitlist_id"`
+	TicketID   int        `json:"ticket_id"`
+	Continent  string     `json:"continent"`
+	Email      string     `json:"email"`
+	Quantity   int        `json:"quantity"`
+	Status     string     `json:"status"`
+	HoldUntil  *time.Time `json:"hold_until"`
+	OrderID    string     `json:"order_id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type WaitlistRequest struct {
+	TicketID  int    `json:"ticket_id" validate:"required,gt=0"`
+	Continent string `json:"continent" validate:"required"`
+	Quantity  int    `json:"quantity" validate:"required,gt=0"`
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"go.uber.org/zap"
+)
+
+type adminRepository struct {
+	DB     *sql.DB
+	logger config.Logger
+}
+
+type AdminPersister interface {
+	SearchTransactions(filter model.AdminTransactionFilter) ([]model.Transaction, int, error)
+	GetOrderIDByTransactionID(transactionID int) (string, error)
+}
+
+func NewAdminRepository(DB *sql.DB, logger config.Logger) AdminPersister {
+	return &adminRepository{DB: DB, logger: logger}
+}
+
+// adminTransactionWhere builds the WHERE clause of a search, every value goes in as a placeholder
+func adminTransactionWhere(filter model.AdminTransactionFilter) (string, []any) {
+	var conditions []string
+	var args []any
+	add := func(condition string, arg any) {
+		conditions = append(conditions, condition)
+		args = append(args, arg)
+	}
+
+	if filter.OrderID != "" {
+		add("order_id = ?", filter.OrderID)
+	}
+	if filter.Email != "" {
+		add("email = ?", filter.Email)
+	}
+	if filter.MobileNumber != "" {
+		add("mobile_number = ?", filter.MobileNumber)
+	}
+	if filter.Status != "" {
+		add("payment_status = ?", filter.Status)
+	}
+	if filter.Continent != "" {
+		add("FIND_IN_SET(?, continent) > 0", filter.Continent)
+	}
+	if !filter.From.IsZero() {
+		add("transaction_date >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		add("transaction_date < ?", filter.To)
+	}
+	if filter.MinAmount > 0 {
+		add("total_amount >= ?", filter.MinAmount)
+	}
+	if filter.MaxAmount > 0 {
+		add("total_amount <= ?", filter.MaxAmount)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func (r *adminRepository) SearchTransactions(filter model.AdminTransactionFilter) ([]model.Transaction, int, error) {
+	where, args := adminTransactionWhere(filter)
+
+	var total int
+	if err := r.DB.QueryRow(`SELECT COUNT(*) FROM transaction`+where, args...).Scan(&total); err != nil {
+		r.logger.Error("Error when counting transaction table", zap.Error(err))
+		return nil, 0, err
+	}
+
+	query := `SELECT transaction_id, user_id, order_id, transaction_date, payment_method, total_amount, gross_amount, total_ticket,
+		full_name, mobile_number, email, payment_status, continent, split_count, created_at, updated_at FROM transaction` + where +
+		` ORDER BY transaction_id DESC LIMIT ? OFFSET ?`
+
+	rows, err := r.DB.Query(query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		r.logger.Error("Error when querying transaction table", zap.Error(err))
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	transactions := []model.Transaction{}
+	for rows.Next() {
+		var transaction model.Transaction
+		if err := rows.Scan(&transaction.TransactionID, &transaction.UserID, &transaction.OrderID, &transaction.TransactionDate,
+			&transaction.PaymentMethod, &transaction.TotalAmount, &transaction.GrossAmount, &transaction.TotalTicket, &transaction.FullName,
+			&transaction.MobileNumber, &transaction.Email, &transaction.PaymentStatus, &transaction.Continent, &transaction.SplitCount,
+			&transaction.CreatedAt, &transaction.UpdatedAt); err != nil {
+			r.logger.Error("Error when scanning transaction table", zap.Error(err))
+			return nil, 0, err
+		}
+		transactions = append(transactions, transaction)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Error("Error when iterating transaction rows", zap.Error(err))
+		return nil, 0, err
+	}
+
+	return transactions, total, nil
+}
+
+func (r *adminRepository) GetOrderIDByTransactionID(transactionID int) (string, error) {
+	var orderID string
+	err := r.DB.QueryRow(`SELECT order_id FROM transaction WHERE transaction_id = ?`, transactionID).Scan(&orderID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		r.logger.Error("Error when scanning transaction table", zap.Error(err))
+	}
+
+	return orderID, err
+}
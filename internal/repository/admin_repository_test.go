@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	mock_config "github.com/SyamSolution/transaction-service/mock/config"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSearchTransactions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+
+	r := NewAdminRepository(db, logger)
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	filter := model.AdminTransactionFilter{Email: "ani@example.com", Status: "completed", Continent: "Asia", From: from, MinAmount: 10,
+		Offset: 20, Limit: 20}
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM transaction WHERE email = \? AND payment_status = \? AND FIND_IN_SET\(\?, continent\) > 0
+		AND transaction_date >= \? AND total_amount >= \?`).
+		WithArgs("ani@example.com", "completed", "Asia", from, float64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(21))
+	mock.ExpectQuery(`SELECT transaction_id, .+ FROM transaction WHERE email = \? .+ ORDER BY transaction_id DESC LIMIT \? OFFSET \?`).
+		WithArgs("ani@example.com", "completed", "Asia", from, float64(10), 20, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"transaction_id", "user_id", "order_id", "transaction_date", "payment_method",
+			"total_amount", "gross_amount", "total_ticket", "full_name", "mobile_number", "email", "payment_status", "continent",
+			"split_count", "created_at", "updated_at"}).
+			AddRow(1, 1, "ORDER-1", from, "gopay", 15, 240675, 1, "Ani", "0812", "ani@example.com", "completed", "Asia", 0, from, from))
+
+	transactions, total, err := r.SearchTransactions(filter)
+	if err != nil {
+		t.Errorf("error was not expected while searching transactions: %s", err)
+	}
+	if total != 21 || len(transactions) != 1 || transactions[0].OrderID != "ORDER-1" {
+		t.Errorf("unexpected result: %d %+v", total, transactions)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,18 @@
+package repository
+
+import "errors"
+
+var ErrDuplicateOrderID = errors.New("order id already exists")
+
+var ErrDuplicateWaitlist = errors.New("active waitlist entry already exists")
+
+var (
+	ErrDuplicateVoucherCode = errors.New("voucher code already exists")
+	ErrVoucherExhausted     = errors.New("voucher has no redemption left")
+	ErrVoucherUserLimit     = errors.New("voucher redemption limit per user reached")
+)
+
+var (
+	ErrTransferOrderNotCompleted  = errors.New("order of the transfer is not completed")
+	ErrTransferTicketsUnavailable = errors.New("sender has not enough unused tickets")
+)
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"go.uber.org/zap"
+)
+
+const invoiceColumns = `invoice_id, invoice_number, invoice_year, sequence_number, transaction_id, order_id, issued_at, data, pdf`
+
+type invoiceRepository struct {
+	DB     *sql.DB
+	logger config.Logger
+}
+
+type InvoicePersister interface {
+	CreateInvoice(invoice model.InvoiceRecord, prefix string) (model.InvoiceRecord, bool, error)
+	GetInvoiceByTransactionID(transactionID int) (model.InvoiceRecord, error)
+	UpdateInvoiceDocument(invoiceID int, data, pdf []byte) error
+}
+
+func NewInvoiceRepository(DB *sql.DB, logger config.Logger) InvoicePersister {
+	return &invoiceRepository{DB: DB, logger: logger}
+}
+
+func scanInvoice(row interface{ Scan(dest ...any) error }) (model.InvoiceRecord, error) {
+	var invoice model.InvoiceRecord
+	err := row.Scan(&invoice.InvoiceID, &invoice.InvoiceNumber, &invoice.InvoiceYear, &invoice.SequenceNumber, &invoice.TransactionID,
+		&invoice.OrderID, &invoice.IssuedAt, &invoice.Data, &invoice.PDF)
+
+	return invoice, err
+}
+
+// CreateInvoice numbers the invoice as prefix/year/sequence. The year's sequence row stays locked until the
+// invoice is inserted, so numbers are handed out one at a time and a failed insert gives its number back.
+// An invoice that already exists for the transaction is returned with false.
+func (r *invoiceRepository) CreateInvoice(invoice model.InvoiceRecord, prefix string) (model.InvoiceRecord, bool, error) {
+	year := invoice.IssuedAt.Year()
+
+	tx, err := r.DB.Begin()
+	if err != nil {
+		r.logger.Error("Error when begin transaction", zap.Error(err))
+		return invoice, false, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT INTO invoice_sequence (invoice_year, last_number) VALUES (?, 0)
+		ON DUPLICATE KEY UPDATE invoice_year = invoice_year`, year)
+	if err != nil {
+		r.logger.Error("Error when creating invoice sequence", zap.Error(err))
+		return invoice, false, err
+	}
+
+	var lastNumber int
+	err = tx.QueryRow(`SELECT last_number FROM invoice_sequence WHERE invoice_year = ? FOR UPDATE`, year).Scan(&lastNumber)
+	if err != nil {
+		r.logger.Error("Error when locking invoice sequence", zap.Error(err))
+		return invoice, false, err
+	}
+
+	// dicek setelah sequence dikunci, settlement dan request invoice yang bersamaan tidak membuat dua nomor
+	existing, err := scanInvoice(tx.QueryRow(`SELECT `+invoiceColumns+` FROM invoice WHERE transaction_id = ?`, invoice.TransactionID))
+	if err == nil {
+		return existing, false, tx.Commit()
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		r.logger.Error("Error when scanning invoice table", zap.Error(err))
+		return invoice, false, err
+	}
+
+	invoice.InvoiceYear = year
+	invoice.SequenceNumber = lastNumber + 1
+	invoice.InvoiceNumber = fmt.Sprintf("%s/%d/%06d", prefix, year, invoice.SequenceNumber)
+
+	if _, err = tx.Exec(`UPDATE invoice_sequence SET last_number = ? WHERE invoice_year = ?`, invoice.SequenceNumber, year); err != nil {
+		r.logger.Error("Error when updating invoice sequence", zap.Error(err))
+		return invoice, false, err
+	}
+
+	now := time.Now()
+	result, err := tx.Exec(`INSERT INTO invoice (invoice_number, invoice_year, sequence_number, transaction_id, order_id, issued_at,
+		created_at, updated_at) VALUES (?,?,?,?,?,?,?,?)`, invoice.InvoiceNumber, invoice.InvoiceYear, invoice.SequenceNumber,
+		invoice.TransactionID, invoice.OrderID, invoice.IssuedAt, now, now)
+	if err != nil {
+		r.logger.Error("Error when inserting invoice", zap.Error(err))
+		return invoice, false, err
+	}
+	invoiceID, _ := result.LastInsertId()
+	invoice.InvoiceID = int(invoiceID)
+
+	if err = tx.Commit(); err != nil {
+		r.logger.Error("Error when commit transaction", zap.Error(err))
+		return invoice, false, err
+	}
+
+	return invoice, true, nil
+}
+
+func (r *invoiceRepository) GetInvoiceByTransactionID(transactionID int) (model.InvoiceRecord, error) {
+	query := `SELECT ` + invoiceColumns + ` FROM invoice WHERE transaction_id = ?`
+
+	invoice, err := scanInvoice(r.DB.QueryRow(query, transactionID))
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			r.logger.Error("Error when scanning invoice table", zap.Error(err))
+		}
+		return invoice, err
+	}
+
+	return invoice, nil
+}
+
+func (r *invoiceRepository) UpdateInvoiceDocument(invoiceID int, data, pdf []byte) error {
+	query := `UPDATE invoice SET data = ?, pdf = ?, updated_at = ? WHERE invoice_id = ?`
+
+	_, err := r.DB.Exec(query, data, pdf, time.Now(), invoiceID)
+	if err != nil {
+		r.logger.Error("Error when updating invoice document", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	mock_config "github.com/SyamSolution/transaction-service/mock/config"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateInvoice(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+
+	r := NewInvoiceRepository(db, logger)
+
+	issuedAt := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	columns := []string{"invoice_id", "invoice_number", "invoice_year", "sequence_number", "transaction_id", "order_id", "issued_at",
+		"data", "pdf"}
+
+	t.Run("takes the next number of the year", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO invoice_sequence \(invoice_year, last_number\) VALUES \(\?, 0\)`).
+			WithArgs(2026).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`SELECT last_number FROM invoice_sequence WHERE invoice_year = \? FOR UPDATE`).
+			WithArgs(2026).WillReturnRows(sqlmock.NewRows([]string{"last_number"}).AddRow(41))
+		mock.ExpectQuery(`SELECT .+ FROM invoice WHERE transaction_id = \?`).
+			WithArgs(7).WillReturnRows(sqlmock.NewRows(columns))
+		mock.ExpectExec(`UPDATE invoice_sequence SET last_number = \? WHERE invoice_year = \?`).
+			WithArgs(42, 2026).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO invoice \(invoice_number, invoice_year, sequence_number, transaction_id, order_id, issued_at,`).
+			WithArgs("INV/2026/000042", 2026, 42, 7, "ORD-7", issuedAt, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.ExpectCommit()
+
+		invoice, created, err := r.CreateInvoice(model.InvoiceRecord{TransactionID: 7, OrderID: "ORD-7", IssuedAt: issuedAt}, "INV")
+		if err != nil {
+			t.Errorf("error was not expected while creating invoice: %s", err)
+		}
+		if !created || invoice.InvoiceID != 3 || invoice.InvoiceNumber != "INV/2026/000042" {
+			t.Errorf("unexpected invoice: %+v, created %v", invoice, created)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("returns the existing invoice without a new number", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO invoice_sequence`).WithArgs(2026).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`SELECT last_number FROM invoice_sequence`).
+			WithArgs(2026).WillReturnRows(sqlmock.NewRows([]string{"last_number"}).AddRow(42))
+		mock.ExpectQuery(`SELECT .+ FROM invoice WHERE transaction_id = \?`).
+			WithArgs(7).WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(3, "INV/2026/000042", 2026, 42, 7, "ORD-7", issuedAt, []byte(`{}`), []byte("%PDF")))
+		mock.ExpectCommit()
+
+		invoice, created, err := r.CreateInvoice(model.InvoiceRecord{TransactionID: 7, OrderID: "ORD-7", IssuedAt: issuedAt}, "INV")
+		if err != nil {
+			t.Errorf("error was not expected while creating invoice: %s", err)
+		}
+		if created || invoice.InvoiceNumber != "INV/2026/000042" || string(invoice.PDF) != "%PDF" {
+			t.Errorf("unexpected invoice: %+v, created %v", invoice, created)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"go.uber.org/zap"
+)
+
+const issuedTicketColumns = `issued_ticket_id, detail_transaction_id, order_id, ticket_id, unit_number, code, ticket_type,
+		holder_name, owner_email, status, used_at, gate, checked_in_by, created_at, updated_at`
+
+type issuedTicketRepository struct {
+	DB     *sql.DB
+	logger config.Logger
+}
+
+type IssuedTicketPersister interface {
+	CreateIssuedTickets(tickets []model.IssuedTicket) error
+	GetIssuedTicketsByOrderID(orderID string) ([]model.IssuedTicket, error)
+	GetIssuedTicketsByOwner(email string) ([]model.IssuedTicket, error)
+	GetIssuedTicketByCode(code string) (model.IssuedTicket, error)
+	UseIssuedTicket(code, gate, checkedInBy string, usedAt time.Time) (bool, error)
+	GetAttendance(ticketIDs []int) ([]model.TicketAttendance, error)
+}
+
+func NewIssuedTicketRepository(DB *sql.DB, logger config.Logger) IssuedTicketPersister {
+	return &issuedTicketRepository{DB: DB, logger: logger}
+}
+
+func scanIssuedTicket(row interface{ Scan(dest ...any) error }) (model.IssuedTicket, error) {
+	var ticket model.IssuedTicket
+	err := row.Scan(&ticket.IssuedTicketID, &ticket.DetailTransactionID, &ticket.OrderID, &ticket.TicketID, &ticket.UnitNumber,
+		&ticket.Code, &ticket.TicketType, &ticket.HolderName, &ticket.OwnerEmail, &ticket.Status, &ticket.UsedAt, &ticket.Gate,
+		&ticket.CheckedInBy, &ticket.CreatedAt, &ticket.UpdatedAt)
+
+	return ticket, err
+}
+
+// CreateIssuedTickets skips units that already exist, so a repeated settlement notification issues nothing new
+func (r *issuedTicketRepository) CreateIssuedTickets(tickets []model.IssuedTicket) error {
+	query := `INSERT IGNORE INTO issued_ticket (detail_transaction_id, order_id, ticket_id, unit_number, code, ticket_type,
+		holder_name, owner_email, status, created_at, updated_at) VALUES (?,?,?,?,?,?,?,?,?,?,?)`
+
+	tx, err := r.DB.Begin()
+	if err != nil {
+		r.logger.Error("Error when begin transaction", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, ticket := range tickets {
+		_, err = tx.Exec(query, ticket.DetailTransactionID, ticket.OrderID, ticket.TicketID, ticket.UnitNumber, ticket.Code,
+			ticket.TicketType, ticket.HolderName, ticket.OwnerEmail, model.IssuedTicketIssued, ticket.CreatedAt, ticket.UpdatedAt)
+		if err != nil {
+			r.logger.Error("Error when inserting issued ticket", zap.Error(err))
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.logger.Error("Error when commit transaction", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *issuedTicketRepository) GetIssuedTicketsByOrderID(orderID string) ([]model.IssuedTicket, error) {
+	var tickets []model.IssuedTicket
+	query := `SELECT ` + issuedTicketColumns + ` FROM issued_ticket WHERE order_id = ? ORDER BY detail_transaction_id, unit_number`
+
+	rows, err := r.DB.Query(query, orderID)
+	if err != nil {
+		r.logger.Error("Error when querying issued ticket table", zap.Error(err))
+		return tickets, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		ticket, err := scanIssuedTicket(rows)
+		if err != nil {
+			r.logger.Error("Error when scanning issued ticket table", zap.Error(err))
+			return tickets, err
+		}
+		tickets = append(tickets, ticket)
+	}
+
+	return tickets, nil
+}
+
+// GetIssuedTicketsByOwner lists the tickets email holds, bought or received through a transfer
+func (r *issuedTicketRepository) GetIssuedTicketsByOwner(email string) ([]model.IssuedTicket, error) {
+	var tickets []model.IssuedTicket
+	query := `SELECT ` + issuedTicketColumns + ` FROM issued_ticket WHERE owner_email = ?
+		ORDER BY order_id, detail_transaction_id, unit_number`
+
+	rows, err := r.DB.Query(query, email)
+	if err != nil {
+		r.logger.Error("Error when querying issued ticket table", zap.Error(err))
+		return tickets, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		ticket, err := scanIssuedTicket(rows)
+		if err != nil {
+			r.logger.Error("Error when scanning issued ticket table", zap.Error(err))
+			return tickets, err
+		}
+		tickets = append(tickets, ticket)
+	}
+
+	return tickets, nil
+}
+
+func (r *issuedTicketRepository) GetIssuedTicketByCode(code string) (model.IssuedTicket, error) {
+	query := `SELECT ` + issuedTicketColumns + ` FROM issued_ticket WHERE code = ?`
+
+	ticket, err := scanIssuedTicket(r.DB.QueryRow(query, code))
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			r.logger.Error("Error when scanning issued ticket table", zap.Error(err))
+		}
+		return ticket, err
+	}
+
+	return ticket, nil
+}
+
+// UseIssuedTicket marks the ticket used, only the first of concurrent scans gets true
+func (r *issuedTicketRepository) UseIssuedTicket(code, gate, checkedInBy string, usedAt time.Time) (bool, error) {
+	query := `UPDATE issued_ticket SET status = 'used', used_at = ?, gate = ?, checked_in_by = ?, updated_at = ? WHERE code = ?
+		AND status = 'issued'`
+
+	result, err := r.DB.Exec(query, usedAt, gate, checkedInBy, time.Now(), code)
+	if err != nil {
+		r.logger.Error("Error when using issued ticket", zap.Error(err))
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.Error("Error when getting affected rows", zap.Error(err))
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (r *issuedTicketRepository) GetAttendance(ticketIDs []int) ([]model.TicketAttendance, error) {
+	var attendances []model.TicketAttendance
+	query := `SELECT ticket_id, ticket_type, COALESCE(SUM(status <> 'void'), 0), COALESCE(SUM(status = 'used'), 0) FROM issued_ticket
+		WHERE ticket_id IN (` + placeholders(len(ticketIDs)) + `) GROUP BY ticket_id, ticket_type ORDER BY ticket_id, ticket_type`
+
+	args := make([]any, 0, len(ticketIDs))
+	for _, id := range ticketIDs {
+		args = append(args, id)
+	}
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		r.logger.Error("Error when querying issued ticket table", zap.Error(err))
+		return attendances, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var attendance model.TicketAttendance
+		if err := rows.Scan(&attendance.TicketID, &attendance.TicketType, &attendance.Issued, &attendance.CheckedIn); err != nil {
+			r.logger.Error("Error when scanning issued ticket table", zap.Error(err))
+			return attendances, err
+		}
+		attendances = append(attendances, attendance)
+	}
+
+	return attendances, nil
+}
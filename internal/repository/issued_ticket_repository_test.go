@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	mock_config "github.com/SyamSolution/transaction-service/mock/config"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateIssuedTickets(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+
+	r := NewIssuedTicketRepository(db, logger)
+
+	now := time.Now()
+	tickets := []model.IssuedTicket{
+		{DetailTransactionID: 1, OrderID: "ORDER-1", TicketID: 3, UnitNumber: 1, Code: "AAAA", TicketType: "VIP", HolderName: "Ani", OwnerEmail: "ani@example.com", CreatedAt: now, UpdatedAt: now},
+		{DetailTransactionID: 1, OrderID: "ORDER-1", TicketID: 3, UnitNumber: 2, Code: "BBBB", TicketType: "VIP", HolderName: "Ani", OwnerEmail: "ani@example.com", CreatedAt: now, UpdatedAt: now},
+	}
+
+	mock.ExpectBegin()
+	for _, ticket := range tickets {
+		mock.ExpectExec("INSERT IGNORE INTO issued_ticket").WithArgs(ticket.DetailTransactionID, ticket.OrderID, ticket.TicketID, ticket.UnitNumber,
+			ticket.Code, ticket.TicketType, ticket.HolderName, ticket.OwnerEmail, model.IssuedTicketIssued, now, now).WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	mock.ExpectCommit()
+
+	if err := r.CreateIssuedTickets(tickets); err != nil {
+		t.Errorf("error was not expected while creating issued tickets: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUseIssuedTicket(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+
+	r := NewIssuedTicketRepository(db, logger)
+
+	now := time.Now()
+	mock.ExpectExec(`UPDATE issued_ticket SET status = 'used', used_at = \?, gate = \?, checked_in_by = \?, updated_at = \? WHERE code = \?
+  AND status = 'issued'`).
+		WithArgs(now, "A1", "scanner@example.com", sqlmock.AnyArg(), "AAAA").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE issued_ticket SET status = 'used'`).
+		WithArgs(now, "B2", "scanner@example.com", sqlmock.AnyArg(), "AAAA").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	used, err := r.UseIssuedTicket("AAAA", "A1", "scanner@example.com", now)
+	if err != nil || !used {
+		t.Errorf("first scan should use the ticket, got %v %v", used, err)
+	}
+	used, err = r.UseIssuedTicket("AAAA", "B2", "scanner@example.com", now)
+	if err != nil || used {
+		t.Errorf("second scan should not use the ticket, got %v %v", used, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestGetAttendance(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+
+	r := NewIssuedTicketRepository(db, logger)
+
+	rows := sqlmock.NewRows([]string{"ticket_id", "ticket_type", "issued", "checked_in"}).
+		AddRow(1, "VIP", 10, 4).
+		AddRow(2, "Regular", 20, 0)
+	mock.ExpectQuery(`SELECT ticket_id, ticket_type, COALESCE\(SUM\(status <> 'void'\), 0\), COALESCE\(SUM\(status = 'used'\), 0\) FROM issued_ticket
+  WHERE ticket_id IN \(\?,\?\) GROUP BY ticket_id, ticket_type`).WithArgs(1, 2).WillReturnRows(rows)
+
+	attendances, err := r.GetAttendance([]int{1, 2})
+	if err != nil {
+		t.Errorf("error was not expected while getting attendance: %s", err)
+	}
+	if len(attendances) != 2 || attendances[0].CheckedIn != 4 || attendances[1].Issued != 20 {
+		t.Errorf("unexpected attendance %+v", attendances)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"go.uber.org/zap"
+)
+
+// SavePayment stores how the order is paid. Empty fields keep what was stored, so the webhook can add the VA number
+// and gateway transaction id without touching the snap link. A link with a higher link number replaces everything of
+// the old one, link_number is updated last because the assignments before it compare with the stored one.
+func (r *transactionRepository) SavePayment(payment model.Payment) error {
+	query := `INSERT INTO payment (transaction_id, order_id, token, redirect_url, va_number, qr_string, gateway_transaction_id, link_number,
+		expires_at, created_at, updated_at)
+		SELECT transaction_id, order_id, ?, ?, ?, NULLIF(?, ''), ?, ?, ?, ?, ? FROM transaction WHERE order_id = ?
+		ON DUPLICATE KEY UPDATE token = IF(VALUES(token) = '' AND VALUES(link_number) <= link_number, token, VALUES(token)),
+		redirect_url = IF(VALUES(redirect_url) = '' AND VALUES(link_number) <= link_number, redirect_url, VALUES(redirect_url)),
+		va_number = IF(VALUES(va_number) = '' AND VALUES(link_number) <= link_number, va_number, VALUES(va_number)),
+		qr_string = IF(VALUES(link_number) > link_number, VALUES(qr_string), COALESCE(VALUES(qr_string), qr_string)),
+		gateway_transaction_id = IF(VALUES(gateway_transaction_id) = '' AND VALUES(link_number) <= link_number, gateway_transaction_id,
+			VALUES(gateway_transaction_id)),
+		expires_at = COALESCE(VALUES(expires_at), expires_at), link_number = GREATEST(link_number, VALUES(link_number)),
+		updated_at = VALUES(updated_at)`
+
+	now := time.Now()
+	_, err := r.DB.Exec(query, payment.Token, payment.RedirectURL, payment.VANumber, payment.QRString, payment.GatewayTransactionID,
+		payment.LinkNumber, payment.ExpiresAt, now, now, payment.OrderID)
+	if err != nil {
+		r.logger.Error("Error when saving payment", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *transactionRepository) GetPaymentByOrderID(orderID string) (model.Payment, error) {
+	var payment model.Payment
+	var qrString sql.NullString
+	query := `SELECT payment_id, transaction_id, order_id, token, redirect_url, va_number, qr_string, gateway_transaction_id, link_number,
+		expires_at, created_at, updated_at FROM payment WHERE order_id = ?`
+
+	err := r.DB.QueryRow(query, orderID).Scan(&payment.PaymentID, &payment.TransactionID, &payment.OrderID, &payment.Token,
+		&payment.RedirectURL, &payment.VANumber, &qrString, &payment.GatewayTransactionID, &payment.LinkNumber, &payment.ExpiresAt,
+		&payment.CreatedAt, &payment.UpdatedAt)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			r.logger.Error("Error when scanning payment table", zap.Error(err))
+		}
+		return payment, err
+	}
+	payment.QRString = qrString.String
+
+	return payment, nil
+}
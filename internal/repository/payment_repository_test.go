@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	mock_config "github.com/SyamSolution/transaction-service/mock/config"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSavePayment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+
+	r := NewTransactionRepository(db, logger)
+
+	expiresAt := time.Now().Add(24 * time.Hour)
+	payment := model.Payment{OrderID: "order1", Token: "token1", RedirectURL: "https://example.com/snap/token1", ExpiresAt: &expiresAt}
+
+	mock.ExpectExec(`INSERT INTO payment \(.+\)\s+SELECT transaction_id, order_id, .+ FROM transaction WHERE order_id = \?\s+ON DUPLICATE KEY UPDATE`).
+		WithArgs("token1", "https://example.com/snap/token1", "", "", "", 0, &expiresAt, sqlmock.AnyArg(), sqlmock.AnyArg(), "order1").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	if err := r.SavePayment(payment); err != nil {
+		t.Errorf("error was not expected while saving payment: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestGetPaymentByOrderID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+
+	r := NewTransactionRepository(db, logger)
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"payment_id", "transaction_id", "order_id", "token", "redirect_url", "va_number", "qr_string",
+		"gateway_transaction_id", "link_number", "expires_at", "created_at", "updated_at"}).
+		AddRow(1, 7, "order1", "", "", "880012345678", nil, "midtrans-1", 1, now, now, now)
+
+	mock.ExpectQuery(`SELECT payment_id, .+ FROM payment WHERE order_id = \?`).
+		WithArgs("order1").
+		WillReturnRows(rows)
+
+	payment, err := r.GetPaymentByOrderID("order1")
+	if err != nil {
+		t.Errorf("error was not expected while getting payment: %s", err)
+	}
+	if payment.VANumber != "880012345678" || payment.QRString != "" || payment.GatewayTransactionID != "midtrans-1" ||
+		payment.LinkNumber != 1 || payment.ExpiresAt == nil {
+		t.Errorf("unexpected payment: %+v", payment)
+	}
+
+	mock.ExpectQuery(`SELECT payment_id, .+ FROM payment WHERE order_id = \?`).
+		WithArgs("order2").
+		WillReturnError(sql.ErrNoRows)
+
+	if _, err := r.GetPaymentByOrderID("order2"); err != sql.ErrNoRows {
+		t.Errorf("expected sql.ErrNoRows, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"go.uber.org/zap"
+)
+
+const paymentShareColumns = `payment_share_id, order_id, share_order_id, share_number, amount, status, token,
+		redirect_url, paid_at, created_at, updated_at`
+
+type paymentShareRepository struct {
+	DB     *sql.DB
+	logger config.Logger
+}
+
+type PaymentSharePersister interface {
+	CreatePaymentShares(shares []model.PaymentShare) error
+	UpdatePaymentShareLink(shareOrderID, token, redirectURL string) error
+	UpdatePaymentShareStatus(shareOrderID, status string) error
+	GetPaymentShareByShareOrderID(shareOrderID string) (model.PaymentShare, error)
+	GetPaymentSharesByOrderID(orderID string) ([]model.PaymentShare, error)
+	MarkPaymentSharePaid(shareOrderID string, paidAt time.Time) (bool, error)
+	GetOverdueSplitOrderIDs(now time.Time) ([]string, error)
+	GetRefundFailedPaymentShares() ([]model.PaymentShare, error)
+}
+
+func NewPaymentShareRepository(DB *sql.DB, logger config.Logger) PaymentSharePersister {
+	return &paymentShareRepository{DB: DB, logger: logger}
+}
+
+func scanPaymentShare(row interface{ Scan(dest ...any) error }) (model.PaymentShare, error) {
+	var share model.PaymentShare
+	err := row.Scan(&share.PaymentShareID, &share.OrderID, &share.ShareOrderID, &share.ShareNumber,
+		&share.Amount, &share.Status, &share.Token, &share.RedirectURL, &share.PaidAt, &share.CreatedAt, &share.UpdatedAt)
+
+	return share, err
+}
+
+func (r *paymentShareRepository) CreatePaymentShares(shares []model.PaymentShare) error {
+	query := `INSERT INTO payment_share (order_id, share_order_id, share_number, amount, status, created_at, updated_at)
+		VALUES (?,?,?,?,?,?,?)`
+
+	tx, err := r.DB.Begin()
+	if err != nil {
+		r.logger.Error("Error when begin transaction", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, share := range shares {
+		_, err = tx.Exec(query, share.OrderID, share.ShareOrderID, share.ShareNumber, share.Amount,
+			model.PaymentSharePending, share.CreatedAt, share.UpdatedAt)
+		if err != nil {
+			r.logger.Error("Error when inserting payment share", zap.Error(err))
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.logger.Error("Error when commit transaction", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *paymentShareRepository) UpdatePaymentShareLink(shareOrderID, token, redirectURL string) error {
+	query := `UPDATE payment_share SET token = ?, redirect_url = ?, updated_at = ? WHERE share_order_id = ?`
+
+	_, err := r.DB.Exec(query, token, redirectURL, time.Now(), shareOrderID)
+	if err != nil {
+		r.logger.Error("Error when updating payment share link", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *paymentShareRepository) UpdatePaymentShareStatus(shareOrderID, status string) error {
+	query := `UPDATE payment_share SET status = ?, updated_at = ? WHERE share_order_id = ?`
+
+	_, err := r.DB.Exec(query, status, time.Now(), shareOrderID)
+	if err != nil {
+		r.logger.Error("Error when updating payment share status", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *paymentShareRepository) GetPaymentShareByShareOrderID(shareOrderID string) (model.PaymentShare, error) {
+	query := `SELECT ` + paymentShareColumns + ` FROM payment_share WHERE share_order_id = ?`
+
+	share, err := scanPaymentShare(r.DB.QueryRow(query, shareOrderID))
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			r.logger.Error("Error when scanning payment share table", zap.Error(err))
+		}
+		return share, err
+	}
+
+	return share, nil
+}
+
+func (r *paymentShareRepository) GetPaymentSharesByOrderID(orderID string) ([]model.PaymentShare, error) {
+	var shares []model.PaymentShare
+	query := `SELECT ` + paymentShareColumns + ` FROM payment_share WHERE order_id = ? ORDER BY share_number`
+
+	rows, err := r.DB.Query(query, orderID)
+	if err != nil {
+		r.logger.Error("Error when querying payment share table", zap.Error(err))
+		return shares, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		share, err := scanPaymentShare(rows)
+		if err != nil {
+			r.logger.Error("Error when scanning payment share table", zap.Error(err))
+			return shares, err
+		}
+		shares = append(shares, share)
+	}
+
+	return shares, nil
+}
+
+// MarkPaymentSharePaid returns true only for the call that pays the last share of a pending order. The order
+// row is locked first so two shares settling at the same time can not both see the order as complete.
+func (r *paymentShareRepository) MarkPaymentSharePaid(shareOrderID string, paidAt time.Time) (bool, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		r.logger.Error("Error when begin transaction", zap.Error(err))
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var orderID, paymentStatus string
+	err = tx.QueryRow(`SELECT t.order_id, t.payment_status FROM transaction t
+		JOIN payment_share ps ON ps.order_id = t.order_id WHERE ps.share_order_id = ? FOR UPDATE`,
+		shareOrderID).Scan(&orderID, &paymentStatus)
+	if err != nil {
+		r.logger.Error("Error when locking transaction", zap.Error(err))
+		return false, err
+	}
+
+	result, err := tx.Exec(`UPDATE payment_share SET status = 'paid', paid_at = ?, updated_at = ? WHERE share_order_id = ?
+		AND status = 'pending'`, paidAt, paidAt, shareOrderID)
+	if err != nil {
+		r.logger.Error("Error when updating payment share", zap.Error(err))
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.Error("Error when getting affected rows", zap.Error(err))
+		return false, err
+	}
+
+	var unpaid int
+	err = tx.QueryRow(`SELECT COUNT(*) FROM payment_share WHERE order_id = ? AND status <> 'paid'`, orderID).Scan(&unpaid)
+	if err != nil {
+		r.logger.Error("Error when counting unpaid payment share", zap.Error(err))
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.logger.Error("Error when commit transaction", zap.Error(err))
+		return false, err
+	}
+
+	return affected > 0 && unpaid == 0 && paymentStatus == "pending", nil
+}
+
+// GetOverdueSplitOrderIDs lists split orders still pending after their deadline
+func (r *paymentShareRepository) GetOverdueSplitOrderIDs(now time.Time) ([]string, error) {
+	var orderIDs []string
+	query := `SELECT order_id FROM transaction WHERE payment_status = 'pending' AND split_count > 0 AND split_deadline <= ?`
+
+	rows, err := r.DB.Query(query, now)
+	if err != nil {
+		r.logger.Error("Error when querying overdue split transaction", zap.Error(err))
+		return orderIDs, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderID string
+		if err := rows.Scan(&orderID); err != nil {
+			r.logger.Error("Error when scanning overdue split transaction", zap.Error(err))
+			return orderIDs, err
+		}
+		orderIDs = append(orderIDs, orderID)
+	}
+
+	return orderIDs, nil
+}
+
+// GetRefundFailedPaymentShares lists paid shares whose refund has to be retried
+func (r *paymentShareRepository) GetRefundFailedPaymentShares() ([]model.PaymentShare, error) {
+	var shares []model.PaymentShare
+	query := `SELECT ` + paymentShareColumns + ` FROM payment_share WHERE status = 'refund_failed' ORDER BY updated_at`
+
+	rows, err := r.DB.Query(query)
+	if err != nil {
+		r.logger.Error("Error when querying payment share table", zap.Error(err))
+		return shares, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		share, err := scanPaymentShare(rows)
+		if err != nil {
+			r.logger.Error("Error when scanning payment share table", zap.Error(err))
+			return shares, err
+		}
+		shares = append(shares, share)
+	}
+
+	return shares, nil
+}
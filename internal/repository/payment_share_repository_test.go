@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	mock_config "github.com/SyamSolution/transaction-service/mock/config"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMarkPaymentSharePaid(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+
+	r := NewPaymentShareRepository(db, logger)
+
+	paidAt := time.Now()
+	tests := []struct {
+		name          string
+		paymentStatus string
+		affected      int64
+		unpaid        int
+		allPaid       bool
+	}{
+		{name: "last share", paymentStatus: "pending", affected: 1, unpaid: 0, allPaid: true},
+		{name: "other shares still open", paymentStatus: "pending", affected: 1, unpaid: 1, allPaid: false},
+		{name: "duplicate notification", paymentStatus: "pending", affected: 0, unpaid: 0, allPaid: false},
+		{name: "order already cancelled", paymentStatus: "cancelled", affected: 1, unpaid: 0, allPaid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectBegin()
+			mock.ExpectQuery(`SELECT t.order_id, t.payment_status FROM transaction t (.+) FOR UPDATE`).WithArgs("ORDER-1-S2").
+				WillReturnRows(sqlmock.NewRows([]string{"order_id", "payment_status"}).AddRow("ORDER-1", tt.paymentStatus))
+			mock.ExpectExec(`UPDATE payment_share SET status = 'paid'`).WithArgs(paidAt, paidAt, "ORDER-1-S2").
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+			mock.ExpectQuery(`SELECT COUNT\(\*\) FROM payment_share WHERE order_id = \?`).WithArgs("ORDER-1").
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(tt.unpaid))
+			mock.ExpectCommit()
+
+			allPaid, err := r.MarkPaymentSharePaid("ORDER-1-S2", paidAt)
+			if err != nil {
+				t.Errorf("error was not expected while marking payment share: %s", err)
+			}
+			if allPaid != tt.allPaid {
+				t.Errorf("expected all paid %v, got %v", tt.allPaid, allPaid)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
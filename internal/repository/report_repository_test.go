@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	mock_config "github.com/SyamSolution/transaction-service/mock/config"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetSalesReport(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+
+	r := NewReportRepository(db, logger)
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	rows := sqlmock.NewRows([]string{"group_key", "transactions", "tickets", "gross_amount", "net_amount"}).
+		AddRow("europe", 2, 3, 300, 270).
+		AddRow("asia", 3, 4, 400, 400).
+		AddRow(nil, 4, 7, 700, 670)
+
+	mock.ExpectQuery(`SELECT dt.continent AS group_key, COUNT\(DISTINCT t.transaction_id\), COALESCE\(SUM\(dt.quantity\), 0\),
+		.+ FROM transaction t JOIN detail_transaction dt ON dt.transaction_id = t.transaction_id
+		WHERE t.transaction_date >= \? AND t.transaction_date < \? AND t.payment_status = \? GROUP BY group_key WITH ROLLUP`).
+		WithArgs(from, to, "completed").
+		WillReturnRows(rows)
+
+	report, total, err := r.GetSalesReport(model.ReportGroupContinent, from, to, "completed")
+	if err != nil {
+		t.Errorf("error was not expected while getting sales report: %s", err)
+	}
+	if len(report) != 2 || report[0].Key != "asia" || report[1].Key != "europe" {
+		t.Errorf("unexpected rows: %+v", report)
+	}
+	if total.Transactions != 4 || total.NetAmount != 670 {
+		t.Errorf("unexpected total: %+v", total)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	if _, _, err := r.GetSalesReport("email", from, to, ""); err == nil {
+		t.Errorf("expected an error for an unknown group")
+	}
+}
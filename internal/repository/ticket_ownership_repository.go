@@ -0,0 +1,310 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"go.uber.org/zap"
+)
+
+const ticketOwnershipColumns = `ticket_ownership_id, detail_transaction_id, order_id, ticket_id, email, quantity, status,
+		transferred_from, event_date, expires_at, created_at, updated_at`
+
+type ticketOwnershipRepository struct {
+	DB     *sql.DB
+	logger config.Logger
+}
+
+type TicketOwnershipPersister interface {
+	GetTicketLine(detailTransactionID int) (model.TicketLine, error)
+	CreateTransfer(transfer model.TicketOwnership, line model.TicketLine) (int64, bool, error)
+	AcceptTransfer(transferID int, email string, now time.Time, newCode func() (string, error)) (model.TicketOwnership, bool, error)
+	CancelTransfer(transferID int, email string) (bool, error)
+	GetListTransferByEmail(email string) ([]model.TicketOwnership, error)
+}
+
+func NewTicketOwnershipRepository(DB *sql.DB, logger config.Logger) TicketOwnershipPersister {
+	return &ticketOwnershipRepository{DB: DB, logger: logger}
+}
+
+func scanTicketOwnership(row interface{ Scan(dest ...any) error }) (model.TicketOwnership, error) {
+	var ownership model.TicketOwnership
+	err := row.Scan(&ownership.TicketOwnershipID, &ownership.DetailTransactionID, &ownership.OrderID, &ownership.TicketID,
+		&ownership.Email, &ownership.Quantity, &ownership.Status, &ownership.TransferredFrom, &ownership.EventDate,
+		&ownership.ExpiresAt, &ownership.CreatedAt, &ownership.UpdatedAt)
+
+	return ownership, err
+}
+
+func (r *ticketOwnershipRepository) GetTicketLine(detailTransactionID int) (model.TicketLine, error) {
+	var line model.TicketLine
+	query := `SELECT dt.detail_transaction_id, dt.ticket_id, dt.quantity, t.order_id, t.email, t.payment_status
+		FROM detail_transaction dt JOIN transaction t ON t.transaction_id = dt.transaction_id WHERE dt.detail_transaction_id = ?`
+
+	err := r.DB.QueryRow(query, detailTransactionID).Scan(&line.DetailTransactionID, &line.TicketID, &line.Quantity,
+		&line.OrderID, &line.BuyerEmail, &line.PaymentStatus)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			r.logger.Error("Error when scanning detail transaction table", zap.Error(err))
+		}
+		return line, err
+	}
+
+	return line, nil
+}
+
+// CreateTransfer inserts a pending transfer when the sender still has enough tickets on the line, tickets
+// in other open transfers of the sender are not available. The first transfer of a line records the buyer
+// as owner of the whole line. The detail row is locked so transfers of one line run one at a time.
+func (r *ticketOwnershipRepository) CreateTransfer(transfer model.TicketOwnership, line model.TicketLine) (int64, bool, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		r.logger.Error("Error when begin transaction", zap.Error(err))
+		return 0, false, err
+	}
+	defer tx.Rollback()
+
+	var detailTransactionID int
+	err = tx.QueryRow(`SELECT detail_transaction_id FROM detail_transaction WHERE detail_transaction_id = ? FOR UPDATE`,
+		line.DetailTransactionID).Scan(&detailTransactionID)
+	if err != nil {
+		r.logger.Error("Error when locking detail transaction", zap.Error(err))
+		return 0, false, err
+	}
+
+	var count int
+	err = tx.QueryRow(`SELECT COUNT(*) FROM ticket_ownership WHERE detail_transaction_id = ?`, line.DetailTransactionID).Scan(&count)
+	if err != nil {
+		r.logger.Error("Error when counting ticket ownership", zap.Error(err))
+		return 0, false, err
+	}
+	if count == 0 {
+		_, err = tx.Exec(`INSERT INTO ticket_ownership (detail_transaction_id, order_id, ticket_id, email, quantity, status, created_at, updated_at)
+			VALUES (?,?,?,?,?,?,?,?)`, line.DetailTransactionID, line.OrderID, line.TicketID, line.BuyerEmail, line.Quantity,
+			model.OwnershipOwned, transfer.CreatedAt, transfer.UpdatedAt)
+		if err != nil {
+			r.logger.Error("Error when inserting ticket ownership", zap.Error(err))
+			return 0, false, err
+		}
+	}
+
+	var available int
+	err = tx.QueryRow(`SELECT COALESCE(SUM(CASE WHEN status = 'owned' AND email = ? THEN quantity
+			WHEN status = 'pending' AND transferred_from = ? AND expires_at > ? THEN -quantity ELSE 0 END), 0)
+		FROM ticket_ownership WHERE detail_transaction_id = ?`,
+		transfer.TransferredFrom, transfer.TransferredFrom, transfer.CreatedAt, line.DetailTransactionID).Scan(&available)
+	if err != nil {
+		r.logger.Error("Error when summing ticket ownership", zap.Error(err))
+		return 0, false, err
+	}
+	if available < transfer.Quantity {
+		return 0, false, nil
+	}
+
+	result, err := tx.Exec(`INSERT INTO ticket_ownership (detail_transaction_id, order_id, ticket_id, email, quantity, status,
+			transferred_from, event_date, expires_at, created_at, updated_at) VALUES (?,?,?,?,?,?,?,?,?,?,?)`,
+		transfer.DetailTransactionID, transfer.OrderID, transfer.TicketID, transfer.Email, transfer.Quantity, model.OwnershipPending,
+		transfer.TransferredFrom, transfer.EventDate, transfer.ExpiresAt, transfer.CreatedAt, transfer.UpdatedAt)
+	if err != nil {
+		r.logger.Error("Error when inserting ticket transfer", zap.Error(err))
+		return 0, false, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		r.logger.Error("Error when getting last insert id", zap.Error(err))
+		return 0, false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.logger.Error("Error when commit transaction", zap.Error(err))
+		return 0, false, err
+	}
+
+	return id, true, nil
+}
+
+// AcceptTransfer moves the tickets of an open transfer from the sender to the recipient. It returns false
+// when the transfer is not pending, belongs to someone else or has expired. The issued units that move get
+// a new code from newCode, so the codes the sender has seen stop working.
+func (r *ticketOwnershipRepository) AcceptTransfer(transferID int, email string, now time.Time,
+	newCode func() (string, error)) (model.TicketOwnership, bool, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		r.logger.Error("Error when begin transaction", zap.Error(err))
+		return model.TicketOwnership{}, false, err
+	}
+	defer tx.Rollback()
+
+	var detailTransactionID int
+	var paymentStatus string
+	err = tx.QueryRow(`SELECT dt.detail_transaction_id, t.payment_status FROM detail_transaction dt
+		JOIN transaction t ON t.transaction_id = dt.transaction_id
+		JOIN ticket_ownership o ON o.detail_transaction_id = dt.detail_transaction_id WHERE o.ticket_ownership_id = ? FOR UPDATE`,
+		transferID).Scan(&detailTransactionID, &paymentStatus)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.TicketOwnership{}, false, nil
+		}
+		r.logger.Error("Error when locking detail transaction", zap.Error(err))
+		return model.TicketOwnership{}, false, err
+	}
+
+	transfer, err := scanTicketOwnership(tx.QueryRow(`SELECT `+ticketOwnershipColumns+` FROM ticket_ownership WHERE ticket_ownership_id = ?`, transferID))
+	if err != nil {
+		r.logger.Error("Error when scanning ticket ownership table", zap.Error(err))
+		return transfer, false, err
+	}
+	if transfer.Status != model.OwnershipPending || !strings.EqualFold(transfer.Email, email) || transfer.ExpiresAt == nil || !transfer.ExpiresAt.After(now) {
+		return transfer, false, nil
+	}
+	// order yang dibatalkan atau di-refund setelah transfer dibuat tidak boleh berpindah tangan
+	if paymentStatus != "completed" {
+		return transfer, false, ErrTransferOrderNotCompleted
+	}
+
+	rows, err := tx.Query(`SELECT ticket_ownership_id, quantity FROM ticket_ownership WHERE detail_transaction_id = ? AND email = ?
+		AND status = 'owned' AND quantity > 0 ORDER BY ticket_ownership_id`, detailTransactionID, transfer.TransferredFrom)
+	if err != nil {
+		r.logger.Error("Error when querying ticket ownership table", zap.Error(err))
+		return transfer, false, err
+	}
+	owned := make(map[int]int)
+	var ownedIDs []int
+	for rows.Next() {
+		var id, quantity int
+		if err := rows.Scan(&id, &quantity); err != nil {
+			rows.Close()
+			r.logger.Error("Error when scanning ticket ownership table", zap.Error(err))
+			return transfer, false, err
+		}
+		owned[id] = quantity
+		ownedIDs = append(ownedIDs, id)
+	}
+	rows.Close()
+
+	// kurangi ticket pengirim dari baris paling lama
+	remaining := transfer.Quantity
+	for _, id := range ownedIDs {
+		if remaining == 0 {
+			break
+		}
+		take := min(owned[id], remaining)
+		_, err = tx.Exec(`UPDATE ticket_ownership SET quantity = quantity - ?, updated_at = ? WHERE ticket_ownership_id = ?`, take, now, id)
+		if err != nil {
+			r.logger.Error("Error when updating ticket ownership", zap.Error(err))
+			return transfer, false, err
+		}
+		remaining -= take
+	}
+	if remaining > 0 {
+		return transfer, false, nil
+	}
+
+	if err = r.rotateIssuedTickets(tx, detailTransactionID, transfer, now, newCode); err != nil {
+		return transfer, false, err
+	}
+
+	_, err = tx.Exec(`UPDATE ticket_ownership SET status = 'owned', updated_at = ? WHERE ticket_ownership_id = ?`, now, transferID)
+	if err != nil {
+		r.logger.Error("Error when accepting ticket transfer", zap.Error(err))
+		return transfer, false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.logger.Error("Error when commit transaction", zap.Error(err))
+		return transfer, false, err
+	}
+	transfer.Status = model.OwnershipOwned
+	transfer.UpdatedAt = now
+
+	return transfer, true, nil
+}
+
+// rotateIssuedTickets gives the oldest unused units of the sender on the line to the recipient under new codes
+func (r *ticketOwnershipRepository) rotateIssuedTickets(tx *sql.Tx, detailTransactionID int, transfer model.TicketOwnership,
+	now time.Time, newCode func() (string, error)) error {
+	rows, err := tx.Query(`SELECT issued_ticket_id FROM issued_ticket WHERE detail_transaction_id = ? AND owner_email = ?
+		AND status = 'issued' ORDER BY unit_number LIMIT ? FOR UPDATE`, detailTransactionID, transfer.TransferredFrom, transfer.Quantity)
+	if err != nil {
+		r.logger.Error("Error when querying issued ticket table", zap.Error(err))
+		return err
+	}
+	var issuedTicketIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			r.logger.Error("Error when scanning issued ticket table", zap.Error(err))
+			return err
+		}
+		issuedTicketIDs = append(issuedTicketIDs, id)
+	}
+	rows.Close()
+	if len(issuedTicketIDs) < transfer.Quantity {
+		return ErrTransferTicketsUnavailable
+	}
+
+	for _, id := range issuedTicketIDs {
+		code, err := newCode()
+		if err != nil {
+			r.logger.Error("Error when generating ticket code", zap.Error(err))
+			return err
+		}
+		_, err = tx.Exec(`UPDATE issued_ticket SET code = ?, owner_email = ?, holder_name = ?, updated_at = ? WHERE issued_ticket_id = ?`,
+			code, transfer.Email, transfer.Email, now, id)
+		if err != nil {
+			r.logger.Error("Error when transferring issued ticket", zap.Error(err))
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *ticketOwnershipRepository) CancelTransfer(transferID int, email string) (bool, error) {
+	query := `UPDATE ticket_ownership SET status = 'cancelled', updated_at = ? WHERE ticket_ownership_id = ? AND transferred_from = ?
+		AND status = 'pending'`
+
+	result, err := r.DB.Exec(query, time.Now(), transferID, email)
+	if err != nil {
+		r.logger.Error("Error when cancelling ticket transfer", zap.Error(err))
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.Error("Error when getting affected rows", zap.Error(err))
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// GetListTransferByEmail lists transfers sent or received by email
+func (r *ticketOwnershipRepository) GetListTransferByEmail(email string) ([]model.TicketOwnership, error) {
+	var transfers []model.TicketOwnership
+	query := `SELECT ` + ticketOwnershipColumns + ` FROM ticket_ownership WHERE transferred_from <> '' AND (email = ? OR transferred_from = ?)
+		ORDER BY created_at DESC, ticket_ownership_id DESC`
+
+	rows, err := r.DB.Query(query, email, email)
+	if err != nil {
+		r.logger.Error("Error when querying ticket ownership table", zap.Error(err))
+		return transfers, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		transfer, err := scanTicketOwnership(rows)
+		if err != nil {
+			r.logger.Error("Error when scanning ticket ownership table", zap.Error(err))
+			return transfers, err
+		}
+		transfers = append(transfers, transfer)
+	}
+
+	return transfers, nil
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	mock_config "github.com/SyamSolution/transaction-service/mock/config"
+	"go.uber.org/mock/gomock"
+)
+
+var ticketOwnershipRowColumns = []string{"ticket_ownership_id", "detail_transaction_id", "order_id", "ticket_id", "email", "quantity",
+	"status", "transferred_from", "event_date", "expires_at", "created_at", "updated_at"}
+
+func TestCreateTransfer(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+
+	r := NewTicketOwnershipRepository(db, logger)
+
+	now := time.Now()
+	expiresAt := now.Add(time.Hour)
+	line := model.TicketLine{DetailTransactionID: 7, TicketID: 3, Quantity: 4, OrderID: "ORDER-1", BuyerEmail: "buyer@example.com"}
+	transfer := model.TicketOwnership{DetailTransactionID: 7, OrderID: "ORDER-1", TicketID: 3, Email: "friend@example.com", Quantity: 2,
+		TransferredFrom: "buyer@example.com", ExpiresAt: &expiresAt, CreatedAt: now, UpdatedAt: now}
+
+	t.Run("first transfer records the buyer as owner", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT detail_transaction_id FROM detail_transaction WHERE detail_transaction_id = \? FOR UPDATE`).
+			WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"detail_transaction_id"}).AddRow(7))
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM ticket_ownership`).WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec("INSERT INTO ticket_ownership").
+			WithArgs(7, "ORDER-1", 3, "buyer@example.com", 4, model.OwnershipOwned, now, now).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectQuery(`SELECT COALESCE\(SUM`).WithArgs("buyer@example.com", "buyer@example.com", now, 7).
+			WillReturnRows(sqlmock.NewRows([]string{"available"}).AddRow(4))
+		mock.ExpectExec("INSERT INTO ticket_ownership").
+			WithArgs(7, "ORDER-1", 3, "friend@example.com", 2, model.OwnershipPending, "buyer@example.com", nil, &expiresAt, now, now).
+			WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectCommit()
+
+		id, created, err := r.CreateTransfer(transfer, line)
+		if err != nil || !created || id != 2 {
+			t.Errorf("unexpected result %d %v %v", id, created, err)
+		}
+	})
+
+	t.Run("tickets in open transfers are not available", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT detail_transaction_id FROM detail_transaction`).
+			WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"detail_transaction_id"}).AddRow(7))
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM ticket_ownership`).WithArgs(7).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectQuery(`SELECT COALESCE\(SUM`).WithArgs("buyer@example.com", "buyer@example.com", now, 7).
+			WillReturnRows(sqlmock.NewRows([]string{"available"}).AddRow(1))
+		mock.ExpectRollback()
+
+		_, created, err := r.CreateTransfer(transfer, line)
+		if err != nil || created {
+			t.Errorf("transfer should not be created, got %v %v", created, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAcceptTransfer(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+
+	r := NewTicketOwnershipRepository(db, logger)
+
+	now := time.Now()
+	expiresAt := now.Add(time.Hour)
+
+	t.Run("sender rows are reduced oldest first", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT dt.detail_transaction_id, t.payment_status FROM detail_transaction dt`).WithArgs(5).
+			WillReturnRows(sqlmock.NewRows([]string{"detail_transaction_id", "payment_status"}).AddRow(7, "completed"))
+		mock.ExpectQuery(`FROM ticket_ownership WHERE ticket_ownership_id = \?`).WithArgs(5).
+			WillReturnRows(sqlmock.NewRows(ticketOwnershipRowColumns).AddRow(5, 7, "ORDER-1", 3, "friend@example.com", 3,
+				model.OwnershipPending, "buyer@example.com", nil, expiresAt, now, now))
+		mock.ExpectQuery(`SELECT ticket_ownership_id, quantity FROM ticket_ownership`).WithArgs(7, "buyer@example.com").
+			WillReturnRows(sqlmock.NewRows([]string{"ticket_ownership_id", "quantity"}).AddRow(1, 2).AddRow(4, 5))
+		mock.ExpectExec(`UPDATE ticket_ownership SET quantity = quantity - \?`).WithArgs(2, now, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE ticket_ownership SET quantity = quantity - \?`).WithArgs(1, now, 4).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`SELECT issued_ticket_id FROM issued_ticket WHERE detail_transaction_id = \? AND owner_email = \?`).
+			WithArgs(7, "buyer@example.com", 3).
+			WillReturnRows(sqlmock.NewRows([]string{"issued_ticket_id"}).AddRow(11).AddRow(12).AddRow(13))
+		for i, id := range []int{11, 12, 13} {
+			mock.ExpectExec(`UPDATE issued_ticket SET code = \?, owner_email = \?`).
+				WithArgs(fmt.Sprintf("CODE-%d", i+1), "friend@example.com", "friend@example.com", now, id).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+		mock.ExpectExec(`UPDATE ticket_ownership SET status = 'owned'`).WithArgs(now, 5).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		var generated int
+		newCode := func() (string, error) {
+			generated++
+			return fmt.Sprintf("CODE-%d", generated), nil
+		}
+		transfer, accepted, err := r.AcceptTransfer(5, "Friend@example.com", now, newCode)
+		if err != nil || !accepted || transfer.Status != model.OwnershipOwned {
+			t.Errorf("unexpected result %+v %v %v", transfer, accepted, err)
+		}
+	})
+
+	t.Run("expired transfer", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT dt.detail_transaction_id, t.payment_status FROM detail_transaction dt`).WithArgs(6).
+			WillReturnRows(sqlmock.NewRows([]string{"detail_transaction_id", "payment_status"}).AddRow(7, "completed"))
+		mock.ExpectQuery(`FROM ticket_ownership WHERE ticket_ownership_id = \?`).WithArgs(6).
+			WillReturnRows(sqlmock.NewRows(ticketOwnershipRowColumns).AddRow(6, 7, "ORDER-1", 3, "friend@example.com", 1,
+				model.OwnershipPending, "buyer@example.com", nil, now.Add(-time.Minute), now, now))
+		mock.ExpectRollback()
+
+		_, accepted, err := r.AcceptTransfer(6, "friend@example.com", now, nil)
+		if err != nil || accepted {
+			t.Errorf("transfer should not be accepted, got %v %v", accepted, err)
+		}
+	})
+
+	t.Run("order refunded after the transfer was offered", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT dt.detail_transaction_id, t.payment_status FROM detail_transaction dt`).WithArgs(8).
+			WillReturnRows(sqlmock.NewRows([]string{"detail_transaction_id", "payment_status"}).AddRow(7, "refunded"))
+		mock.ExpectQuery(`FROM ticket_ownership WHERE ticket_ownership_id = \?`).WithArgs(8).
+			WillReturnRows(sqlmock.NewRows(ticketOwnershipRowColumns).AddRow(8, 7, "ORDER-1", 3, "friend@example.com", 1,
+				model.OwnershipPending, "buyer@example.com", nil, expiresAt, now, now))
+		mock.ExpectRollback()
+
+		_, accepted, err := r.AcceptTransfer(8, "friend@example.com", now, nil)
+		if !errors.Is(err, ErrTransferOrderNotCompleted) || accepted {
+			t.Errorf("transfer should be refused, got %v %v", accepted, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCancelTransfer(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+
+	r := NewTicketOwnershipRepository(db, logger)
+
+	mock.ExpectExec(`UPDATE ticket_ownership SET status = 'cancelled'`).WithArgs(sqlmock.AnyArg(), 5, "buyer@example.com").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	cancelled, err := r.CancelTransfer(5, "buyer@example.com")
+	if err != nil || cancelled {
+		t.Errorf("transfer should not be cancelled, got %v %v", cancelled, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
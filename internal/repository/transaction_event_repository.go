@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"go.uber.org/zap"
+)
+
+// insertTransactionEvent is called inside the transaction that changes the status, so the history never misses
+// a change nor records one that was rolled back
+func insertTransactionEvent(tx interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}, event model.TransactionEvent) error {
+	query := `INSERT INTO transaction_event (transaction_id, order_id, actor, actor_id, previous_status, new_status, payload_reference,
+		note, created_at) VALUES (?,?,?,?,?,?,?,?,?)`
+
+	_, err := tx.Exec(query, event.TransactionID, event.OrderID, event.Actor, event.ActorID, event.PreviousStatus, event.NewStatus,
+		event.PayloadReference, event.Note, time.Now())
+
+	return err
+}
+
+// CreateTransactionEvent records an action that did not change the status itself, e.g. an admin resending a message
+func (r *transactionRepository) CreateTransactionEvent(event model.TransactionEvent) error {
+	if err := insertTransactionEvent(r.DB, event); err != nil {
+		r.logger.Error("Error when inserting transaction event", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *transactionRepository) GetTransactionEvents(transactionID int) ([]model.TransactionEvent, error) {
+	query := `SELECT transaction_event_id, transaction_id, order_id, actor, actor_id, previous_status, new_status, payload_reference,
+		note, created_at FROM transaction_event WHERE transaction_id = ? ORDER BY transaction_event_id`
+
+	rows, err := r.DB.Query(query, transactionID)
+	if err != nil {
+		r.logger.Error("Error when querying transaction event table", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []model.TransactionEvent{}
+	for rows.Next() {
+		var event model.TransactionEvent
+		if err := rows.Scan(&event.TransactionEventID, &event.TransactionID, &event.OrderID, &event.Actor, &event.ActorID,
+			&event.PreviousStatus, &event.NewStatus, &event.PayloadReference, &event.Note, &event.CreatedAt); err != nil {
+			r.logger.Error("Error when scanning transaction event table", zap.Error(err))
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Error("Error when iterating transaction event rows", zap.Error(err))
+		return nil, err
+	}
+
+	return events, nil
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	mock_config "github.com/SyamSolution/transaction-service/mock/config"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetTransactionEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+
+	r := NewTransactionRepository(db, logger)
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"transaction_event_id", "transaction_id", "order_id", "actor", "actor_id", "previous_status",
+		"new_status", "payload_reference", "note", "created_at"}).
+		AddRow(1, 7, "order1", model.TransactionActorUser, "test@example.com", "", "pending", "", "", now).
+		AddRow(2, 7, "order1", model.TransactionActorWebhook, "", "pending", "completed", "midtrans-1", "midtrans settlement", now)
+
+	mock.ExpectQuery(`SELECT transaction_event_id, .+ FROM transaction_event WHERE transaction_id = \? ORDER BY transaction_event_id`).
+		WithArgs(7).
+		WillReturnRows(rows)
+
+	events, err := r.GetTransactionEvents(7)
+	if err != nil {
+		t.Errorf("error was not expected while getting transaction events: %s", err)
+	}
+	if len(events) != 2 || events[1].PreviousStatus != "pending" || events[1].PayloadReference != "midtrans-1" {
+		t.Errorf("unexpected events: %+v", events)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -2,11 +2,17 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
+	"time"
+
 	"github.com/SyamSolution/transaction-service/config"
 	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/go-sql-driver/mysql"
 	"go.uber.org/zap"
 )
 
+const mysqlErrDuplicateEntry = 1062
+
 type transactionRepository struct {
 	DB     *sql.DB
 	logger config.Logger
@@ -18,8 +24,15 @@ type TransactionPersister interface {
 	GetTransactionByOrderID(orderID string) (model.Transaction, error)
 	GetDetailTransactionByTransactionID(transactionID int) ([]model.DetailTransaction, error)
 	GetListTransaction(request model.TransactionListRequest) ([]model.Transaction, error)
-	UpdateTransactionStatus(orderID string, status string) error
+	GetTransactionsByDateRange(from, to time.Time, afterID, limit int) ([]model.Transaction, error)
+	GetTransactionExport(email string, from, to time.Time) ([]model.TransactionExport, error)
+	UpdateTransactionStatus(orderID string, status string, event model.TransactionEvent) (bool, error)
+	CancelTransaction(orderID string, event model.TransactionEvent) (bool, error)
 	GetDistinctContinentTransaction(email string) ([]string, error)
+	GetTransactionEvents(transactionID int) ([]model.TransactionEvent, error)
+	CreateTransactionEvent(event model.TransactionEvent) error
+	SavePayment(payment model.Payment) error
+	GetPaymentByOrderID(orderID string) (model.Payment, error)
 }
 
 func NewTransactionRepository(DB *sql.DB, logger config.Logger) TransactionPersister {
@@ -27,12 +40,16 @@ func NewTransactionRepository(DB *sql.DB, logger config.Logger) TransactionPersi
 }
 
 func (r *transactionRepository) CreateTransaction(transaction model.Transaction, detailTransaction []model.DetailTransaction) error {
-	query := `INSERT INTO transaction (user_id, order_id, transaction_date, payment_method, total_amount, total_ticket, full_name, 
-    		mobile_number, email, payment_status, continent, discount, created_at, updated_at) 
-		VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?)`
+	query := `INSERT INTO transaction (user_id, order_id, transaction_date, payment_method, total_amount, gross_amount, total_ticket, full_name, 
+    		mobile_number, email, payment_status, continent, discount, discount_trace, voucher_code, voucher_discount, split_count, split_deadline,
+    		created_at, updated_at) 
+		VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`
 
-	query2 := `INSERT INTO detail_transaction (transaction_id, ticket_id, ticket_type, country_name, city, quantity, created_at, updated_at)
-    		VALUES (?,?,?,?,?,?,?,?)`
+	query2 := `INSERT INTO detail_transaction (transaction_id, ticket_id, ticket_type, continent, country_name, city, quantity, price, net_amount,
+    		created_at, updated_at) VALUES (?,?,?,?,?,?,?,?,?,?,?)`
+
+	query3 := `INSERT INTO ticket_holder (detail_transaction_id, full_name, email, id_number, created_at, updated_at)
+			VALUES (?,?,?,?,?,?)`
 
 	tx, err := r.DB.Begin()
 	if err != nil {
@@ -41,19 +58,40 @@ func (r *transactionRepository) CreateTransaction(transaction model.Transaction,
 	}
 
 	result, err := tx.Exec(query, transaction.UserID, transaction.OrderID, transaction.TransactionDate, transaction.PaymentMethod, transaction.TotalAmount,
-		transaction.TotalTicket, transaction.FullName, transaction.MobileNumber, transaction.Email, transaction.PaymentStatus, transaction.Continent,
-		transaction.Discount, transaction.CreatedAt, transaction.UpdatedAt)
+		transaction.GrossAmount, transaction.TotalTicket, transaction.FullName, transaction.MobileNumber, transaction.Email, transaction.PaymentStatus, transaction.Continent,
+		transaction.Discount, transaction.DiscountTrace, transaction.VoucherCode, transaction.VoucherDiscount, transaction.SplitCount, transaction.SplitDeadline,
+		transaction.CreatedAt, transaction.UpdatedAt)
 	if err != nil {
 		r.logger.Error("Error when inserting transaction", zap.Error(err))
 		if err := tx.Rollback(); err != nil {
 			r.logger.Error("Error when rolling back transaction", zap.Error(err))
 		}
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry {
+			return ErrDuplicateOrderID
+		}
 		return err
 	}
 	idResult, _ := result.LastInsertId()
 
+	err = insertTransactionEvent(tx, model.TransactionEvent{
+		TransactionID: int(idResult),
+		OrderID:       transaction.OrderID,
+		Actor:         model.TransactionActorUser,
+		ActorID:       transaction.Email,
+		NewStatus:     transaction.PaymentStatus,
+	})
+	if err != nil {
+		r.logger.Error("Error when inserting transaction event", zap.Error(err))
+		if err := tx.Rollback(); err != nil {
+			r.logger.Error("Error when rolling back transaction", zap.Error(err))
+		}
+		return err
+	}
+
 	for _, dt := range detailTransaction {
-		_, err = tx.Exec(query2, idResult, dt.TicketID, dt.TicketType, dt.CountryName, dt.City, dt.Quantity, dt.CreatedAt, dt.UpdatedAt)
+		detailResult, err := tx.Exec(query2, idResult, dt.TicketID, dt.TicketType, dt.Continent, dt.CountryName, dt.City, dt.Quantity, dt.Price,
+			dt.NetAmount, dt.CreatedAt, dt.UpdatedAt)
 		if err != nil {
 			r.logger.Error("Error when inserting detail transaction", zap.Error(err))
 			if err := tx.Rollback(); err != nil {
@@ -61,6 +99,19 @@ func (r *transactionRepository) CreateTransaction(transaction model.Transaction,
 			}
 			return err
 		}
+
+		if dt.Holder == nil {
+			continue
+		}
+		detailID, _ := detailResult.LastInsertId()
+		_, err = tx.Exec(query3, detailID, dt.Holder.FullName, dt.Holder.Email, dt.Holder.IDNumber, dt.CreatedAt, dt.UpdatedAt)
+		if err != nil {
+			r.logger.Error("Error when inserting ticket holder", zap.Error(err))
+			if err := tx.Rollback(); err != nil {
+				r.logger.Error("Error when rolling back transaction", zap.Error(err))
+			}
+			return err
+		}
 	}
 
 	err = tx.Commit()
@@ -75,11 +126,12 @@ func (r *transactionRepository) CreateTransaction(transaction model.Transaction,
 func (r *transactionRepository) GetTransactionByTransactionID(transactionID int, email string) (model.Transaction, error) {
 	var transactions model.Transaction
 	query := `SELECT transaction_id, user_id, order_id, transaction_date, payment_method, total_amount, total_ticket, full_name,
-		mobile_number, email, payment_status, continent, created_at, updated_at FROM transaction WHERE transaction_id = ? AND email = ?`
+		mobile_number, email, payment_status, continent, split_count, split_deadline, created_at, updated_at FROM transaction WHERE transaction_id = ? AND email = ?`
 
 	err := r.DB.QueryRow(query, transactionID, email).Scan(&transactions.TransactionID, &transactions.UserID, &transactions.OrderID, &transactions.TransactionDate,
 		&transactions.PaymentMethod, &transactions.TotalAmount, &transactions.TotalTicket, &transactions.FullName, &transactions.MobileNumber,
-		&transactions.Email, &transactions.PaymentStatus, &transactions.Continent, &transactions.CreatedAt, &transactions.UpdatedAt)
+		&transactions.Email, &transactions.PaymentStatus, &transactions.Continent, &transactions.SplitCount, &transactions.SplitDeadline,
+		&transactions.CreatedAt, &transactions.UpdatedAt)
 	if err != nil {
 		r.logger.Error("Error when scanning transaction table", zap.Error(err))
 		return transactions, err
@@ -91,11 +143,14 @@ func (r *transactionRepository) GetTransactionByTransactionID(transactionID int,
 func (r *transactionRepository) GetTransactionByOrderID(orderID string) (model.Transaction, error) {
 	var transactions model.Transaction
 	query := `SELECT transaction_id, user_id, order_id, transaction_date, payment_method, total_amount, total_ticket, full_name,
-		mobile_number, email, payment_status, continent, created_at, updated_at FROM transaction WHERE order_id = ?`
+		mobile_number, email, payment_status, continent, split_count, split_deadline, gross_amount, COALESCE(discount, 0), voucher_code,
+		voucher_discount, created_at, updated_at FROM transaction WHERE order_id = ?`
 
 	err := r.DB.QueryRow(query, orderID).Scan(&transactions.TransactionID, &transactions.UserID, &transactions.OrderID, &transactions.TransactionDate,
 		&transactions.PaymentMethod, &transactions.TotalAmount, &transactions.TotalTicket, &transactions.FullName, &transactions.MobileNumber,
-		&transactions.Email, &transactions.PaymentStatus, &transactions.Continent, &transactions.CreatedAt, &transactions.UpdatedAt)
+		&transactions.Email, &transactions.PaymentStatus, &transactions.Continent, &transactions.SplitCount, &transactions.SplitDeadline,
+		&transactions.GrossAmount, &transactions.Discount, &transactions.VoucherCode, &transactions.VoucherDiscount,
+		&transactions.CreatedAt, &transactions.UpdatedAt)
 	if err != nil {
 		r.logger.Error("Error when scanning transaction table", zap.Error(err))
 		return transactions, err
@@ -106,8 +161,10 @@ func (r *transactionRepository) GetTransactionByOrderID(orderID string) (model.T
 
 func (r *transactionRepository) GetDetailTransactionByTransactionID(transactionID int) ([]model.DetailTransaction, error) {
 	var detailTransactions []model.DetailTransaction
-	query := `SELECT detail_transaction_id, transaction_id, ticket_id, ticket_type, country_name, city, quantity, created_at, updated_at
-		FROM detail_transaction WHERE transaction_id = ?`
+	query := `SELECT dt.detail_transaction_id, dt.transaction_id, dt.ticket_id, dt.ticket_type, dt.continent, dt.country_name, dt.city,
+		dt.quantity, dt.price, dt.net_amount, dt.created_at, dt.updated_at, th.full_name, th.email, th.id_number
+		FROM detail_transaction dt LEFT JOIN ticket_holder th ON th.detail_transaction_id = dt.detail_transaction_id
+		WHERE dt.transaction_id = ?`
 
 	rows, err := r.DB.Query(query, transactionID)
 	if err != nil {
@@ -118,13 +175,21 @@ func (r *transactionRepository) GetDetailTransactionByTransactionID(transactionI
 
 	for rows.Next() {
 		var detailTransaction model.DetailTransaction
+		var holderName, holderEmail, holderIDNumber sql.NullString
 		err := rows.Scan(&detailTransaction.DetailTransactionID, &detailTransaction.TransactionID, &detailTransaction.TicketID,
-			&detailTransaction.TicketType, &detailTransaction.CountryName, &detailTransaction.City, &detailTransaction.Quantity,
-			&detailTransaction.CreatedAt, &detailTransaction.UpdatedAt)
+			&detailTransaction.TicketType, &detailTransaction.Continent, &detailTransaction.CountryName, &detailTransaction.City, &detailTransaction.Quantity,
+			&detailTransaction.Price, &detailTransaction.NetAmount, &detailTransaction.CreatedAt, &detailTransaction.UpdatedAt, &holderName, &holderEmail, &holderIDNumber)
 		if err != nil {
 			r.logger.Error("Error when scanning detail transaction table", zap.Error(err))
 			return detailTransactions, err
 		}
+		if holderName.Valid {
+			detailTransaction.Holder = &model.TicketHolder{
+				FullName: holderName.String,
+				Email:    holderEmail.String,
+				IDNumber: holderIDNumber.String,
+			}
+		}
 		detailTransactions = append(detailTransactions, detailTransaction)
 	}
 
@@ -169,21 +234,197 @@ func (r *transactionRepository) GetListTransaction(request model.TransactionList
 	return transactions, nil
 }
 
-func (r *transactionRepository) UpdateTransactionStatus(orderID string, status string) error {
-	query := `UPDATE transaction SET payment_status = ? WHERE order_id = ?`
+// GetTransactionsByDateRange pages through [from, to) by transaction_id, pass the last id of a page to get the next
+func (r *transactionRepository) GetTransactionsByDateRange(from, to time.Time, afterID, limit int) ([]model.Transaction, error) {
+	var transactions []model.Transaction
+	query := `SELECT transaction_id, order_id, transaction_date, total_amount, gross_amount, email, payment_status, split_count
+		FROM transaction WHERE transaction_date >= ? AND transaction_date < ? AND transaction_id > ? ORDER BY transaction_id LIMIT ?`
+
+	rows, err := r.DB.Query(query, from, to, afterID, limit)
+	if err != nil {
+		r.logger.Error("Error when querying transaction table", zap.Error(err))
+		return transactions, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var transaction model.Transaction
+		err := rows.Scan(&transaction.TransactionID, &transaction.OrderID, &transaction.TransactionDate, &transaction.TotalAmount,
+			&transaction.GrossAmount, &transaction.Email, &transaction.PaymentStatus, &transaction.SplitCount)
+		if err != nil {
+			r.logger.Error("Error when scanning transaction table", zap.Error(err))
+			return transactions, err
+		}
+		transactions = append(transactions, transaction)
+	}
 
-	_, err := r.DB.Exec(query, status, orderID)
+	return transactions, nil
+}
+
+// GetTransactionExport returns the user's orders made in [from, to) with their lines, oldest first
+func (r *transactionRepository) GetTransactionExport(email string, from, to time.Time) ([]model.TransactionExport, error) {
+	var exports []model.TransactionExport
+	// kolom lama masih nullable
+	query := `SELECT t.transaction_id, t.order_id, t.transaction_date, COALESCE(t.payment_method, ''), COALESCE(t.payment_status, ''),
+		COALESCE(t.discount, 0), t.voucher_code, t.voucher_discount, COALESCE(t.total_amount, 0), t.gross_amount,
+		COALESCE(dt.ticket_type, ''), dt.continent, COALESCE(dt.country_name, ''), COALESCE(dt.city, ''), COALESCE(dt.quantity, 0),
+		dt.price, dt.net_amount FROM transaction t JOIN detail_transaction dt ON dt.transaction_id = t.transaction_id
+		WHERE t.email = ? AND t.transaction_date >= ? AND t.transaction_date < ?
+		ORDER BY t.transaction_date, t.transaction_id, dt.detail_transaction_id`
+
+	rows, err := r.DB.Query(query, email, from, to)
+	if err != nil {
+		r.logger.Error("Error when querying transaction table", zap.Error(err))
+		return exports, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var export model.TransactionExport
+		var line model.TransactionExportLine
+		err := rows.Scan(&export.TransactionID, &export.OrderID, &export.TransactionDate, &export.PaymentMethod, &export.PaymentStatus,
+			&export.Discount, &export.VoucherCode, &export.VoucherDiscount, &export.TotalAmount, &export.TotalIDR, &line.TicketType,
+			&line.Continent, &line.CountryName, &line.City, &line.Quantity, &line.Price, &line.NetAmount)
+		if err != nil {
+			r.logger.Error("Error when scanning transaction table", zap.Error(err))
+			return exports, err
+		}
+
+		// baris berurutan per order, line berikutnya masuk ke order terakhir
+		if n := len(exports); n > 0 && exports[n-1].TransactionID == export.TransactionID {
+			exports[n-1].Lines = append(exports[n-1].Lines, line)
+			continue
+		}
+		export.Lines = []model.TransactionExportLine{line}
+		exports = append(exports, export)
+	}
+
+	return exports, nil
+}
+
+// UpdateTransactionStatus records who changed the status in the same transaction, writing the same status again
+// is not a change and is skipped. The bool tells whether the status really changed.
+func (r *transactionRepository) UpdateTransactionStatus(orderID string, status string, event model.TransactionEvent) (bool, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		r.logger.Error("Error when begin transaction", zap.Error(err))
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var transactionID int
+	var previousStatus string
+	err = tx.QueryRow(`SELECT transaction_id, payment_status FROM transaction WHERE order_id = ? FOR UPDATE`, orderID).
+		Scan(&transactionID, &previousStatus)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		r.logger.Error("Error when locking transaction", zap.Error(err))
+		return false, err
+	}
+	if previousStatus == status {
+		return false, nil
+	}
+
+	_, err = tx.Exec(`UPDATE transaction SET payment_status = ?, updated_at = ? WHERE transaction_id = ?`, status, time.Now(), transactionID)
 	if err != nil {
 		r.logger.Error("Error when updating transaction status", zap.Error(err))
-		return err
+		return false, err
+	}
+	if status != "completed" {
+		if err = voidIssuedTickets(tx, orderID); err != nil {
+			r.logger.Error("Error when voiding issued tickets", zap.Error(err))
+			return false, err
+		}
 	}
 
-	return nil
+	event.TransactionID = transactionID
+	event.OrderID = orderID
+	event.PreviousStatus = previousStatus
+	event.NewStatus = status
+	if err = insertTransactionEvent(tx, event); err != nil {
+		r.logger.Error("Error when inserting transaction event", zap.Error(err))
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.logger.Error("Error when commit transaction", zap.Error(err))
+		return false, err
+	}
+
+	return true, nil
+}
+
+// voidIssuedTickets voids the unused tickets of an order that is no longer completed
+func voidIssuedTickets(tx *sql.Tx, orderID string) error {
+	_, err := tx.Exec(`UPDATE issued_ticket SET status = 'void', updated_at = ? WHERE order_id = ? AND status = 'issued'`,
+		time.Now(), orderID)
+
+	return err
+}
+
+func (r *transactionRepository) CancelTransaction(orderID string, event model.TransactionEvent) (bool, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		r.logger.Error("Error when begin transaction", zap.Error(err))
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// only pending rows are touched, so a concurrent settlement is never overwritten
+	query := `UPDATE transaction SET payment_status = 'cancelled', updated_at = ? WHERE order_id = ? AND payment_status = 'pending'`
+
+	result, err := tx.Exec(query, time.Now(), orderID)
+	if err != nil {
+		r.logger.Error("Error when cancelling transaction", zap.Error(err))
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.Error("Error when getting affected rows", zap.Error(err))
+		return false, err
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	var transactionID int
+	if err = tx.QueryRow(`SELECT transaction_id FROM transaction WHERE order_id = ?`, orderID).Scan(&transactionID); err != nil {
+		r.logger.Error("Error when scanning transaction table", zap.Error(err))
+		return false, err
+	}
+
+	event.TransactionID = transactionID
+	event.OrderID = orderID
+	event.PreviousStatus = "pending"
+	event.NewStatus = "cancelled"
+	if err = insertTransactionEvent(tx, event); err != nil {
+		r.logger.Error("Error when inserting transaction event", zap.Error(err))
+		return false, err
+	}
+	// ticket bisa sudah terbit kalau settlement masuk bersamaan dengan cancel
+	if err = voidIssuedTickets(tx, orderID); err != nil {
+		r.logger.Error("Error when voiding issued tickets", zap.Error(err))
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.logger.Error("Error when commit transaction", zap.Error(err))
+		return false, err
+	}
+
+	return true, nil
 }
 
 func (r *transactionRepository) GetDistinctContinentTransaction(email string) ([]string, error) {
 	var continents []string
-	query := `SELECT DISTINCT continent FROM transaction WHERE email = ?`
+	// per line, a single order can span several continents. Cancelled and expired orders were never
+	// bought so they do not count
+	query := `SELECT DISTINCT dt.continent FROM detail_transaction dt
+		JOIN transaction t ON t.transaction_id = dt.transaction_id
+		WHERE t.email = ? AND t.payment_status = 'completed' AND dt.continent <> ''`
 
 	rows, err := r.DB.Query(query, email)
 	if err != nil {
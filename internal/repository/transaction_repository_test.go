@@ -1,9 +1,12 @@
 package repository
 
 import (
+	"errors"
+
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/SyamSolution/transaction-service/internal/model"
 	mock_config "github.com/SyamSolution/transaction-service/mock/config"
+	"github.com/go-sql-driver/mysql"
 	"go.uber.org/mock/gomock"
 	"testing"
 	"time"
@@ -28,10 +31,12 @@ func TestCreateTransaction(t *testing.T) {
 
 	mock.ExpectBegin()
 	mock.ExpectExec("INSERT INTO transaction").WithArgs(transaction.UserID, transaction.OrderID, transaction.TransactionDate, transaction.PaymentMethod, transaction.TotalAmount,
-		transaction.TotalTicket, transaction.FullName, transaction.MobileNumber, transaction.Email, transaction.PaymentStatus, transaction.Continent,
-		transaction.Discount, transaction.CreatedAt, transaction.UpdatedAt).WillReturnResult(sqlmock.NewResult(1, 1))
+		transaction.GrossAmount, transaction.TotalTicket, transaction.FullName, transaction.MobileNumber, transaction.Email, transaction.PaymentStatus, transaction.Continent,
+		transaction.Discount, transaction.DiscountTrace, transaction.VoucherCode, transaction.VoucherDiscount, transaction.SplitCount, transaction.SplitDeadline, transaction.CreatedAt, transaction.UpdatedAt).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO transaction_event").WithArgs(1, transaction.OrderID, model.TransactionActorUser, transaction.Email, "",
+		transaction.PaymentStatus, "", "", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
 	for _, dt := range detailTransaction {
-		mock.ExpectExec("INSERT INTO detail_transaction").WithArgs(sqlmock.AnyArg(), dt.TicketID, dt.TicketType, dt.CountryName, dt.City, dt.Quantity, dt.CreatedAt, dt.UpdatedAt).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO detail_transaction").WithArgs(sqlmock.AnyArg(), dt.TicketID, dt.TicketType, dt.Continent, dt.CountryName, dt.City, dt.Quantity, dt.Price, dt.NetAmount, dt.CreatedAt, dt.UpdatedAt).WillReturnResult(sqlmock.NewResult(1, 1))
 	}
 	mock.ExpectCommit()
 
@@ -45,6 +50,73 @@ func TestCreateTransaction(t *testing.T) {
 	}
 }
 
+func TestCreateTransactionWithTicketHolder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+
+	r := NewTransactionRepository(db, logger)
+
+	holder := &model.TicketHolder{FullName: "Holder Name", Email: "holder@example.com", IDNumber: "3201"}
+	detailTransaction := []model.DetailTransaction{{TicketID: 1}, {TicketID: 2, Holder: holder}}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO transaction").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO transaction_event").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO detail_transaction").WithArgs(int64(1), 1, "", "", "", "", 0, float32(0), float32(0), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(10, 1))
+	mock.ExpectExec("INSERT INTO detail_transaction").WithArgs(int64(1), 2, "", "", "", "", 0, float32(0), float32(0), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(11, 1))
+	mock.ExpectExec("INSERT INTO ticket_holder").WithArgs(int64(11), holder.FullName, holder.Email, holder.IDNumber, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = r.CreateTransaction(model.Transaction{}, detailTransaction)
+	if err != nil {
+		t.Errorf("error was not expected while creating transaction: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCreateTransactionDuplicateOrderID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+	logger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+
+	r := NewTransactionRepository(db, logger)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO transaction").WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'ORDER-1' for key 'uq_transaction_order_id'"})
+	mock.ExpectRollback()
+
+	err = r.CreateTransaction(model.Transaction{OrderID: "ORDER-1"}, []model.DetailTransaction{{}})
+	if !errors.Is(err, ErrDuplicateOrderID) {
+		t.Errorf("expected ErrDuplicateOrderID, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
 func TestGetTransactionByTransactionID(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -63,11 +135,11 @@ func TestGetTransactionByTransactionID(t *testing.T) {
 	email := "test@example.com"
 
 	rows := sqlmock.NewRows([]string{"transaction_id", "user_id", "order_id", "transaction_date", "payment_method", "total_amount", "total_ticket", "full_name",
-		"mobile_number", "email", "payment_status", "continent", "created_at", "updated_at"}).
-		AddRow(1, 1, "order1", time.Now(), "method1", 100, 2, "fullname1", "1234567890", "test@example.com", "status1", "continent1", time.Now(), time.Now())
+		"mobile_number", "email", "payment_status", "continent", "split_count", "split_deadline", "created_at", "updated_at"}).
+		AddRow(1, 1, "order1", time.Now(), "method1", 100, 2, "fullname1", "1234567890", "test@example.com", "status1", "continent1", 0, nil, time.Now(), time.Now())
 
 	mock.ExpectQuery(`SELECT transaction_id, user_id, order_id, transaction_date, payment_method, total_amount, total_ticket, full_name,
-		mobile_number, email, payment_status, continent, created_at, updated_at FROM transaction WHERE transaction_id = \? AND email = \?`).
+		mobile_number, email, payment_status, continent, split_count, split_deadline, created_at, updated_at FROM transaction WHERE transaction_id = \? AND email = \?`).
 		WithArgs(transactionID, email).
 		WillReturnRows(rows)
 
@@ -98,11 +170,14 @@ func TestGetTransactionByOrderID(t *testing.T) {
 	orderID := "order1"
 
 	rows := sqlmock.NewRows([]string{"transaction_id", "user_id", "order_id", "transaction_date", "payment_method", "total_amount", "total_ticket", "full_name",
-		"mobile_number", "email", "payment_status", "continent", "created_at", "updated_at"}).
-		AddRow(1, 1, "order1", time.Now(), "method1", 100, 2, "fullname1", "1234567890", "test@example.com", "status1", "continent1", time.Now(), time.Now())
+		"mobile_number", "email", "payment_status", "continent", "split_count", "split_deadline", "gross_amount", "discount", "voucher_code",
+		"voucher_discount", "created_at", "updated_at"}).
+		AddRow(1, 1, "order1", time.Now(), "method1", 100, 2, "fullname1", "1234567890", "test@example.com", "status1", "continent1", 0, nil,
+			1604500, 0, "", 0, time.Now(), time.Now())
 
 	mock.ExpectQuery(`SELECT transaction_id, user_id, order_id, transaction_date, payment_method, total_amount, total_ticket, full_name,
-  mobile_number, email, payment_status, continent, created_at, updated_at FROM transaction WHERE order_id = \?`).
+  mobile_number, email, payment_status, continent, split_count, split_deadline, gross_amount, COALESCE\(discount, 0\), voucher_code,
+  voucher_discount, created_at, updated_at FROM transaction WHERE order_id = \?`).
 		WithArgs(orderID).
 		WillReturnRows(rows)
 
@@ -132,18 +207,26 @@ func TestGetDetailTransactionByTransactionID(t *testing.T) {
 
 	transactionID := 1
 
-	rows := sqlmock.NewRows([]string{"detail_transaction_id", "transaction_id", "ticket_id", "ticket_type", "country_name", "city", "quantity", "created_at", "updated_at"}).
-		AddRow(1, 1, 1, "type1", "country1", "city1", 1, time.Now(), time.Now())
+	rows := sqlmock.NewRows([]string{"detail_transaction_id", "transaction_id", "ticket_id", "ticket_type", "continent", "country_name", "city", "quantity",
+		"price", "net_amount", "created_at", "updated_at", "full_name", "email", "id_number"}).
+		AddRow(1, 1, 1, "type1", "continent1", "country1", "city1", 1, 100, 100, time.Now(), time.Now(), nil, nil, nil).
+		AddRow(2, 1, 2, "type2", "continent1", "country1", "city1", 2, 50, 100, time.Now(), time.Now(), "Holder Name", "holder@example.com", "3201")
 
-	mock.ExpectQuery(`SELECT detail_transaction_id, transaction_id, ticket_id, ticket_type, country_name, city, quantity, created_at, updated_at
-  FROM detail_transaction WHERE transaction_id = \?`).
+	mock.ExpectQuery(`SELECT dt.detail_transaction_id, dt.transaction_id, dt.ticket_id, dt.ticket_type, dt.continent, dt.country_name, dt.city,
+  dt.quantity, dt.price, dt.net_amount, dt.created_at, dt.updated_at, th.full_name, th.email, th.id_number
+  FROM detail_transaction dt LEFT JOIN ticket_holder th ON th.detail_transaction_id = dt.detail_transaction_id
+  WHERE dt.transaction_id = \?`).
 		WithArgs(transactionID).
 		WillReturnRows(rows)
 
-	_, err = r.GetDetailTransactionByTransactionID(transactionID)
+	detailTransactions, err := r.GetDetailTransactionByTransactionID(transactionID)
 	if err != nil {
 		t.Errorf("error was not expected while getting detail transaction: %s", err)
 	}
+	if len(detailTransactions) != 2 || detailTransactions[0].Holder != nil || detailTransactions[1].Holder == nil ||
+		detailTransactions[1].Holder.Email != "holder@example.com" {
+		t.Errorf("unexpected ticket holders: %+v", detailTransactions)
+	}
 
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
@@ -187,6 +270,89 @@ func TestGetListTransaction(t *testing.T) {
 	}
 }
 
+func TestGetTransactionsByDateRange(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+
+	r := NewTransactionRepository(db, logger)
+
+	from := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	rows := sqlmock.NewRows([]string{"transaction_id", "order_id", "transaction_date", "total_amount", "gross_amount", "email",
+		"payment_status", "split_count"}).
+		AddRow(11, "ORDER-11", from, 10, 150000, "test@example.com", "pending", 0).
+		AddRow(12, "ORDER-12", from, 20, 300000, "test@example.com", "completed", 0)
+
+	mock.ExpectQuery(`SELECT transaction_id, order_id, transaction_date, total_amount, gross_amount, email, payment_status, split_count
+		FROM transaction WHERE transaction_date >= \? AND transaction_date < \? AND transaction_id > \? ORDER BY transaction_id LIMIT \?`).
+		WithArgs(from, to, 10, 2).
+		WillReturnRows(rows)
+
+	transactions, err := r.GetTransactionsByDateRange(from, to, 10, 2)
+	if err != nil {
+		t.Errorf("error was not expected while getting transactions by date range: %s", err)
+	}
+	if len(transactions) != 2 || transactions[1].OrderID != "ORDER-12" || transactions[1].GrossAmount != 300000 {
+		t.Errorf("unexpected transactions: %+v", transactions)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestGetTransactionExport(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+
+	r := NewTransactionRepository(db, logger)
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	rows := sqlmock.NewRows([]string{"transaction_id", "order_id", "transaction_date", "payment_method", "payment_status", "discount",
+		"voucher_code", "voucher_discount", "total_amount", "gross_amount", "ticket_type", "continent", "country_name", "city", "quantity",
+		"price", "net_amount"}).
+		AddRow(1, "ORDER-1", from, "bca_va", "completed", 10, "", 0, 180, 2888100, "VIP", "asia", "Indonesia", "Jakarta", 1, 100, 90).
+		AddRow(1, "ORDER-1", from, "bca_va", "completed", 10, "", 0, 180, 2888100, "CAT 1", "asia", "Indonesia", "Jakarta", 2, 50, 90).
+		AddRow(2, "ORDER-2", from, "gopay", "pending", 0, "", 0, 50, 802250, "CAT 1", "asia", "Indonesia", "Jakarta", 1, 50, 50).
+		AddRow(3, "ORDER-3", from, "", "", 0, "", 0, 0, 0, "", "", "", "", 0, 0, 0)
+
+	mock.ExpectQuery(`SELECT t.transaction_id, t.order_id, t.transaction_date, COALESCE\(t.payment_method, ''\), .+
+		COALESCE\(dt.ticket_type, ''\), dt.continent, COALESCE\(dt.country_name, ''\), COALESCE\(dt.city, ''\), COALESCE\(dt.quantity, 0\),
+		.+ FROM transaction t JOIN detail_transaction dt ON dt.transaction_id = t.transaction_id
+		WHERE t.email = \? AND t.transaction_date >= \? AND t.transaction_date < \?`).
+		WithArgs("test@example.com", from, to).
+		WillReturnRows(rows)
+
+	exports, err := r.GetTransactionExport("test@example.com", from, to)
+	if err != nil {
+		t.Errorf("error was not expected while getting transaction export: %s", err)
+	}
+	if len(exports) != 3 || len(exports[0].Lines) != 2 || len(exports[1].Lines) != 1 || exports[0].Lines[1].TicketType != "CAT 1" {
+		t.Errorf("unexpected exports: %+v", exports)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
 func TestUpdateTransactionStatus(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -203,15 +369,63 @@ func TestUpdateTransactionStatus(t *testing.T) {
 
 	orderID := "order1"
 	status := "completed"
+	event := model.TransactionEvent{Actor: model.TransactionActorWebhook, PayloadReference: "midtrans-1"}
 
-	mock.ExpectExec(`UPDATE transaction SET payment_status = \? WHERE order_id = \?`).
-		WithArgs(status, orderID).
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT transaction_id, payment_status FROM transaction WHERE order_id = \? FOR UPDATE`).
+		WithArgs(orderID).
+		WillReturnRows(sqlmock.NewRows([]string{"transaction_id", "payment_status"}).AddRow(7, "pending"))
+	mock.ExpectExec(`UPDATE transaction SET payment_status = \?, updated_at = \? WHERE transaction_id = \?`).
+		WithArgs(status, sqlmock.AnyArg(), 7).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO transaction_event`).
+		WithArgs(7, orderID, model.TransactionActorWebhook, "", "pending", status, "midtrans-1", "", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	changed, err := r.UpdateTransactionStatus(orderID, status, event)
+	if err != nil {
+		t.Errorf("error was not expected while updating transaction status: %s", err)
+	}
+	if !changed {
+		t.Errorf("expected the status to change")
+	}
+
+	// status yang sama bukan perubahan, tidak dicatat
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT transaction_id, payment_status FROM transaction WHERE order_id = \? FOR UPDATE`).
+		WithArgs(orderID).
+		WillReturnRows(sqlmock.NewRows([]string{"transaction_id", "payment_status"}).AddRow(7, status))
+	mock.ExpectRollback()
 
-	err = r.UpdateTransactionStatus(orderID, status)
+	changed, err = r.UpdateTransactionStatus(orderID, status, event)
 	if err != nil {
 		t.Errorf("error was not expected while updating transaction status: %s", err)
 	}
+	if changed {
+		t.Errorf("expected the same status not to be a change")
+	}
+
+	// order yang batal setelah lunas, ticket yang sudah terbit ikut di-void
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT transaction_id, payment_status FROM transaction WHERE order_id = \? FOR UPDATE`).
+		WithArgs(orderID).
+		WillReturnRows(sqlmock.NewRows([]string{"transaction_id", "payment_status"}).AddRow(7, "completed"))
+	mock.ExpectExec(`UPDATE transaction SET payment_status = \?, updated_at = \? WHERE transaction_id = \?`).
+		WithArgs("cancelled", sqlmock.AnyArg(), 7).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`UPDATE issued_ticket SET status = 'void', updated_at = \? WHERE order_id = \? AND status = 'issued'`).
+		WithArgs(sqlmock.AnyArg(), orderID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`INSERT INTO transaction_event`).
+		WithArgs(7, orderID, model.TransactionActorWebhook, "", "completed", "cancelled", "midtrans-1", "", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	_, err = r.UpdateTransactionStatus(orderID, "cancelled", event)
+	if err != nil {
+		t.Errorf("error was not expected while cancelling a completed transaction: %s", err)
+	}
 
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
@@ -238,7 +452,9 @@ func TestGetDistinctContinentTransaction(t *testing.T) {
 		AddRow("continent1").
 		AddRow("continent2")
 
-	mock.ExpectQuery(`SELECT DISTINCT continent FROM transaction WHERE email = \?`).
+	mock.ExpectQuery(`SELECT DISTINCT dt.continent FROM detail_transaction dt
+		JOIN transaction t ON t.transaction_id = dt.transaction_id
+		WHERE t.email = \? AND t.payment_status = 'completed' AND dt.continent <> ''`).
 		WithArgs(email).
 		WillReturnRows(rows)
 
@@ -251,3 +467,61 @@ func TestGetDistinctContinentTransaction(t *testing.T) {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 }
+
+func TestCancelTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+
+	r := NewTransactionRepository(db, logger)
+
+	orderID := "order1"
+	event := model.TransactionEvent{Actor: model.TransactionActorUser, ActorID: "test@example.com", Note: "cancelled by customer"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE transaction SET payment_status = 'cancelled', updated_at = \? WHERE order_id = \? AND payment_status = 'pending'`).
+		WithArgs(sqlmock.AnyArg(), orderID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT transaction_id FROM transaction WHERE order_id = \?`).
+		WithArgs(orderID).
+		WillReturnRows(sqlmock.NewRows([]string{"transaction_id"}).AddRow(7))
+	mock.ExpectExec(`INSERT INTO transaction_event`).
+		WithArgs(7, orderID, model.TransactionActorUser, "test@example.com", "pending", "cancelled", "", "cancelled by customer", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`UPDATE issued_ticket SET status = 'void'`).
+		WithArgs(sqlmock.AnyArg(), orderID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE transaction SET payment_status = 'cancelled', updated_at = \? WHERE order_id = \? AND payment_status = 'pending'`).
+		WithArgs(sqlmock.AnyArg(), orderID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	cancelled, err := r.CancelTransaction(orderID, event)
+	if err != nil {
+		t.Errorf("error was not expected while cancelling transaction: %s", err)
+	}
+	if !cancelled {
+		t.Errorf("expected pending transaction to be cancelled")
+	}
+
+	cancelled, err = r.CancelTransaction(orderID, event)
+	if err != nil {
+		t.Errorf("error was not expected while cancelling transaction: %s", err)
+	}
+	if cancelled {
+		t.Errorf("expected non pending transaction to be left untouched")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
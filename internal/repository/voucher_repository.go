@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/go-sql-driver/mysql"
+	"go.uber.org/zap"
+)
+
+const voucherColumns = `voucher_id, code, description, discount_type, amount, min_spend, valid_from, valid_until,
+		max_redemption, max_per_user, redeemed_count, continent, ticket_type, is_active, created_at, updated_at`
+
+type voucherRepository struct {
+	DB     *sql.DB
+	logger config.Logger
+}
+
+type VoucherPersister interface {
+	CreateVoucher(voucher model.Voucher) (int64, error)
+	UpdateVoucher(voucher model.Voucher) error
+	GetVoucherByCode(code string) (model.Voucher, error)
+	GetListVoucher() ([]model.Voucher, error)
+	ReserveVoucher(redemption model.VoucherRedemption) error
+	ReleaseVoucher(orderID string) error
+	RedeemVoucher(orderID string) error
+}
+
+func NewVoucherRepository(DB *sql.DB, logger config.Logger) VoucherPersister {
+	return &voucherRepository{DB: DB, logger: logger}
+}
+
+func scanVoucher(row interface{ Scan(dest ...any) error }) (model.Voucher, error) {
+	var voucher model.Voucher
+	err := row.Scan(&voucher.VoucherID, &voucher.Code, &voucher.Description, &voucher.DiscountType, &voucher.Amount,
+		&voucher.MinSpend, &voucher.ValidFrom, &voucher.ValidUntil, &voucher.MaxRedemption, &voucher.MaxPerUser,
+		&voucher.RedeemedCount, &voucher.Continent, &voucher.TicketType, &voucher.IsActive, &voucher.CreatedAt, &voucher.UpdatedAt)
+
+	return voucher, err
+}
+
+func (r *voucherRepository) CreateVoucher(voucher model.Voucher) (int64, error) {
+	query := `INSERT INTO voucher (code, description, discount_type, amount, min_spend, valid_from, valid_until,
+		max_redemption, max_per_user, continent, ticket_type, is_active, created_at, updated_at)
+		VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?)`
+
+	result, err := r.DB.Exec(query, voucher.Code, voucher.Description, voucher.DiscountType, voucher.Amount, voucher.MinSpend,
+		voucher.ValidFrom, voucher.ValidUntil, voucher.MaxRedemption, voucher.MaxPerUser, voucher.Continent, voucher.TicketType,
+		voucher.IsActive, voucher.CreatedAt, voucher.UpdatedAt)
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry {
+			return 0, ErrDuplicateVoucherCode
+		}
+		r.logger.Error("Error when inserting voucher", zap.Error(err))
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		r.logger.Error("Error when getting last insert id", zap.Error(err))
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// UpdateVoucher never touches redeemed_count, that one is owned by reserve and release
+func (r *voucherRepository) UpdateVoucher(voucher model.Voucher) error {
+	query := `UPDATE voucher SET description = ?, discount_type = ?, amount = ?, min_spend = ?, valid_from = ?, valid_until = ?,
+		max_redemption = ?, max_per_user = ?, continent = ?, ticket_type = ?, is_active = ?, updated_at = ? WHERE code = ?`
+
+	result, err := r.DB.Exec(query, voucher.Description, voucher.DiscountType, voucher.Amount, voucher.MinSpend, voucher.ValidFrom,
+		voucher.ValidUntil, voucher.MaxRedemption, voucher.MaxPerUser, voucher.Continent, voucher.TicketType, voucher.IsActive,
+		voucher.UpdatedAt, voucher.Code)
+	if err != nil {
+		r.logger.Error("Error when updating voucher", zap.Error(err))
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.Error("Error when getting affected rows", zap.Error(err))
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *voucherRepository) GetVoucherByCode(code string) (model.Voucher, error) {
+	query := `SELECT ` + voucherColumns + ` FROM voucher WHERE code = ?`
+
+	voucher, err := scanVoucher(r.DB.QueryRow(query, code))
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			r.logger.Error("Error when querying voucher table", zap.Error(err))
+		}
+		return model.Voucher{}, err
+	}
+
+	return voucher, nil
+}
+
+func (r *voucherRepository) GetListVoucher() ([]model.Voucher, error) {
+	var vouchers []model.Voucher
+	query := `SELECT ` + voucherColumns + ` FROM voucher ORDER BY created_at DESC`
+
+	rows, err := r.DB.Query(query)
+	if err != nil {
+		r.logger.Error("Error when querying voucher table", zap.Error(err))
+		return vouchers, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		voucher, err := scanVoucher(rows)
+		if err != nil {
+			r.logger.Error("Error when scanning voucher table", zap.Error(err))
+			return vouchers, err
+		}
+		vouchers = append(vouchers, voucher)
+	}
+
+	return vouchers, nil
+}
+
+// ReserveVoucher locks the voucher row so two orders can not both take the last redemption
+func (r *voucherRepository) ReserveVoucher(redemption model.VoucherRedemption) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		r.logger.Error("Error when begin transaction", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback()
+
+	var redeemedCount, maxRedemption, maxPerUser int
+	err = tx.QueryRow(`SELECT redeemed_count, max_redemption, max_per_user FROM voucher WHERE voucher_id = ? FOR UPDATE`,
+		redemption.VoucherID).Scan(&redeemedCount, &maxRedemption, &maxPerUser)
+	if err != nil {
+		r.logger.Error("Error when locking voucher", zap.Error(err))
+		return err
+	}
+	if maxRedemption > 0 && redeemedCount >= maxRedemption {
+		return ErrVoucherExhausted
+	}
+
+	if maxPerUser > 0 {
+		var used int
+		err = tx.QueryRow(`SELECT COUNT(*) FROM voucher_redemption WHERE voucher_id = ? AND email = ? AND status IN ('reserved', 'redeemed')`,
+			redemption.VoucherID, redemption.Email).Scan(&used)
+		if err != nil {
+			r.logger.Error("Error when counting voucher redemption", zap.Error(err))
+			return err
+		}
+		if used >= maxPerUser {
+			return ErrVoucherUserLimit
+		}
+	}
+
+	_, err = tx.Exec(`INSERT INTO voucher_redemption (voucher_id, order_id, email, amount, status, created_at, updated_at)
+		VALUES (?,?,?,?,?,?,?)`, redemption.VoucherID, redemption.OrderID, redemption.Email, redemption.Amount,
+		model.VoucherRedemptionReserved, redemption.CreatedAt, redemption.UpdatedAt)
+	if err != nil {
+		r.logger.Error("Error when inserting voucher redemption", zap.Error(err))
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE voucher SET redeemed_count = redeemed_count + 1, updated_at = ? WHERE voucher_id = ?`,
+		time.Now(), redemption.VoucherID)
+	if err != nil {
+		r.logger.Error("Error when updating voucher redeemed count", zap.Error(err))
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.logger.Error("Error when commit transaction", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// ReleaseVoucher gives the reservation of a cancelled or expired order back, orders without a
+// reserved voucher are a no-op
+func (r *voucherRepository) ReleaseVoucher(orderID string) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		r.logger.Error("Error when begin transaction", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback()
+
+	var voucherID int
+	err = tx.QueryRow(`SELECT voucher_id FROM voucher_redemption WHERE order_id = ? AND status = 'reserved' FOR UPDATE`,
+		orderID).Scan(&voucherID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		r.logger.Error("Error when locking voucher redemption", zap.Error(err))
+		return err
+	}
+
+	now := time.Now()
+	_, err = tx.Exec(`UPDATE voucher_redemption SET status = 'released', updated_at = ? WHERE order_id = ?`, now, orderID)
+	if err != nil {
+		r.logger.Error("Error when releasing voucher redemption", zap.Error(err))
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE voucher SET redeemed_count = redeemed_count - 1, updated_at = ? WHERE voucher_id = ? AND redeemed_count > 0`,
+		now, voucherID)
+	if err != nil {
+		r.logger.Error("Error when updating voucher redeemed count", zap.Error(err))
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.logger.Error("Error when commit transaction", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *voucherRepository) RedeemVoucher(orderID string) error {
+	query := `UPDATE voucher_redemption SET status = 'redeemed', updated_at = ? WHERE order_id = ? AND status = 'reserved'`
+
+	_, err := r.DB.Exec(query, time.Now(), orderID)
+	if err != nil {
+		r.logger.Error("Error when redeeming voucher", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	mock_config "github.com/SyamSolution/transaction-service/mock/config"
+	"github.com/go-sql-driver/mysql"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateVoucherDuplicateCode(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+
+	r := NewVoucherRepository(db, logger)
+
+	mock.ExpectExec("INSERT INTO voucher").WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+
+	_, err = r.CreateVoucher(model.Voucher{Code: "HEMAT10"})
+	if !errors.Is(err, ErrDuplicateVoucherCode) {
+		t.Errorf("expected ErrDuplicateVoucherCode, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestGetVoucherByCode(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+
+	r := NewVoucherRepository(db, logger)
+
+	rows := sqlmock.NewRows([]string{"voucher_id", "code", "description", "discount_type", "amount", "min_spend", "valid_from",
+		"valid_until", "max_redemption", "max_per_user", "redeemed_count", "continent", "ticket_type", "is_active", "created_at", "updated_at"}).
+		AddRow(1, "HEMAT10", "", "percentage", 10, 0, nil, time.Now(), 100, 1, 3, "", "", true, time.Now(), time.Now())
+	mock.ExpectQuery(`SELECT (.+) FROM voucher WHERE code = \?`).WithArgs("HEMAT10").WillReturnRows(rows)
+
+	voucher, err := r.GetVoucherByCode("HEMAT10")
+	if err != nil {
+		t.Errorf("error was not expected while getting voucher: %s", err)
+	}
+	if voucher.ValidFrom != nil || voucher.ValidUntil == nil {
+		t.Errorf("validity window was not scanned correctly: %+v", voucher)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestReserveVoucher(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+
+	r := NewVoucherRepository(db, logger)
+
+	redemption := model.VoucherRedemption{VoucherID: 1, OrderID: "ORDER-1", Email: "test@example.com", Amount: 10}
+	lockQuery := `SELECT redeemed_count, max_redemption, max_per_user FROM voucher WHERE voucher_id = \? FOR UPDATE`
+	countQuery := `SELECT COUNT\(\*\) FROM voucher_redemption WHERE voucher_id = \? AND email = \?`
+
+	// last redemption taken
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockQuery).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"redeemed_count", "max_redemption", "max_per_user"}).AddRow(5, 5, 0))
+	mock.ExpectRollback()
+
+	if err = r.ReserveVoucher(redemption); !errors.Is(err, ErrVoucherExhausted) {
+		t.Errorf("expected ErrVoucherExhausted, got %v", err)
+	}
+
+	// per user cap
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockQuery).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"redeemed_count", "max_redemption", "max_per_user"}).AddRow(1, 5, 1))
+	mock.ExpectQuery(countQuery).WithArgs(1, redemption.Email).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectRollback()
+
+	if err = r.ReserveVoucher(redemption); !errors.Is(err, ErrVoucherUserLimit) {
+		t.Errorf("expected ErrVoucherUserLimit, got %v", err)
+	}
+
+	// reserved
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockQuery).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"redeemed_count", "max_redemption", "max_per_user"}).AddRow(4, 5, 1))
+	mock.ExpectQuery(countQuery).WithArgs(1, redemption.Email).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec("INSERT INTO voucher_redemption").
+		WithArgs(1, "ORDER-1", redemption.Email, redemption.Amount, "reserved", redemption.CreatedAt, redemption.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`UPDATE voucher SET redeemed_count = redeemed_count \+ 1`).WithArgs(sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err = r.ReserveVoucher(redemption); err != nil {
+		t.Errorf("error was not expected while reserving voucher: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestReleaseVoucher(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+
+	r := NewVoucherRepository(db, logger)
+
+	lockQuery := `SELECT voucher_id FROM voucher_redemption WHERE order_id = \? AND status = 'reserved' FOR UPDATE`
+
+	// order without voucher
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockQuery).WithArgs("ORDER-1").WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	if err = r.ReleaseVoucher("ORDER-1"); err != nil {
+		t.Errorf("error was not expected while releasing voucher: %s", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockQuery).WithArgs("ORDER-2").WillReturnRows(sqlmock.NewRows([]string{"voucher_id"}).AddRow(3))
+	mock.ExpectExec(`UPDATE voucher_redemption SET status = 'released'`).WithArgs(sqlmock.AnyArg(), "ORDER-2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE voucher SET redeemed_count = redeemed_count - 1`).WithArgs(sqlmock.AnyArg(), 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err = r.ReleaseVoucher("ORDER-2"); err != nil {
+		t.Errorf("error was not expected while releasing voucher: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,280 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/go-sql-driver/mysql"
+	"go.uber.org/zap"
+)
+
+const waitlistColumns = `waitlist_id, ticket_id, continent, email, quantity, status, hold_until, order_id, created_at, updated_at`
+
+type waitlistRepository struct {
+	DB     *sql.DB
+	logger config.Logger
+}
+
+type WaitlistPersister interface {
+	CreateWaitlist(waitlist model.Waitlist) (int64, error)
+	GetListWaitlistByEmail(email string) ([]model.Waitlist, error)
+	LeaveWaitlist(waitlistID int, email string, now time.Time) (model.Waitlist, error)
+	GetHeldQuantity(ticketIDs []int, email string, now time.Time) (map[int]int, error)
+	GetExpiredHoldTicketIDs(now time.Time) ([]int, error)
+	PromoteWaitlist(ticketID, quantity int, now, holdUntil time.Time) ([]model.Waitlist, error)
+	ConsumeWaitlistHold(email string, ticketIDs []int, orderID string, now time.Time) error
+}
+
+func NewWaitlistRepository(DB *sql.DB, logger config.Logger) WaitlistPersister {
+	return &waitlistRepository{DB: DB, logger: logger}
+}
+
+func scanWaitlist(row interface{ Scan(dest ...any) error }) (model.Waitlist, error) {
+	var waitlist model.Waitlist
+	err := row.Scan(&waitlist.WaitlistID, &waitlist.TicketID, &waitlist.Continent, &waitlist.Email, &waitlist.Quantity,
+		&waitlist.Status, &waitlist.HoldUntil, &waitlist.OrderID, &waitlist.CreatedAt, &waitlist.UpdatedAt)
+
+	return waitlist, err
+}
+
+// placeholders returns "?,?,?" for n arguments
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+func (r *waitlistRepository) CreateWaitlist(waitlist model.Waitlist) (int64, error) {
+	query := `INSERT INTO waitlist (ticket_id, continent, email, quantity, status, created_at, updated_at) VALUES (?,?,?,?,?,?,?)`
+
+	result, err := r.DB.Exec(query, waitlist.TicketID, waitlist.Continent, waitlist.Email, waitlist.Quantity,
+		model.WaitlistWaiting, waitlist.CreatedAt, waitlist.UpdatedAt)
+	if err != nil {
+		// unique key on the active entry of (email, ticket_id)
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry {
+			return 0, ErrDuplicateWaitlist
+		}
+		r.logger.Error("Error when inserting waitlist", zap.Error(err))
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+func (r *waitlistRepository) GetListWaitlistByEmail(email string) ([]model.Waitlist, error) {
+	var waitlists []model.Waitlist
+	query := `SELECT ` + waitlistColumns + ` FROM waitlist WHERE email = ? ORDER BY created_at DESC, waitlist_id DESC`
+
+	rows, err := r.DB.Query(query, email)
+	if err != nil {
+		r.logger.Error("Error when querying waitlist table", zap.Error(err))
+		return waitlists, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		waitlist, err := scanWaitlist(rows)
+		if err != nil {
+			r.logger.Error("Error when scanning waitlist table", zap.Error(err))
+			return waitlists, err
+		}
+		waitlists = append(waitlists, waitlist)
+	}
+
+	return waitlists, nil
+}
+
+// LeaveWaitlist returns the entry as it was before leaving, sql.ErrNoRows when it does not belong to the user
+// or is no longer active
+func (r *waitlistRepository) LeaveWaitlist(waitlistID int, email string, now time.Time) (model.Waitlist, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		r.logger.Error("Error when begin transaction", zap.Error(err))
+		return model.Waitlist{}, err
+	}
+	defer tx.Rollback()
+
+	waitlist, err := scanWaitlist(tx.QueryRow(`SELECT `+waitlistColumns+` FROM waitlist WHERE waitlist_id = ? AND email = ?
+		AND status IN ('waiting', 'notified') FOR UPDATE`, waitlistID, email))
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			r.logger.Error("Error when locking waitlist", zap.Error(err))
+		}
+		return model.Waitlist{}, err
+	}
+
+	_, err = tx.Exec(`UPDATE waitlist SET status = 'left', updated_at = ? WHERE waitlist_id = ?`, now, waitlistID)
+	if err != nil {
+		r.logger.Error("Error when leaving waitlist", zap.Error(err))
+		return model.Waitlist{}, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.logger.Error("Error when commit transaction", zap.Error(err))
+		return model.Waitlist{}, err
+	}
+
+	return waitlist, nil
+}
+
+// GetHeldQuantity sums the running holds of other users per ticket, those tickets are not for sale to email
+func (r *waitlistRepository) GetHeldQuantity(ticketIDs []int, email string, now time.Time) (map[int]int, error) {
+	held := make(map[int]int)
+	if len(ticketIDs) == 0 {
+		return held, nil
+	}
+
+	query := `SELECT ticket_id, SUM(quantity) FROM waitlist WHERE ticket_id IN (` + placeholders(len(ticketIDs)) + `)
+		AND status = 'notified' AND hold_until > ? AND email <> ? GROUP BY ticket_id`
+
+	args := make([]any, 0, len(ticketIDs)+2)
+	for _, id := range ticketIDs {
+		args = append(args, id)
+	}
+	args = append(args, now, email)
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		r.logger.Error("Error when querying held waitlist", zap.Error(err))
+		return held, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ticketID, quantity int
+		if err := rows.Scan(&ticketID, &quantity); err != nil {
+			r.logger.Error("Error when scanning held waitlist", zap.Error(err))
+			return held, err
+		}
+		held[ticketID] = quantity
+	}
+
+	return held, nil
+}
+
+// GetExpiredHoldTicketIDs returns the tickets with a hold that ran out without a purchase
+func (r *waitlistRepository) GetExpiredHoldTicketIDs(now time.Time) ([]int, error) {
+	var ticketIDs []int
+	query := `SELECT DISTINCT ticket_id FROM waitlist WHERE status = 'notified' AND hold_until <= ?`
+
+	rows, err := r.DB.Query(query, now)
+	if err != nil {
+		r.logger.Error("Error when querying expired waitlist", zap.Error(err))
+		return ticketIDs, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ticketID int
+		if err := rows.Scan(&ticketID); err != nil {
+			r.logger.Error("Error when scanning expired waitlist", zap.Error(err))
+			return ticketIDs, err
+		}
+		ticketIDs = append(ticketIDs, ticketID)
+	}
+
+	return ticketIDs, nil
+}
+
+// PromoteWaitlist gives quantity released tickets to the oldest waiting entries. Expired holds of the ticket
+// are closed first and their quantity is handed on as well. Entries are served strictly in order, a large
+// entry at the head blocks the ones behind it.
+func (r *waitlistRepository) PromoteWaitlist(ticketID, quantity int, now, holdUntil time.Time) ([]model.Waitlist, error) {
+	var promoted []model.Waitlist
+
+	tx, err := r.DB.Begin()
+	if err != nil {
+		r.logger.Error("Error when begin transaction", zap.Error(err))
+		return promoted, err
+	}
+	defer tx.Rollback()
+
+	var expired int
+	err = tx.QueryRow(`SELECT COALESCE(SUM(quantity), 0) FROM waitlist WHERE ticket_id = ? AND status = 'notified'
+		AND hold_until <= ? FOR UPDATE`, ticketID, now).Scan(&expired)
+	if err != nil {
+		r.logger.Error("Error when locking expired waitlist", zap.Error(err))
+		return promoted, err
+	}
+	if expired > 0 {
+		_, err = tx.Exec(`UPDATE waitlist SET status = 'expired', updated_at = ? WHERE ticket_id = ? AND status = 'notified'
+			AND hold_until <= ?`, now, ticketID, now)
+		if err != nil {
+			r.logger.Error("Error when expiring waitlist", zap.Error(err))
+			return promoted, err
+		}
+	}
+	available := quantity + expired
+
+	rows, err := tx.Query(`SELECT `+waitlistColumns+` FROM waitlist WHERE ticket_id = ? AND status = 'waiting'
+		ORDER BY created_at, waitlist_id FOR UPDATE`, ticketID)
+	if err != nil {
+		r.logger.Error("Error when querying waiting waitlist", zap.Error(err))
+		return promoted, err
+	}
+	for rows.Next() {
+		waitlist, err := scanWaitlist(rows)
+		if err != nil {
+			rows.Close()
+			r.logger.Error("Error when scanning waitlist table", zap.Error(err))
+			return nil, err
+		}
+		if waitlist.Quantity > available {
+			break
+		}
+		available -= waitlist.Quantity
+		promoted = append(promoted, waitlist)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		r.logger.Error("Error when iterating waitlist table", zap.Error(err))
+		return nil, err
+	}
+
+	for i := range promoted {
+		_, err = tx.Exec(`UPDATE waitlist SET status = 'notified', hold_until = ?, updated_at = ? WHERE waitlist_id = ?`,
+			holdUntil, now, promoted[i].WaitlistID)
+		if err != nil {
+			r.logger.Error("Error when notifying waitlist", zap.Error(err))
+			return nil, err
+		}
+		promoted[i].Status = model.WaitlistNotified
+		promoted[i].HoldUntil = &holdUntil
+		promoted[i].UpdatedAt = now
+	}
+
+	if err = tx.Commit(); err != nil {
+		r.logger.Error("Error when commit transaction", zap.Error(err))
+		return nil, err
+	}
+
+	return promoted, nil
+}
+
+// ConsumeWaitlistHold marks the running holds of email on the ordered tickets as purchased
+func (r *waitlistRepository) ConsumeWaitlistHold(email string, ticketIDs []int, orderID string, now time.Time) error {
+	if len(ticketIDs) == 0 {
+		return nil
+	}
+
+	query := `UPDATE waitlist SET status = 'purchased', order_id = ?, updated_at = ? WHERE email = ?
+		AND ticket_id IN (` + placeholders(len(ticketIDs)) + `) AND status = 'notified' AND hold_until > ?`
+
+	args := make([]any, 0, len(ticketIDs)+4)
+	args = append(args, orderID, now, email)
+	for _, id := range ticketIDs {
+		args = append(args, id)
+	}
+	args = append(args, now)
+
+	_, err := r.DB.Exec(query, args...)
+	if err != nil {
+		r.logger.Error("Error when consuming waitlist hold", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	mock_config "github.com/SyamSolution/transaction-service/mock/config"
+	"go.uber.org/mock/gomock"
+)
+
+var waitlistRowColumns = []string{"waitlist_id", "ticket_id", "continent", "email", "quantity", "status", "hold_until",
+	"order_id", "created_at", "updated_at"}
+
+func TestGetHeldQuantity(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+
+	r := NewWaitlistRepository(db, logger)
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"ticket_id", "quantity"}).AddRow(1, 3)
+	mock.ExpectQuery(`SELECT ticket_id, SUM\(quantity\) FROM waitlist WHERE ticket_id IN \(\?,\?\)`).
+		WithArgs(1, 2, now, "test@example.com").WillReturnRows(rows)
+
+	held, err := r.GetHeldQuantity([]int{1, 2}, "test@example.com", now)
+	if err != nil {
+		t.Errorf("error was not expected while getting held quantity: %s", err)
+	}
+	if held[1] != 3 || held[2] != 0 {
+		t.Errorf("unexpected held quantity %v", held)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPromoteWaitlist(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+
+	r := NewWaitlistRepository(db, logger)
+
+	now := time.Now()
+	holdUntil := now.Add(30 * time.Minute)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT COALESCE\(SUM\(quantity\), 0\) FROM waitlist WHERE ticket_id = \? AND status = 'notified'`).
+		WithArgs(5, now).WillReturnRows(sqlmock.NewRows([]string{"quantity"}).AddRow(1))
+	mock.ExpectExec(`UPDATE waitlist SET status = 'expired'`).WithArgs(now, 5, now).WillReturnResult(sqlmock.NewResult(0, 1))
+	// 2 dikembalikan + 1 expired, antrian ke-3 minta 2 jadi berhenti di sana
+	rows := sqlmock.NewRows(waitlistRowColumns).
+		AddRow(10, 5, "Asia", "a@example.com", 1, "waiting", nil, "", now, now).
+		AddRow(11, 5, "Asia", "b@example.com", 1, "waiting", nil, "", now, now).
+		AddRow(12, 5, "Asia", "c@example.com", 2, "waiting", nil, "", now, now).
+		AddRow(13, 5, "Asia", "d@example.com", 1, "waiting", nil, "", now, now)
+	mock.ExpectQuery(`SELECT (.+) FROM waitlist WHERE ticket_id = \? AND status = 'waiting'`).WithArgs(5).WillReturnRows(rows)
+	mock.ExpectExec(`UPDATE waitlist SET status = 'notified'`).WithArgs(holdUntil, now, 10).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE waitlist SET status = 'notified'`).WithArgs(holdUntil, now, 11).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	promoted, err := r.PromoteWaitlist(5, 2, now, holdUntil)
+	if err != nil {
+		t.Errorf("error was not expected while promoting waitlist: %s", err)
+	}
+	if len(promoted) != 2 || promoted[1].Email != "b@example.com" || promoted[1].Status != model.WaitlistNotified {
+		t.Errorf("unexpected promoted entries %+v", promoted)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestConsumeWaitlistHold(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+
+	r := NewWaitlistRepository(db, logger)
+
+	now := time.Now()
+	mock.ExpectExec(`UPDATE waitlist SET status = 'purchased'`).
+		WithArgs("ORDER-1", now, "test@example.com", 1, 2, now).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := r.ConsumeWaitlistHold("test@example.com", []int{1, 2}, "ORDER-1", now); err != nil {
+		t.Errorf("error was not expected while consuming waitlist hold: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestLeaveWaitlist(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+
+	r := NewWaitlistRepository(db, logger)
+
+	now := time.Now()
+	email := "test@example.com"
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM waitlist WHERE waitlist_id = \? AND email = \?`).WithArgs(1, email).
+		WillReturnRows(sqlmock.NewRows(waitlistRowColumns).
+			AddRow(1, 5, "Asia", email, 2, model.WaitlistNotified, now, "", now, now))
+	mock.ExpectExec(`UPDATE waitlist SET status = 'left'`).WithArgs(now, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	waitlist, err := r.LeaveWaitlist(1, email, now)
+	if err != nil {
+		t.Errorf("error was not expected while leaving waitlist: %s", err)
+	}
+	if waitlist.Status != model.WaitlistNotified || waitlist.Quantity != 2 {
+		t.Errorf("unexpected waitlist %+v", waitlist)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestGetExpiredHoldTicketIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mock_config.NewMockLogger(ctrl)
+
+	r := NewWaitlistRepository(db, logger)
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT DISTINCT ticket_id FROM waitlist WHERE status = 'notified' AND hold_until <= \?`).
+		WithArgs(now).WillReturnRows(sqlmock.NewRows([]string{"ticket_id"}).AddRow(5).AddRow(7))
+
+	ticketIDs, err := r.GetExpiredHoldTicketIDs(now)
+	if err != nil {
+		t.Errorf("error was not expected while getting expired holds: %s", err)
+	}
+	if len(ticketIDs) != 2 {
+		t.Errorf("unexpected ticket ids %v", ticketIDs)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,296 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/internal/gateway"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/repository"
+	"github.com/midtrans/midtrans-go/coreapi"
+	"go.uber.org/zap"
+)
+
+const defaultAdminSearchLimit = 20
+
+type adminUsecase struct {
+	adminRepo                  repository.AdminPersister
+	transactionRepo            repository.TransactionPersister
+	paymentShareRepo           repository.PaymentSharePersister
+	issuedTicketRepo           repository.IssuedTicketPersister
+	paymentNotificationUsecase PaymentNotificationExecutor
+	paymentGateway             gateway.PaymentGateway
+	logger                     config.Logger
+}
+
+type AdminExecutor interface {
+	SearchTransactions(request model.AdminTransactionSearchRequest, adminEmail string) (model.AdminTransactionList, error)
+	GetTransactionDetail(transactionID int, adminEmail string) (model.AdminTransactionDetail, error)
+	ResyncTransaction(transactionID int, adminEmail string) (model.AdminResyncResult, error)
+	ResendMessage(transactionID int, message, adminEmail string) error
+}
+
+func NewAdminUsecase(adminRepo repository.AdminPersister, transactionRepo repository.TransactionPersister,
+	paymentShareRepo repository.PaymentSharePersister, issuedTicketRepo repository.IssuedTicketPersister,
+	paymentNotificationUsecase PaymentNotificationExecutor, paymentGateway gateway.PaymentGateway, logger config.Logger) AdminExecutor {
+	return &adminUsecase{
+		adminRepo:                  adminRepo,
+		transactionRepo:            transactionRepo,
+		paymentShareRepo:           paymentShareRepo,
+		issuedTicketRepo:           issuedTicketRepo,
+		paymentNotificationUsecase: paymentNotificationUsecase,
+		paymentGateway:             paymentGateway,
+		logger:                     logger,
+	}
+}
+
+func (uc *adminUsecase) SearchTransactions(request model.AdminTransactionSearchRequest, adminEmail string) (model.AdminTransactionList, error) {
+	filter := model.AdminTransactionFilter{
+		OrderID:      request.OrderID,
+		Email:        request.Email,
+		MobileNumber: request.MobileNumber,
+		Status:       request.Status,
+		Continent:    request.Continent,
+		MinAmount:    request.MinAmount,
+		MaxAmount:    request.MaxAmount,
+		Limit:        request.Limit,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultAdminSearchLimit
+	}
+	page := request.Page
+	if page == 0 {
+		page = 1
+	}
+	filter.Offset = (page - 1) * filter.Limit
+
+	var err error
+	if request.From != "" {
+		if filter.From, err = time.ParseInLocation("2006-01-02", request.From, time.Local); err != nil {
+			return model.AdminTransactionList{}, ErrAdminSearch
+		}
+	}
+	if request.To != "" {
+		if filter.To, err = time.ParseInLocation("2006-01-02", request.To, time.Local); err != nil {
+			return model.AdminTransactionList{}, ErrAdminSearch
+		}
+		// to ikut dihitung satu hari penuh
+		filter.To = filter.To.AddDate(0, 0, 1)
+	}
+	if (!filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To)) ||
+		(filter.MaxAmount > 0 && filter.MinAmount > filter.MaxAmount) {
+		return model.AdminTransactionList{}, ErrAdminSearch
+	}
+
+	uc.logger.Info("Admin searched transactions", zap.String("admin", adminEmail), zap.Any("filter", filter))
+
+	transactions, total, err := uc.adminRepo.SearchTransactions(filter)
+	if err != nil {
+		uc.logger.Error("Error when searching transactions", zap.Error(err))
+		return model.AdminTransactionList{}, err
+	}
+
+	return model.AdminTransactionList{Transactions: transactions, Page: page, Limit: filter.Limit, Total: total}, nil
+}
+
+func (uc *adminUsecase) getTransaction(transactionID int) (model.Transaction, error) {
+	orderID, err := uc.adminRepo.GetOrderIDByTransactionID(transactionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Transaction{}, ErrTransactionNotFound
+		}
+		return model.Transaction{}, err
+	}
+
+	transaction, err := uc.transactionRepo.GetTransactionByOrderID(orderID)
+	if err != nil {
+		uc.logger.Error("Error when getting transaction by orderID", zap.Error(err))
+		return model.Transaction{}, err
+	}
+
+	return transaction, nil
+}
+
+func (uc *adminUsecase) GetTransactionDetail(transactionID int, adminEmail string) (model.AdminTransactionDetail, error) {
+	transaction, err := uc.getTransaction(transactionID)
+	if err != nil {
+		return model.AdminTransactionDetail{}, err
+	}
+
+	uc.logger.Info("Admin viewed transaction", zap.String("admin", adminEmail), zap.String("order_id", transaction.OrderID))
+
+	detail := model.AdminTransactionDetail{Transaction: transaction}
+	if detail.Lines, err = uc.transactionRepo.GetDetailTransactionByTransactionID(transactionID); err != nil {
+		uc.logger.Error("Error when getting detail transaction by transactionID", zap.Error(err))
+		return model.AdminTransactionDetail{}, err
+	}
+	if transaction.SplitCount > 0 {
+		if detail.PaymentShares, err = uc.paymentShareRepo.GetPaymentSharesByOrderID(transaction.OrderID); err != nil {
+			uc.logger.Error("Error when getting payment shares", zap.Error(err))
+			return model.AdminTransactionDetail{}, err
+		}
+	} else {
+		payment, err := uc.transactionRepo.GetPaymentByOrderID(transaction.OrderID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return model.AdminTransactionDetail{}, err
+		}
+		if err == nil {
+			detail.Payment = &payment
+		}
+	}
+	if detail.Events, err = uc.transactionRepo.GetTransactionEvents(transactionID); err != nil {
+		uc.logger.Error("Error when getting transaction events", zap.Error(err))
+		return model.AdminTransactionDetail{}, err
+	}
+
+	return detail, nil
+}
+
+// ResyncTransaction compares the order, or each open share of a split order, with midtrans and applies the
+// status of those that differ the way the webhook does, recorded under the admin
+func (uc *adminUsecase) ResyncTransaction(transactionID int, adminEmail string) (model.AdminResyncResult, error) {
+	transaction, err := uc.getTransaction(transactionID)
+	if err != nil {
+		return model.AdminResyncResult{}, err
+	}
+
+	result := model.AdminResyncResult{
+		OrderID:        transaction.OrderID,
+		PreviousStatus: transaction.PaymentStatus,
+		Applied:        []string{},
+	}
+
+	// order id di midtrans beserta status yang tercatat di sisi kita
+	type gatewayOrder struct {
+		orderID, localStatus string
+		statusResp           *coreapi.TransactionStatusResponse
+	}
+	var orders []gatewayOrder
+	if transaction.SplitCount == 0 {
+		orders = append(orders, gatewayOrder{orderID: gatewayOrderID(uc.transactionRepo, uc.logger, transaction.OrderID),
+			localStatus: transaction.PaymentStatus})
+	} else {
+		result.GatewayStatus = "split"
+		shares, err := uc.paymentShareRepo.GetPaymentSharesByOrderID(transaction.OrderID)
+		if err != nil {
+			uc.logger.Error("Error when getting payment shares", zap.Error(err))
+			return model.AdminResyncResult{}, err
+		}
+		for _, share := range shares {
+			if share.Status == model.PaymentSharePending {
+				orders = append(orders, gatewayOrder{orderID: share.ShareOrderID, localStatus: "pending"})
+			}
+		}
+	}
+
+	var gatewayReference string
+	var apply []gatewayOrder
+	for _, order := range orders {
+		statusResp, err := uc.paymentGateway.CheckStatus(order.orderID)
+		if err != nil {
+			if gateway.StatusCode(err) == http.StatusNotFound {
+				if transaction.SplitCount == 0 {
+					result.GatewayStatus = "not_found"
+				}
+				continue
+			}
+			uc.logger.Error("Error when checking transaction status", zap.Error(err))
+			return model.AdminResyncResult{}, err
+		}
+		if transaction.SplitCount == 0 {
+			result.GatewayStatus = statusResp.TransactionStatus
+			gatewayReference = statusResp.TransactionID
+		}
+
+		expected := expectedPaymentStatus(statusResp.TransactionStatus, statusResp.FraudStatus)
+		if expected != "" && expected != order.localStatus {
+			order.statusResp = statusResp
+			apply = append(apply, order)
+		}
+	}
+
+	// dicatat sebelum perubahan status supaya urutannya benar di history
+	uc.recordAdminAction(transaction, adminEmail, gatewayReference, "resync from midtrans, gateway status "+result.GatewayStatus)
+
+	event := model.TransactionEvent{Actor: model.TransactionActorAdmin, ActorID: adminEmail}
+	for _, order := range apply {
+		if err := uc.paymentNotificationUsecase.ApplyPaymentStatus(order.orderID, order.statusResp, event); err != nil {
+			uc.logger.Error("Error when applying payment status", zap.String("order_id", order.orderID), zap.Error(err))
+			return result, err
+		}
+		result.Applied = append(result.Applied, order.orderID)
+	}
+
+	after, err := uc.transactionRepo.GetTransactionByOrderID(transaction.OrderID)
+	if err != nil {
+		uc.logger.Error("Error when getting transaction by orderID", zap.Error(err))
+		return model.AdminResyncResult{}, err
+	}
+	result.CurrentStatus = after.PaymentStatus
+
+	return result, nil
+}
+
+func (uc *adminUsecase) ResendMessage(transactionID int, message, adminEmail string) error {
+	transaction, err := uc.getTransaction(transactionID)
+	if err != nil {
+		return err
+	}
+
+	switch message {
+	case model.AdminResendConfirmation:
+		var url string
+		var shares []model.PaymentShareResponse
+		if transaction.SplitCount > 0 {
+			paymentShares, err := uc.paymentShareRepo.GetPaymentSharesByOrderID(transaction.OrderID)
+			if err != nil {
+				uc.logger.Error("Error when getting payment shares", zap.Error(err))
+				return err
+			}
+			shares = paymentShareResponses(paymentShares)
+		} else {
+			payment, err := uc.transactionRepo.GetPaymentByOrderID(transaction.OrderID)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+			url = payment.RedirectURL
+		}
+		publishTransactionMessage(uc.logger, transactionMessage(transaction, url, shares))
+	case model.AdminResendTickets:
+		if transaction.PaymentStatus != "completed" {
+			return ErrTransactionNotCompleted
+		}
+		if err := sendTicketEmails(uc.transactionRepo, uc.issuedTicketRepo, uc.logger, transaction); err != nil {
+			return err
+		}
+	default:
+		return errors.New("unknown message " + message)
+	}
+
+	uc.recordAdminAction(transaction, adminEmail, "", "resent "+message+" message")
+
+	return nil
+}
+
+// recordAdminAction puts an admin action in the transaction history, the status stays the same
+func (uc *adminUsecase) recordAdminAction(transaction model.Transaction, adminEmail, payloadReference, note string) {
+	uc.logger.Info("Admin action on transaction", zap.String("admin", adminEmail), zap.String("order_id", transaction.OrderID),
+		zap.String("action", note))
+
+	err := uc.transactionRepo.CreateTransactionEvent(model.TransactionEvent{
+		TransactionID:    transaction.TransactionID,
+		OrderID:          transaction.OrderID,
+		Actor:            model.TransactionActorAdmin,
+		ActorID:          adminEmail,
+		PreviousStatus:   transaction.PaymentStatus,
+		NewStatus:        transaction.PaymentStatus,
+		PayloadReference: payloadReference,
+		Note:             note,
+	})
+	if err != nil {
+		uc.logger.Error("Error when recording admin action", zap.Error(err))
+	}
+}
@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/internal/gateway"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/mock"
+	mock_config "github.com/SyamSolution/transaction-service/mock/config"
+	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/midtrans/midtrans-go/snap"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAdminSearchTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAdminRepo := mock.NewMockAdminPersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+	logger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+	uc := NewAdminUsecase(mockAdminRepo, nil, nil, nil, nil, gateway.NewFakeGateway(nil), logger)
+
+	t.Run("date range includes the last day", func(t *testing.T) {
+		mockAdminRepo.EXPECT().SearchTransactions(model.AdminTransactionFilter{
+			Email:  "ani@example.com",
+			From:   time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local),
+			To:     time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local),
+			Offset: 20,
+			Limit:  20,
+		}).Return([]model.Transaction{{TransactionID: 1}}, 21, nil)
+
+		list, err := uc.SearchTransactions(model.AdminTransactionSearchRequest{Email: "ani@example.com", From: "2026-10-01",
+			To: "2026-10-18", Page: 2}, "admin@example.com")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, list.Page)
+		assert.Equal(t, 21, list.Total)
+		assert.Len(t, list.Transactions, 1)
+	})
+
+	t.Run("range ends before it starts", func(t *testing.T) {
+		_, err := uc.SearchTransactions(model.AdminTransactionSearchRequest{From: "2026-10-18", To: "2026-10-01"}, "admin@example.com")
+
+		assert.ErrorIs(t, err, ErrAdminSearch)
+	})
+
+	t.Run("min amount above max amount", func(t *testing.T) {
+		_, err := uc.SearchTransactions(model.AdminTransactionSearchRequest{MinAmount: 100, MaxAmount: 50}, "admin@example.com")
+
+		assert.ErrorIs(t, err, ErrAdminSearch)
+	})
+}
+
+func TestAdminResyncTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAdminRepo := mock.NewMockAdminPersister(ctrl)
+	mockTransactionRepo := mock.NewMockTransactionPersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+	logger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	fakeGateway := gateway.NewFakeGateway(func(payload map[string]interface{}) {})
+
+	mockPaymentNotificationUsecase := mock.NewMockPaymentNotificationExecutor(ctrl)
+	admin := model.TransactionEvent{Actor: model.TransactionActorAdmin, ActorID: "admin@example.com"}
+	// tidak ada link yang dibuat ulang
+	mockTransactionRepo.EXPECT().GetPaymentByOrderID(gomock.Any()).Return(model.Payment{}, sql.ErrNoRows).AnyTimes()
+
+	uc := NewAdminUsecase(mockAdminRepo, mockTransactionRepo, nil, nil, mockPaymentNotificationUsecase, fakeGateway, logger)
+
+	_, err := fakeGateway.CreateCharge(&snap.Request{TransactionDetails: midtrans.TransactionDetails{OrderID: "ORDER-1", GrossAmt: 150000}})
+	assert.NoError(t, err)
+	assert.NoError(t, fakeGateway.SetStatus("ORDER-1", "settlement", ""))
+
+	pending := model.Transaction{TransactionID: 1, OrderID: "ORDER-1", PaymentStatus: "pending"}
+
+	t.Run("applies a settled order that is still pending", func(t *testing.T) {
+		mockAdminRepo.EXPECT().GetOrderIDByTransactionID(1).Return("ORDER-1", nil)
+		gomock.InOrder(
+			mockTransactionRepo.EXPECT().GetTransactionByOrderID("ORDER-1").Return(pending, nil),
+			mockTransactionRepo.EXPECT().CreateTransactionEvent(gomock.Any()).DoAndReturn(func(event model.TransactionEvent) error {
+				assert.Equal(t, model.TransactionActorAdmin, event.Actor)
+				assert.Equal(t, "admin@example.com", event.ActorID)
+				assert.Equal(t, "pending", event.NewStatus)
+				return nil
+			}),
+			mockPaymentNotificationUsecase.EXPECT().ApplyPaymentStatus("ORDER-1", gomock.Any(), admin).
+				DoAndReturn(func(orderID string, statusResp *coreapi.TransactionStatusResponse, event model.TransactionEvent) error {
+					assert.Equal(t, "settlement", statusResp.TransactionStatus)
+					return nil
+				}),
+			mockTransactionRepo.EXPECT().GetTransactionByOrderID("ORDER-1").
+				Return(model.Transaction{TransactionID: 1, OrderID: "ORDER-1", PaymentStatus: "completed"}, nil),
+		)
+
+		result, err := uc.ResyncTransaction(1, "admin@example.com")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"ORDER-1"}, result.Applied)
+		assert.Equal(t, "settlement", result.GatewayStatus)
+		assert.Equal(t, "pending", result.PreviousStatus)
+		assert.Equal(t, "completed", result.CurrentStatus)
+	})
+
+	t.Run("order never charged is left alone", func(t *testing.T) {
+		mockAdminRepo.EXPECT().GetOrderIDByTransactionID(2).Return("ORDER-2", nil)
+		mockTransactionRepo.EXPECT().GetTransactionByOrderID("ORDER-2").
+			Return(model.Transaction{TransactionID: 2, OrderID: "ORDER-2", PaymentStatus: "pending"}, nil).Times(2)
+		mockTransactionRepo.EXPECT().CreateTransactionEvent(gomock.Any()).Return(nil)
+
+		result, err := uc.ResyncTransaction(2, "admin@example.com")
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Applied)
+		assert.Equal(t, "not_found", result.GatewayStatus)
+	})
+
+	t.Run("unknown transaction", func(t *testing.T) {
+		mockAdminRepo.EXPECT().GetOrderIDByTransactionID(3).Return("", sql.ErrNoRows)
+
+		_, err := uc.ResyncTransaction(3, "admin@example.com")
+
+		assert.ErrorIs(t, err, ErrTransactionNotFound)
+	})
+}
+
+func TestAdminResendMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAdminRepo := mock.NewMockAdminPersister(ctrl)
+	mockTransactionRepo := mock.NewMockTransactionPersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+
+	uc := NewAdminUsecase(mockAdminRepo, mockTransactionRepo, nil, nil, nil, gateway.NewFakeGateway(nil), logger)
+
+	mockAdminRepo.EXPECT().GetOrderIDByTransactionID(1).Return("ORDER-1", nil)
+	mockTransactionRepo.EXPECT().GetTransactionByOrderID("ORDER-1").
+		Return(model.Transaction{TransactionID: 1, OrderID: "ORDER-1", PaymentStatus: "pending"}, nil)
+
+	err := uc.ResendMessage(1, model.AdminResendTickets, "admin@example.com")
+
+	assert.ErrorIs(t, err, ErrTransactionNotCompleted)
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrTransactionNotFound    = errors.New("transaction not found")
+	ErrTransactionNotPending  = errors.New("transaction is no longer pending")
+	ErrTransactionAlreadyPaid = errors.New("transaction has already been paid")
+	ErrPaymentWindowClosed    = errors.New("payment window has closed, create a new order")
+	ErrPaymentLinkUnavailable = errors.New("payment link is not available, create a new order")
+
+	ErrInvalidLineItem     = errors.New("invalid ticket line")
+	ErrTotalTicketMismatch = errors.New("total_ticket does not match the quantities of the ticket lines")
+
+	ErrInvalidPaymentMethod    = errors.New("unsupported payment method")
+	ErrDirectChargeUnsupported = errors.New("payment method does not support direct charge")
+
+	ErrVoucherNotFound      = errors.New("voucher not found")
+	ErrVoucherInactive      = errors.New("voucher is not active")
+	ErrVoucherNotApplicable = errors.New("voucher is not valid for this order")
+	ErrVoucherMinSpend      = errors.New("order does not reach the voucher minimum spend")
+	ErrVoucherExhausted     = errors.New("voucher has been fully redeemed")
+	ErrVoucherUserLimit     = errors.New("voucher redemption limit reached")
+	ErrVoucherCodeTaken     = errors.New("voucher code already exists")
+	ErrInvalidVoucher       = errors.New("invalid voucher")
+
+	ErrInvalidSplitCount    = errors.New("invalid split count")
+	ErrSplitDirectCharge    = errors.New("split payments can not use direct charge")
+	ErrPaymentShareNotFound = errors.New("payment share not found")
+
+	ErrWaitlistNotFound       = errors.New("waitlist entry not found")
+	ErrWaitlistAlreadyJoined  = errors.New("already on the waitlist for this ticket")
+	ErrWaitlistTicketNotFound = errors.New("ticket not found")
+	ErrTicketAvailable        = errors.New("ticket is still available, buy it directly")
+
+	ErrTransferLineNotFound = errors.New("ticket line not found")
+	ErrTransferNotAllowed   = errors.New("only tickets of a completed order can be transferred")
+	ErrTransferToSelf       = errors.New("can not transfer tickets to yourself")
+	ErrTransferQuantity     = errors.New("not enough tickets to transfer")
+	ErrTransferClosed       = errors.New("transfers are closed for this event")
+	ErrTransferInvalid      = errors.New("transfer token is invalid")
+	ErrTransferExpired      = errors.New("transfer has expired")
+	ErrTransferNotFound     = errors.New("transfer not found")
+
+	ErrIssuedTicketNotFound = errors.New("ticket not found")
+	ErrTicketInvalid        = errors.New("ticket QR is invalid")
+	ErrTicketAlreadyUsed    = errors.New("ticket has already been used")
+	ErrTicketVoid           = errors.New("ticket is void, the order was cancelled")
+
+	ErrQuoteInvalid  = errors.New("quote is invalid")
+	ErrQuoteExpired  = errors.New("quote has expired")
+	ErrQuoteMismatch = errors.New("order does not match the quote")
+
+	ErrReportRange = errors.New("report range must be at most a year and not end before it starts")
+	ErrExportRange = errors.New("export range must be at most a year and not end before it starts")
+
+	ErrInvoiceNotAvailable = errors.New("invoice is only available for completed transactions")
+
+	ErrTransactionNotCompleted = errors.New("transaction is not completed")
+	ErrAdminSearch             = errors.New("search range must not end before it starts and min amount must not exceed max amount")
+)
+
+// LineItemError is a problem with one line of the order, Line is the index in detail_ticket
+type LineItemError struct {
+	Line    int
+	Field   string
+	Tag     string
+	Message string
+}
+
+// LineItemErrors holds every invalid line of an order, it matches ErrInvalidLineItem with errors.Is
+type LineItemErrors []LineItemError
+
+func (e LineItemErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, lineErr := range e {
+		messages = append(messages, fmt.Sprintf("line %d %s: %s", lineErr.Line, lineErr.Field, lineErr.Message))
+	}
+
+	return ErrInvalidLineItem.Error() + ": " + strings.Join(messages, "; ")
+}
+
+func (e LineItemErrors) Unwrap() error {
+	return ErrInvalidLineItem
+}
@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/helper"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/repository"
+	"go.uber.org/zap"
+)
+
+type gateUsecase struct {
+	issuedTicketRepo repository.IssuedTicketPersister
+	logger           config.Logger
+}
+
+type GateExecutor interface {
+	LookupTicket(code string) (model.GateTicketResponse, error)
+	CheckIn(request model.CheckInRequest, checkedInBy string) (model.IssuedTicket, error)
+	SyncCheckIns(request model.SyncCheckInRequest, checkedInBy string) ([]model.CheckInResult, error)
+	GetAttendance(ticketIDs []int) ([]model.EventAttendance, error)
+}
+
+func NewGateUsecase(issuedTicketRepo repository.IssuedTicketPersister, logger config.Logger) GateExecutor {
+	return &gateUsecase{issuedTicketRepo: issuedTicketRepo, logger: logger}
+}
+
+// LookupTicket shows a ticket with its event to the gate staff without checking it in
+func (uc *gateUsecase) LookupTicket(code string) (model.GateTicketResponse, error) {
+	ticket, err := uc.issuedTicketRepo.GetIssuedTicketByCode(normalizeTicketCode(code))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.GateTicketResponse{}, ErrIssuedTicketNotFound
+		}
+		uc.logger.Error("Error when getting issued ticket by code", zap.Error(err))
+		return model.GateTicketResponse{}, err
+	}
+
+	response := model.GateTicketResponse{Ticket: ticket}
+	// data event hanya pelengkap, lookup tetap jalan kalau ticket service sedang gangguan
+	ticketEvent, err := helper.GetTicketEventByTicketID(ticket.TicketID)
+	if err != nil {
+		uc.logger.Error("Error when getting ticket event by ticket ID", zap.Error(err))
+		return response, nil
+	}
+	response.EventName = ticketEvent.EventName
+	response.EventDate = ticketEvent.Date
+	response.Venue = ticketEvent.CountryPlace
+
+	return response, nil
+}
+
+func (uc *gateUsecase) CheckIn(request model.CheckInRequest, checkedInBy string) (model.IssuedTicket, error) {
+	code, claims, err := resolveTicketCode(request.Code, request.QRPayload)
+	if err != nil {
+		return model.IssuedTicket{}, err
+	}
+
+	return admitTicket(uc.issuedTicketRepo, uc.logger, code, claims, request.Gate, checkedInBy, time.Now())
+}
+
+// SyncCheckIns applies scans recorded offline in order. The first sync of a ticket wins, a scan of a ticket
+// already checked in elsewhere is reported as a conflict and a scan that was already synced as a duplicate.
+func (uc *gateUsecase) SyncCheckIns(request model.SyncCheckInRequest, checkedInBy string) ([]model.CheckInResult, error) {
+	now := time.Now()
+	results := make([]model.CheckInResult, 0, len(request.Scans))
+	for i, scan := range request.Scans {
+		result := model.CheckInResult{Index: i, Code: scan.Code}
+
+		code, claims, err := resolveTicketCode(scan.Code, scan.QRPayload)
+		if err != nil {
+			result.Status = model.CheckInInvalid
+			results = append(results, result)
+			continue
+		}
+		result.Code = code
+
+		scannedAt := scan.ScannedAt
+		if scannedAt.IsZero() || scannedAt.After(now) {
+			scannedAt = now
+		}
+
+		ticket, err := admitTicket(uc.issuedTicketRepo, uc.logger, code, claims, request.Gate, checkedInBy, scannedAt)
+		switch {
+		case err == nil:
+			result.Status = model.CheckInCheckedIn
+		case errors.Is(err, ErrTicketAlreadyUsed):
+			result.Status = model.CheckInConflict
+			if isSameCheckIn(ticket, request.Gate, checkedInBy, scannedAt) {
+				result.Status = model.CheckInDuplicate
+			}
+		case errors.Is(err, ErrIssuedTicketNotFound):
+			result.Status = model.CheckInNotFound
+			results = append(results, result)
+			continue
+		case errors.Is(err, ErrTicketInvalid):
+			result.Status = model.CheckInInvalid
+			results = append(results, result)
+			continue
+		case errors.Is(err, ErrTicketVoid):
+			result.Status = model.CheckInVoid
+			results = append(results, result)
+			continue
+		default:
+			return nil, err
+		}
+		result.Ticket = &ticket
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// isSameCheckIn reports whether the recorded check-in is this scan sent again, used_at is stored in seconds
+func isSameCheckIn(ticket model.IssuedTicket, gate, checkedInBy string, scannedAt time.Time) bool {
+	return ticket.UsedAt != nil && ticket.Gate == gate && ticket.CheckedInBy == checkedInBy &&
+		ticket.UsedAt.Truncate(time.Second).Equal(scannedAt.Truncate(time.Second))
+}
+
+// GetAttendance counts issued and checked in tickets, grouped per event of the ticket service
+func (uc *gateUsecase) GetAttendance(ticketIDs []int) ([]model.EventAttendance, error) {
+	attendances, err := uc.issuedTicketRepo.GetAttendance(ticketIDs)
+	if err != nil {
+		uc.logger.Error("Error when getting attendance", zap.Error(err))
+		return nil, err
+	}
+
+	var events []model.EventAttendance
+	eventIndex := make(map[string]int)
+	ticketEvents := make(map[int]model.TicketEvent)
+	for _, attendance := range attendances {
+		ticketEvent, ok := ticketEvents[attendance.TicketID]
+		if !ok {
+			ticketEvent, err = helper.GetTicketEventByTicketID(attendance.TicketID)
+			if err != nil {
+				uc.logger.Error("Error when getting ticket event by ticket ID", zap.Error(err))
+			}
+			ticketEvents[attendance.TicketID] = ticketEvent
+		}
+
+		key := ticketEvent.EventName + "|" + ticketEvent.Date.String()
+		i, ok := eventIndex[key]
+		if !ok {
+			i = len(events)
+			eventIndex[key] = i
+			events = append(events, model.EventAttendance{
+				EventName: ticketEvent.EventName,
+				EventDate: ticketEvent.Date,
+				Venue:     ticketEvent.CountryPlace,
+			})
+		}
+		events[i].Issued += attendance.Issued
+		events[i].CheckedIn += attendance.CheckedIn
+		events[i].Tickets = append(events[i].Tickets, attendance)
+	}
+
+	return events, nil
+}
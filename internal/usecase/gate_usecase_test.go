@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/util"
+	"github.com/SyamSolution/transaction-service/mock"
+	mock_config "github.com/SyamSolution/transaction-service/mock/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCheckIn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockIssuedTicketRepo := mock.NewMockIssuedTicketPersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+
+	uc := NewGateUsecase(mockIssuedTicketRepo, logger)
+
+	mockIssuedTicketRepo.EXPECT().GetIssuedTicketByCode("AAAA-BBBB").Return(model.IssuedTicket{Code: "AAAA-BBBB"}, nil)
+	mockIssuedTicketRepo.EXPECT().UseIssuedTicket("AAAA-BBBB", "A1", "scanner@example.com", gomock.Any()).Return(true, nil)
+
+	ticket, err := uc.CheckIn(model.CheckInRequest{Code: " aaaa-bbbb ", Gate: "A1"}, "scanner@example.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.IssuedTicketUsed, ticket.Status)
+	assert.Equal(t, "A1", ticket.Gate)
+}
+
+func TestCheckInQRPayload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockIssuedTicketRepo := mock.NewMockIssuedTicketPersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+
+	uc := NewGateUsecase(mockIssuedTicketRepo, logger)
+
+	t.Setenv("TICKET_QR_SECRET", "secret")
+	payload, err := util.SignToken(ticketQRSecret(), ticketQRClaims{Code: "AAAA", OrderID: "ORDER-1", TicketID: 3})
+	assert.NoError(t, err)
+	issued := model.IssuedTicket{Code: "AAAA", OrderID: "ORDER-1", TicketID: 3, Status: model.IssuedTicketIssued}
+	request := model.CheckInRequest{QRPayload: payload, Gate: "A1"}
+
+	t.Run("forged payload", func(t *testing.T) {
+		forged, _ := util.SignToken([]byte("other"), ticketQRClaims{Code: "AAAA", OrderID: "ORDER-1", TicketID: 3})
+
+		_, err := uc.CheckIn(model.CheckInRequest{QRPayload: forged, Gate: "A1"}, "scanner@example.com")
+
+		assert.ErrorIs(t, err, ErrTicketInvalid)
+	})
+
+	t.Run("first scan", func(t *testing.T) {
+		mockIssuedTicketRepo.EXPECT().GetIssuedTicketByCode("AAAA").Return(issued, nil)
+		mockIssuedTicketRepo.EXPECT().UseIssuedTicket("AAAA", "A1", "scanner@example.com", gomock.Any()).Return(true, nil)
+
+		ticket, err := uc.CheckIn(request, "scanner@example.com")
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.IssuedTicketUsed, ticket.Status)
+		assert.NotNil(t, ticket.UsedAt)
+	})
+
+	t.Run("second scan", func(t *testing.T) {
+		usedAt := time.Now()
+		used := issued
+		used.Status = model.IssuedTicketUsed
+		used.UsedAt = &usedAt
+		mockIssuedTicketRepo.EXPECT().GetIssuedTicketByCode("AAAA").Return(used, nil).Times(2)
+		mockIssuedTicketRepo.EXPECT().UseIssuedTicket("AAAA", "A1", "scanner@example.com", gomock.Any()).Return(false, nil)
+
+		ticket, err := uc.CheckIn(request, "scanner@example.com")
+
+		assert.ErrorIs(t, err, ErrTicketAlreadyUsed)
+		assert.Equal(t, &usedAt, ticket.UsedAt)
+	})
+
+	t.Run("ticket of a cancelled order", func(t *testing.T) {
+		void := issued
+		void.Status = model.IssuedTicketVoid
+		mockIssuedTicketRepo.EXPECT().GetIssuedTicketByCode("AAAA").Return(void, nil)
+
+		_, err := uc.CheckIn(request, "scanner@example.com")
+
+		assert.ErrorIs(t, err, ErrTicketVoid)
+	})
+}
+
+func TestSyncCheckIns(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockIssuedTicketRepo := mock.NewMockIssuedTicketPersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+
+	uc := NewGateUsecase(mockIssuedTicketRepo, logger)
+
+	t.Setenv("TICKET_QR_SECRET", "secret")
+	by := "scanner@example.com"
+	scannedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	otherAt := scannedAt.Add(-time.Minute)
+
+	gomock.InOrder(
+		mockIssuedTicketRepo.EXPECT().GetIssuedTicketByCode("NEW").Return(model.IssuedTicket{Code: "NEW"}, nil),
+		mockIssuedTicketRepo.EXPECT().UseIssuedTicket("NEW", "A1", by, scannedAt).Return(true, nil),
+
+		// sync yang sama dikirim ulang
+		mockIssuedTicketRepo.EXPECT().GetIssuedTicketByCode("SYNCED").Return(model.IssuedTicket{Code: "SYNCED"}, nil),
+		mockIssuedTicketRepo.EXPECT().UseIssuedTicket("SYNCED", "A1", by, scannedAt).Return(false, nil),
+		mockIssuedTicketRepo.EXPECT().GetIssuedTicketByCode("SYNCED").Return(model.IssuedTicket{Code: "SYNCED",
+			Status: model.IssuedTicketUsed, UsedAt: &scannedAt, Gate: "A1", CheckedInBy: by}, nil),
+
+		// sudah masuk lewat gate lain
+		mockIssuedTicketRepo.EXPECT().GetIssuedTicketByCode("OTHER").Return(model.IssuedTicket{Code: "OTHER"}, nil),
+		mockIssuedTicketRepo.EXPECT().UseIssuedTicket("OTHER", "A1", by, scannedAt).Return(false, nil),
+		mockIssuedTicketRepo.EXPECT().GetIssuedTicketByCode("OTHER").Return(model.IssuedTicket{Code: "OTHER",
+			Status: model.IssuedTicketUsed, UsedAt: &otherAt, Gate: "B2", CheckedInBy: "other@example.com"}, nil),
+
+		mockIssuedTicketRepo.EXPECT().GetIssuedTicketByCode("MISSING").Return(model.IssuedTicket{}, sql.ErrNoRows),
+	)
+
+	results, err := uc.SyncCheckIns(model.SyncCheckInRequest{
+		Gate: "A1",
+		Scans: []model.OfflineScan{
+			{Code: "new", ScannedAt: scannedAt},
+			{Code: "SYNCED", ScannedAt: scannedAt},
+			{Code: "OTHER", ScannedAt: scannedAt},
+			{Code: "MISSING", ScannedAt: scannedAt},
+			{QRPayload: "forged.payload", ScannedAt: scannedAt},
+		},
+	}, by)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{model.CheckInCheckedIn, model.CheckInDuplicate, model.CheckInConflict, model.CheckInNotFound, model.CheckInInvalid},
+		[]string{results[0].Status, results[1].Status, results[2].Status, results[3].Status, results[4].Status})
+	assert.Equal(t, "B2", results[2].Ticket.Gate)
+	assert.Equal(t, &otherAt, results[2].Ticket.UsedAt)
+	assert.Nil(t, results[3].Ticket)
+}
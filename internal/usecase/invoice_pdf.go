@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// formatRupiah formats whole rupiah with dot thousand separators, e.g. 1.250.000
+func formatRupiah(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+
+	digits := strconv.FormatInt(amount, 10)
+	var b strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(digit)
+	}
+
+	return sign + b.String()
+}
+
+// renderInvoicePDF renders the invoice with the core fonts of gofpdf, like the transaction export
+func renderInvoicePDF(invoice model.Invoice) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(0, 6, tr(invoice.Seller.Name), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	for _, line := range []string{invoice.Seller.Address, invoice.Seller.Email} {
+		if line != "" {
+			pdf.MultiCell(0, 5, tr(line), "", "L", false)
+		}
+	}
+	if invoice.Seller.TaxID != "" {
+		pdf.CellFormat(0, 5, tr("NPWP "+invoice.Seller.TaxID), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 8, "INVOICE", "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	for _, row := range [][2]string{
+		{"Invoice number", invoice.InvoiceNumber},
+		{"Issued at", invoice.IssuedAt.Format("02 Jan 2006 15:04")},
+		{"Order ID", invoice.OrderID},
+		{"Payment method", invoice.PaymentMethod},
+	} {
+		pdf.CellFormat(35, 6, row[0], "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 6, tr(row[1]), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(0, 6, "Bill to", "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	for _, line := range []string{invoice.Buyer.Name, invoice.Buyer.Email, invoice.Buyer.MobileNumber} {
+		if line != "" {
+			pdf.CellFormat(0, 5, tr(line), "", 1, "L", false, 0, "")
+		}
+	}
+	pdf.Ln(4)
+
+	widths := []float64{90, 20, 35, 35}
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.SetFillColor(230, 230, 230)
+	for i, header := range []string{"Description", "Qty", "Unit price", "Amount"} {
+		align := "R"
+		if i == 0 {
+			align = "L"
+		}
+		pdf.CellFormat(widths[i], 6, header, "1", 0, align, true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 9)
+	for _, line := range invoice.Lines {
+		pdf.CellFormat(widths[0], 6, tr(line.Description), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], 6, strconv.FormatInt(line.Quantity, 10), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[2], 6, formatRupiah(line.UnitPrice), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 6, formatRupiah(line.Amount), "1", 1, "R", false, 0, "")
+	}
+
+	labelWidth := widths[0] + widths[1] + widths[2]
+	summary := [][2]string{{"Subtotal", formatRupiah(invoice.Subtotal)}}
+	if invoice.Discount > 0 {
+		summary = append(summary, [2]string{"Discount", formatRupiah(-invoice.Discount)})
+	}
+	for _, row := range summary {
+		pdf.CellFormat(labelWidth, 6, row[0], "", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 6, row[1], "", 1, "R", false, 0, "")
+	}
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(labelWidth, 7, "Total ("+invoice.Currency+")", "T", 0, "R", false, 0, "")
+	pdf.CellFormat(widths[3], 7, formatRupiah(invoice.Total), "T", 1, "R", false, 0, "")
+
+	if len(invoice.Taxes) > 0 {
+		pdf.Ln(4)
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.CellFormat(0, 5, "Prices include tax:", "", 1, "L", false, 0, "")
+		pdf.CellFormat(labelWidth, 5, "Tax base (DPP)", "", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 5, formatRupiah(invoice.TaxBase), "", 1, "R", false, 0, "")
+		for _, tax := range invoice.Taxes {
+			label := fmt.Sprintf("%s %s%%", tax.Name, strconv.FormatFloat(tax.Rate, 'f', -1, 64))
+			pdf.CellFormat(labelWidth, 5, tr(label), "", 0, "R", false, 0, "")
+			pdf.CellFormat(widths[3], 5, formatRupiah(tax.Amount), "", 1, "R", false, 0, "")
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
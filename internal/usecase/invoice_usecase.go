@@ -0,0 +1,287 @@
+package usecase
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/repository"
+	"go.uber.org/zap"
+)
+
+type invoiceUsecase struct {
+	transactionRepo repository.TransactionPersister
+	invoiceRepo     repository.InvoicePersister
+	logger          config.Logger
+}
+
+type InvoiceExecutor interface {
+	CreateInvoice(orderID string) (model.Invoice, error)
+	GetInvoice(transactionID int, email string) (model.Invoice, []byte, error)
+}
+
+func NewInvoiceUsecase(transactionRepo repository.TransactionPersister, invoiceRepo repository.InvoicePersister,
+	logger config.Logger) InvoiceExecutor {
+	return &invoiceUsecase{transactionRepo: transactionRepo, invoiceRepo: invoiceRepo, logger: logger}
+}
+
+// invoicePrefix reads INVOICE_PREFIX, numbers look like INV/2026/000001
+func invoicePrefix() string {
+	if prefix := os.Getenv("INVOICE_PREFIX"); prefix != "" {
+		return prefix
+	}
+
+	return "INV"
+}
+
+func invoiceSeller() model.InvoiceSeller {
+	return model.InvoiceSeller{
+		Name:    os.Getenv("INVOICE_SELLER_NAME"),
+		Address: os.Getenv("INVOICE_SELLER_ADDRESS"),
+		TaxID:   os.Getenv("INVOICE_SELLER_TAX_ID"),
+		Email:   os.Getenv("INVOICE_SELLER_EMAIL"),
+	}
+}
+
+// invoiceTaxes reads INVOICE_TAXES as comma separated NAME:RATE pairs, e.g. "PPN:11"
+func invoiceTaxes() ([]model.InvoiceTax, error) {
+	var taxes []model.InvoiceTax
+	for _, pair := range strings.Split(os.Getenv("INVOICE_TAXES"), ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		name, rate, found := strings.Cut(pair, ":")
+		value, err := strconv.ParseFloat(strings.TrimSpace(rate), 64)
+		if !found || strings.TrimSpace(name) == "" || err != nil || value <= 0 {
+			return nil, fmt.Errorf("invalid INVOICE_TAXES entry %q", pair)
+		}
+		taxes = append(taxes, model.InvoiceTax{Name: strings.TrimSpace(name), Rate: value})
+	}
+
+	return taxes, nil
+}
+
+// CreateInvoice numbers and renders the invoice of a completed order, calling it again returns the same invoice
+func (uc *invoiceUsecase) CreateInvoice(orderID string) (model.Invoice, error) {
+	transaction, err := uc.transactionRepo.GetTransactionByOrderID(orderID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Invoice{}, ErrTransactionNotFound
+		}
+		uc.logger.Error("Error when getting transaction by orderID", zap.Error(err))
+		return model.Invoice{}, err
+	}
+
+	invoice, _, err := uc.createInvoice(transaction)
+	return invoice, err
+}
+
+func (uc *invoiceUsecase) GetInvoice(transactionID int, email string) (model.Invoice, []byte, error) {
+	transaction, err := uc.transactionRepo.GetTransactionByTransactionID(transactionID, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Invoice{}, nil, ErrTransactionNotFound
+		}
+		return model.Invoice{}, nil, err
+	}
+
+	record, err := uc.invoiceRepo.GetInvoiceByTransactionID(transactionID)
+	switch {
+	case err == nil && len(record.Data) > 0 && len(record.PDF) > 0:
+		var invoice model.Invoice
+		if err := json.Unmarshal(record.Data, &invoice); err != nil {
+			uc.logger.Error("Error when unmarshalling invoice", zap.Error(err))
+			return model.Invoice{}, nil, err
+		}
+		return invoice, record.PDF, nil
+	case err != nil && !errors.Is(err, sql.ErrNoRows):
+		uc.logger.Error("Error when getting invoice by transactionID", zap.Error(err))
+		return model.Invoice{}, nil, err
+	}
+
+	// invoice gagal dibuat saat settlement, buat sekarang
+	if transaction, err = uc.transactionRepo.GetTransactionByOrderID(transaction.OrderID); err != nil {
+		uc.logger.Error("Error when getting transaction by orderID", zap.Error(err))
+		return model.Invoice{}, nil, err
+	}
+
+	return uc.createInvoice(transaction)
+}
+
+func (uc *invoiceUsecase) createInvoice(transaction model.Transaction) (model.Invoice, []byte, error) {
+	if transaction.PaymentStatus != "completed" {
+		return model.Invoice{}, nil, ErrInvoiceNotAvailable
+	}
+
+	taxes, err := invoiceTaxes()
+	if err != nil {
+		uc.logger.Error("Error when reading invoice taxes", zap.Error(err))
+		return model.Invoice{}, nil, err
+	}
+
+	record, _, err := uc.invoiceRepo.CreateInvoice(model.InvoiceRecord{
+		TransactionID: transaction.TransactionID,
+		OrderID:       transaction.OrderID,
+		IssuedAt:      time.Now(),
+	}, invoicePrefix())
+	if err != nil {
+		uc.logger.Error("Error when creating invoice", zap.Error(err))
+		return model.Invoice{}, nil, err
+	}
+	if len(record.Data) > 0 && len(record.PDF) > 0 {
+		var invoice model.Invoice
+		if err := json.Unmarshal(record.Data, &invoice); err != nil {
+			uc.logger.Error("Error when unmarshalling invoice", zap.Error(err))
+			return model.Invoice{}, nil, err
+		}
+		return invoice, record.PDF, nil
+	}
+
+	detailTransactions, err := uc.transactionRepo.GetDetailTransactionByTransactionID(transaction.TransactionID)
+	if err != nil {
+		uc.logger.Error("Error when getting detail transaction by transactionID", zap.Error(err))
+		return model.Invoice{}, nil, err
+	}
+
+	invoice := buildInvoice(record, transaction, detailTransactions, taxes)
+	if transaction.GrossAmount > 0 && invoice.Total != transaction.GrossAmount {
+		uc.logger.Error("Invoice total differs from the charged amount", zap.String("order_id", transaction.OrderID),
+			zap.Int64("total", invoice.Total), zap.Int64("gross_amount", transaction.GrossAmount))
+	}
+
+	data, err := json.Marshal(invoice)
+	if err != nil {
+		uc.logger.Error("Error when marshalling invoice", zap.Error(err))
+		return model.Invoice{}, nil, err
+	}
+	pdf, err := renderInvoicePDF(invoice)
+	if err != nil {
+		uc.logger.Error("Error when rendering invoice pdf", zap.Error(err))
+		return model.Invoice{}, nil, err
+	}
+
+	if err = uc.invoiceRepo.UpdateInvoiceDocument(record.InvoiceID, data, pdf); err != nil {
+		uc.logger.Error("Error when updating invoice document", zap.Error(err))
+		return model.Invoice{}, nil, err
+	}
+
+	return invoice, pdf, nil
+}
+
+// buildInvoice prices the lines the same way the midtrans charge was built, so the total is what the buyer paid
+func buildInvoice(record model.InvoiceRecord, transaction model.Transaction, detailTransactions []model.DetailTransaction,
+	taxes []model.InvoiceTax) model.Invoice {
+	invoice := model.Invoice{
+		InvoiceNumber: record.InvoiceNumber,
+		TransactionID: transaction.TransactionID,
+		OrderID:       transaction.OrderID,
+		IssuedAt:      record.IssuedAt,
+		PaymentMethod: transaction.PaymentMethod,
+		Currency:      "IDR",
+		Seller:        invoiceSeller(),
+		Buyer: model.InvoiceBuyer{
+			Name:         transaction.FullName,
+			Email:        transaction.Email,
+			MobileNumber: transaction.MobileNumber,
+		},
+		Lines: []model.InvoiceLine{},
+	}
+
+	ticketPrices := make(map[int]int, len(detailTransactions))
+	for _, dt := range detailTransactions {
+		if dt.Price <= 0 {
+			// order lama tanpa harga per line, net amount sudah termasuk diskon dan voucher
+			return invoiceFromNetAmounts(invoice, transaction, detailTransactions, taxes)
+		}
+		ticketPrices[dt.TicketID] = int(math.Round(float64(dt.Price)))
+	}
+
+	itemDetails, grossAmount := buildItemDetails(detailTransactions, ticketPrices, transaction.Discount, transaction.VoucherCode,
+		transaction.VoucherDiscount)
+	for _, item := range itemDetails {
+		line := model.InvoiceLine{
+			Description: item.Name,
+			Quantity:    int64(item.Qty),
+			UnitPrice:   item.Price,
+			Amount:      item.Price * int64(item.Qty),
+		}
+		if line.Amount < 0 {
+			invoice.Discount -= line.Amount
+		} else {
+			invoice.Subtotal += line.Amount
+		}
+		invoice.Lines = append(invoice.Lines, line)
+	}
+	invoice.Total = grossAmount
+	invoice.TaxBase, invoice.Taxes = inclusiveTaxes(grossAmount, taxes)
+
+	return invoice
+}
+
+func invoiceFromNetAmounts(invoice model.Invoice, transaction model.Transaction, detailTransactions []model.DetailTransaction,
+	taxes []model.InvoiceTax) model.Invoice {
+	for _, dt := range detailTransactions {
+		if dt.NetAmount <= 0 {
+			// net amount per line juga tidak ada, tagih total order dalam satu line
+			amount := int64(math.Round(float64(transaction.TotalAmount) * idrExchangeRate))
+			invoice.Lines = []model.InvoiceLine{{Description: "Order " + transaction.OrderID, Quantity: 1, UnitPrice: amount, Amount: amount}}
+			invoice.Subtotal, invoice.Total = amount, amount
+			invoice.TaxBase, invoice.Taxes = inclusiveTaxes(amount, taxes)
+			return invoice
+		}
+
+		amount := int64(math.Round(float64(dt.NetAmount) * idrExchangeRate))
+		line := model.InvoiceLine{
+			Description: fmt.Sprintf("%s Ticket - %s", dt.TicketType, dt.City),
+			Quantity:    int64(dt.Quantity),
+			Amount:      amount,
+		}
+		if dt.Quantity > 0 {
+			line.UnitPrice = amount / int64(dt.Quantity)
+		}
+		invoice.Subtotal += amount
+		invoice.Lines = append(invoice.Lines, line)
+	}
+	invoice.Total = invoice.Subtotal
+	invoice.TaxBase, invoice.Taxes = inclusiveTaxes(invoice.Total, taxes)
+
+	return invoice
+}
+
+// inclusiveTaxes splits a tax inclusive total into the base and each tax, the last tax absorbs rounding so
+// base plus taxes is always the total
+func inclusiveTaxes(total int64, taxes []model.InvoiceTax) (int64, []model.InvoiceTax) {
+	if len(taxes) == 0 {
+		return total, []model.InvoiceTax{}
+	}
+
+	var totalRate float64
+	for _, tax := range taxes {
+		totalRate += tax.Rate
+	}
+	base := int64(math.Round(float64(total) * 100 / (100 + totalRate)))
+
+	result := make([]model.InvoiceTax, len(taxes))
+	remaining := total - base
+	for i, tax := range taxes {
+		result[i] = tax
+		if i == len(taxes)-1 {
+			result[i].Amount = remaining
+			break
+		}
+		result[i].Amount = int64(math.Round(float64(base) * tax.Rate / 100))
+		remaining -= result[i].Amount
+	}
+
+	return base, result
+}
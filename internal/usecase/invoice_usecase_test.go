@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"bytes"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/mock"
+	mock_config "github.com/SyamSolution/transaction-service/mock/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestInclusiveTaxes(t *testing.T) {
+	base, taxes := inclusiveTaxes(1110000, []model.InvoiceTax{{Name: "PPN", Rate: 11}})
+	assert.Equal(t, int64(1000000), base)
+	assert.Equal(t, []model.InvoiceTax{{Name: "PPN", Rate: 11, Amount: 110000}}, taxes)
+
+	base, taxes = inclusiveTaxes(100001, []model.InvoiceTax{{Name: "PPN", Rate: 11}, {Name: "PB1", Rate: 10}})
+	assert.Equal(t, int64(100001), base+taxes[0].Amount+taxes[1].Amount)
+
+	base, taxes = inclusiveTaxes(5000, nil)
+	assert.Equal(t, int64(5000), base)
+	assert.Empty(t, taxes)
+}
+
+func TestInvoiceTaxes(t *testing.T) {
+	t.Setenv("INVOICE_TAXES", "PPN:11, PB1:2.5")
+	taxes, err := invoiceTaxes()
+	assert.NoError(t, err)
+	assert.Equal(t, []model.InvoiceTax{{Name: "PPN", Rate: 11}, {Name: "PB1", Rate: 2.5}}, taxes)
+
+	t.Setenv("INVOICE_TAXES", "PPN")
+	_, err = invoiceTaxes()
+	assert.Error(t, err)
+}
+
+func TestCreateInvoice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionRepo := mock.NewMockTransactionPersister(ctrl)
+	mockInvoiceRepo := mock.NewMockInvoicePersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+
+	uc := NewInvoiceUsecase(mockTransactionRepo, mockInvoiceRepo, logger)
+
+	t.Setenv("INVOICE_TAXES", "PPN:11")
+	t.Setenv("INVOICE_SELLER_NAME", "PT Syam Solution")
+
+	transaction := model.Transaction{TransactionID: 7, OrderID: "ORD-7", PaymentStatus: "completed", Email: "buyer@mail.com",
+		Discount: 10, VoucherCode: "HEMAT", VoucherDiscount: 5}
+	details := []model.DetailTransaction{{TicketID: 1, TicketType: "VIP", City: "Jakarta", Quantity: 2, Price: 100}}
+
+	t.Run("numbers and renders the invoice on settlement", func(t *testing.T) {
+		issuedAt := time.Date(2026, 10, 19, 10, 0, 0, 0, time.Local)
+		mockTransactionRepo.EXPECT().GetTransactionByOrderID("ORD-7").Return(transaction, nil)
+		mockInvoiceRepo.EXPECT().CreateInvoice(gomock.Any(), "INV").
+			Return(model.InvoiceRecord{InvoiceID: 3, InvoiceNumber: "INV/2026/000042", IssuedAt: issuedAt}, true, nil)
+		mockTransactionRepo.EXPECT().GetDetailTransactionByTransactionID(7).Return(details, nil)
+		mockInvoiceRepo.EXPECT().UpdateInvoiceDocument(3, gomock.Any(), gomock.Any()).DoAndReturn(func(_ int, data, pdf []byte) error {
+			assert.Contains(t, string(data), "INV/2026/000042")
+			assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF")))
+			return nil
+		})
+
+		invoice, err := uc.CreateInvoice("ORD-7")
+
+		assert.NoError(t, err)
+		// 2 x 100 USD, diskon 10%, voucher 5 USD
+		assert.Equal(t, int64(200*idrExchangeRate), invoice.Subtotal)
+		assert.Equal(t, int64(180*idrExchangeRate-5*idrExchangeRate), invoice.Total)
+		assert.Equal(t, invoice.Subtotal-invoice.Discount, invoice.Total)
+		assert.Len(t, invoice.Lines, 3)
+		assert.Equal(t, invoice.Total, invoice.TaxBase+invoice.Taxes[0].Amount)
+		assert.Equal(t, "PT Syam Solution", invoice.Seller.Name)
+	})
+
+	t.Run("pending order", func(t *testing.T) {
+		pending := transaction
+		pending.PaymentStatus = "pending"
+		mockTransactionRepo.EXPECT().GetTransactionByOrderID("ORD-7").Return(pending, nil)
+
+		_, err := uc.CreateInvoice("ORD-7")
+
+		assert.ErrorIs(t, err, ErrInvoiceNotAvailable)
+	})
+
+	t.Run("stored invoice is returned as is", func(t *testing.T) {
+		mockTransactionRepo.EXPECT().GetTransactionByTransactionID(7, "buyer@mail.com").Return(transaction, nil)
+		mockInvoiceRepo.EXPECT().GetInvoiceByTransactionID(7).
+			Return(model.InvoiceRecord{Data: []byte(`{"invoice_number":"INV/2026/000042"}`), PDF: []byte("%PDF")}, nil)
+
+		invoice, pdf, err := uc.GetInvoice(7, "buyer@mail.com")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "INV/2026/000042", invoice.InvoiceNumber)
+		assert.Equal(t, []byte("%PDF"), pdf)
+	})
+
+	t.Run("other user's transaction", func(t *testing.T) {
+		mockTransactionRepo.EXPECT().GetTransactionByTransactionID(7, "other@mail.com").Return(model.Transaction{}, sql.ErrNoRows)
+
+		_, _, err := uc.GetInvoice(7, "other@mail.com")
+
+		assert.ErrorIs(t, err, ErrTransactionNotFound)
+	})
+}
@@ -0,0 +1,293 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/base32"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/repository"
+	"github.com/SyamSolution/transaction-service/internal/util"
+	"go.uber.org/zap"
+)
+
+type issuedTicketUsecase struct {
+	transactionRepo  repository.TransactionPersister
+	issuedTicketRepo repository.IssuedTicketPersister
+	logger           config.Logger
+}
+
+type IssuedTicketExecutor interface {
+	IssueTickets(orderID string) ([]model.IssuedTicket, error)
+	GetIssuedTickets(transactionID int, email string) ([]model.IssuedTicket, error)
+	GetOwnedTickets(email string) ([]model.IssuedTicket, error)
+	SendTicketEmails(orderID string) error
+	ResendTickets(transactionID int, email string) error
+}
+
+func NewIssuedTicketUsecase(transactionRepo repository.TransactionPersister, issuedTicketRepo repository.IssuedTicketPersister,
+	logger config.Logger) IssuedTicketExecutor {
+	return &issuedTicketUsecase{transactionRepo: transactionRepo, issuedTicketRepo: issuedTicketRepo, logger: logger}
+}
+
+// ticketQRClaims is what the QR code on a ticket carries
+type ticketQRClaims struct {
+	Code     string `json:"code"`
+	OrderID  string `json:"order_id"`
+	TicketID int    `json:"ticket_id"`
+}
+
+func ticketQRSecret() []byte {
+	return []byte(os.Getenv("TICKET_QR_SECRET"))
+}
+
+// newTicketCode returns 80 random bits as XXXX-XXXX-XXXX-XXXX
+func newTicketCode() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	encoded := base32.StdEncoding.EncodeToString(b)
+	return strings.Join([]string{encoded[0:4], encoded[4:8], encoded[8:12], encoded[12:16]}, "-"), nil
+}
+
+func withQRPayload(logger config.Logger, tickets []model.IssuedTicket) ([]model.IssuedTicket, error) {
+	for i := range tickets {
+		payload, err := util.SignToken(ticketQRSecret(), ticketQRClaims{
+			Code:     tickets[i].Code,
+			OrderID:  tickets[i].OrderID,
+			TicketID: tickets[i].TicketID,
+		})
+		if err != nil {
+			logger.Error("Error when signing ticket QR", zap.Error(err))
+			return nil, err
+		}
+		tickets[i].QRPayload = payload
+	}
+
+	return tickets, nil
+}
+
+// IssueTickets creates one ticket per unit of every line of a settled order. Calling it again returns
+// the tickets issued the first time.
+func (uc *issuedTicketUsecase) IssueTickets(orderID string) ([]model.IssuedTicket, error) {
+	if len(ticketQRSecret()) == 0 {
+		return nil, errors.New("TICKET_QR_SECRET is not set")
+	}
+
+	tickets, err := uc.issuedTicketRepo.GetIssuedTicketsByOrderID(orderID)
+	if err != nil {
+		uc.logger.Error("Error when getting issued tickets", zap.Error(err))
+		return nil, err
+	}
+	if len(tickets) > 0 {
+		return withQRPayload(uc.logger, tickets)
+	}
+
+	transaction, err := uc.transactionRepo.GetTransactionByOrderID(orderID)
+	if err != nil {
+		uc.logger.Error("Error when getting transaction by orderID", zap.Error(err))
+		return nil, err
+	}
+	detailTransactions, err := uc.transactionRepo.GetDetailTransactionByTransactionID(transaction.TransactionID)
+	if err != nil {
+		uc.logger.Error("Error when getting detail transaction by transactionID", zap.Error(err))
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, dt := range detailTransactions {
+		// ticket hadiah langsung dimiliki penerimanya
+		holderName, ownerEmail := transaction.FullName, transaction.Email
+		if dt.Holder != nil {
+			holderName, ownerEmail = dt.Holder.FullName, dt.Holder.Email
+		}
+		for unit := 1; unit <= dt.Quantity; unit++ {
+			code, err := newTicketCode()
+			if err != nil {
+				uc.logger.Error("Error when generating ticket code", zap.Error(err))
+				return nil, err
+			}
+			tickets = append(tickets, model.IssuedTicket{
+				DetailTransactionID: dt.DetailTransactionID,
+				OrderID:             orderID,
+				TicketID:            dt.TicketID,
+				UnitNumber:          unit,
+				Code:                code,
+				TicketType:          dt.TicketType,
+				HolderName:          holderName,
+				OwnerEmail:          ownerEmail,
+				Status:              model.IssuedTicketIssued,
+				CreatedAt:           now,
+				UpdatedAt:           now,
+			})
+		}
+	}
+
+	if err = uc.issuedTicketRepo.CreateIssuedTickets(tickets); err != nil {
+		uc.logger.Error("Error when creating issued tickets", zap.Error(err))
+		return nil, err
+	}
+
+	// notifikasi yang sama bisa masuk bersamaan, ambil ulang supaya kode yang dikirim sama dengan yang tersimpan
+	tickets, err = uc.issuedTicketRepo.GetIssuedTicketsByOrderID(orderID)
+	if err != nil {
+		uc.logger.Error("Error when getting issued tickets", zap.Error(err))
+		return nil, err
+	}
+
+	return withQRPayload(uc.logger, tickets)
+}
+
+func (uc *issuedTicketUsecase) GetIssuedTickets(transactionID int, email string) ([]model.IssuedTicket, error) {
+	transaction, err := uc.transactionRepo.GetTransactionByTransactionID(transactionID, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTransactionNotFound
+		}
+		return nil, err
+	}
+
+	tickets, err := uc.issuedTicketRepo.GetIssuedTicketsByOrderID(transaction.OrderID)
+	if err != nil {
+		uc.logger.Error("Error when getting issued tickets", zap.Error(err))
+		return nil, err
+	}
+
+	return withQRPayload(uc.logger, ownedTickets(tickets, transaction.Email))
+}
+
+// GetOwnedTickets lists every ticket the user holds, including the ones received through a transfer
+func (uc *issuedTicketUsecase) GetOwnedTickets(email string) ([]model.IssuedTicket, error) {
+	tickets, err := uc.issuedTicketRepo.GetIssuedTicketsByOwner(email)
+	if err != nil {
+		uc.logger.Error("Error when getting issued tickets by owner", zap.Error(err))
+		return nil, err
+	}
+
+	return withQRPayload(uc.logger, tickets)
+}
+
+// ownedTickets drops the units transferred away, the buyer must not see the codes of the new owner
+func ownedTickets(tickets []model.IssuedTicket, email string) []model.IssuedTicket {
+	owned := make([]model.IssuedTicket, 0, len(tickets))
+	for _, ticket := range tickets {
+		if strings.EqualFold(ticket.OwnerEmail, email) {
+			owned = append(owned, ticket)
+		}
+	}
+
+	return owned
+}
+
+// SendTicketEmails publishes the ticket emails of an order whose tickets have been issued
+func (uc *issuedTicketUsecase) SendTicketEmails(orderID string) error {
+	transaction, err := uc.transactionRepo.GetTransactionByOrderID(orderID)
+	if err != nil {
+		uc.logger.Error("Error when getting transaction by orderID", zap.Error(err))
+		return err
+	}
+
+	return sendTicketEmails(uc.transactionRepo, uc.issuedTicketRepo, uc.logger, transaction)
+}
+
+// ResendTickets sends the ticket emails of a completed order again at the buyer's request
+func (uc *issuedTicketUsecase) ResendTickets(transactionID int, email string) error {
+	transaction, err := uc.transactionRepo.GetTransactionByTransactionID(transactionID, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrTransactionNotFound
+		}
+		return err
+	}
+	if transaction.PaymentStatus != "completed" {
+		return ErrTransactionNotCompleted
+	}
+
+	if err := sendTicketEmails(uc.transactionRepo, uc.issuedTicketRepo, uc.logger, transaction); err != nil {
+		return err
+	}
+
+	err = uc.transactionRepo.CreateTransactionEvent(model.TransactionEvent{
+		TransactionID:  transaction.TransactionID,
+		OrderID:        transaction.OrderID,
+		Actor:          model.TransactionActorUser,
+		ActorID:        email,
+		PreviousStatus: transaction.PaymentStatus,
+		NewStatus:      transaction.PaymentStatus,
+		Note:           "resent tickets",
+	})
+	if err != nil {
+		uc.logger.Error("Error when recording ticket resend", zap.Error(err))
+	}
+
+	return nil
+}
+
+// normalizeTicketCode lets staff type codes in lower case
+func normalizeTicketCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// resolveTicketCode takes the code from a signed QR payload when given, otherwise the typed code
+func resolveTicketCode(code, qrPayload string) (string, *ticketQRClaims, error) {
+	if qrPayload == "" {
+		return normalizeTicketCode(code), nil, nil
+	}
+
+	var claims ticketQRClaims
+	if len(ticketQRSecret()) == 0 {
+		return "", nil, ErrTicketInvalid
+	}
+	if err := util.VerifyToken(ticketQRSecret(), qrPayload, &claims); err != nil {
+		return "", nil, ErrTicketInvalid
+	}
+
+	return claims.Code, &claims, nil
+}
+
+// admitTicket checks a ticket in exactly once, the conditional update decides between concurrent scans.
+// Tickets of a cancelled order are void and never admitted.
+func admitTicket(issuedTicketRepo repository.IssuedTicketPersister, logger config.Logger, code string, claims *ticketQRClaims,
+	gate, checkedInBy string, at time.Time) (model.IssuedTicket, error) {
+	ticket, err := issuedTicketRepo.GetIssuedTicketByCode(code)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.IssuedTicket{}, ErrIssuedTicketNotFound
+		}
+		logger.Error("Error when getting issued ticket by code", zap.Error(err))
+		return model.IssuedTicket{}, err
+	}
+	if claims != nil && (ticket.OrderID != claims.OrderID || ticket.TicketID != claims.TicketID) {
+		return model.IssuedTicket{}, ErrTicketInvalid
+	}
+	if ticket.Status == model.IssuedTicketVoid {
+		return ticket, ErrTicketVoid
+	}
+
+	used, err := issuedTicketRepo.UseIssuedTicket(ticket.Code, gate, checkedInBy, at)
+	if err != nil {
+		logger.Error("Error when using issued ticket", zap.Error(err))
+		return model.IssuedTicket{}, err
+	}
+	if !used {
+		// discan bersamaan di gate lain, ambil waktu pakai yang tersimpan
+		if ticket, err = issuedTicketRepo.GetIssuedTicketByCode(code); err != nil {
+			logger.Error("Error when getting issued ticket by code", zap.Error(err))
+			return model.IssuedTicket{}, err
+		}
+		return ticket, ErrTicketAlreadyUsed
+	}
+	ticket.Status = model.IssuedTicketUsed
+	ticket.UsedAt = &at
+	ticket.Gate = gate
+	ticket.CheckedInBy = checkedInBy
+
+	return ticket, nil
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/util"
+	"github.com/SyamSolution/transaction-service/mock"
+	mock_config "github.com/SyamSolution/transaction-service/mock/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestIssueTickets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionRepo := mock.NewMockTransactionPersister(ctrl)
+	mockIssuedTicketRepo := mock.NewMockIssuedTicketPersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+
+	uc := NewIssuedTicketUsecase(mockTransactionRepo, mockIssuedTicketRepo, logger)
+
+	t.Setenv("TICKET_QR_SECRET", "secret")
+	orderID := "ORDER-1"
+
+	t.Run("one ticket per unit", func(t *testing.T) {
+		mockIssuedTicketRepo.EXPECT().GetIssuedTicketsByOrderID(orderID).Return(nil, nil)
+		mockTransactionRepo.EXPECT().GetTransactionByOrderID(orderID).Return(model.Transaction{TransactionID: 1, FullName: "Ani", Email: "ani@example.com"}, nil)
+		mockTransactionRepo.EXPECT().GetDetailTransactionByTransactionID(1).Return([]model.DetailTransaction{
+			{DetailTransactionID: 10, TicketID: 3, TicketType: "VIP", Quantity: 2},
+			{DetailTransactionID: 11, TicketID: 4, TicketType: "Regular", Quantity: 1, Holder: &model.TicketHolder{FullName: "Budi", Email: "budi@example.com"}},
+		}, nil)
+
+		var created []model.IssuedTicket
+		mockIssuedTicketRepo.EXPECT().CreateIssuedTickets(gomock.Any()).DoAndReturn(func(tickets []model.IssuedTicket) error {
+			created = tickets
+			return nil
+		})
+		mockIssuedTicketRepo.EXPECT().GetIssuedTicketsByOrderID(orderID).DoAndReturn(func(string) ([]model.IssuedTicket, error) {
+			return created, nil
+		})
+
+		tickets, err := uc.IssueTickets(orderID)
+
+		assert.NoError(t, err)
+		assert.Len(t, tickets, 3)
+		assert.Equal(t, []int{1, 2, 1}, []int{tickets[0].UnitNumber, tickets[1].UnitNumber, tickets[2].UnitNumber})
+		assert.Equal(t, "Ani", tickets[0].HolderName)
+		assert.Equal(t, "Budi", tickets[2].HolderName)
+		assert.Equal(t, "ani@example.com", tickets[0].OwnerEmail)
+		// baris hadiah dimiliki penerimanya
+		assert.Equal(t, "budi@example.com", tickets[2].OwnerEmail)
+		assert.NotEqual(t, tickets[0].Code, tickets[1].Code)
+		assert.Regexp(t, `^[A-Z2-7]{4}-[A-Z2-7]{4}-[A-Z2-7]{4}-[A-Z2-7]{4}$`, tickets[0].Code)
+
+		var claims ticketQRClaims
+		assert.NoError(t, util.VerifyToken(ticketQRSecret(), tickets[0].QRPayload, &claims))
+		assert.Equal(t, ticketQRClaims{Code: tickets[0].Code, OrderID: orderID, TicketID: 3}, claims)
+	})
+
+	t.Run("already issued", func(t *testing.T) {
+		mockIssuedTicketRepo.EXPECT().GetIssuedTicketsByOrderID(orderID).Return([]model.IssuedTicket{{Code: "AAAA", OrderID: orderID}}, nil)
+
+		tickets, err := uc.IssueTickets(orderID)
+
+		assert.NoError(t, err)
+		assert.Len(t, tickets, 1)
+		assert.NotEmpty(t, tickets[0].QRPayload)
+	})
+}
+
+func TestGetIssuedTicketsHidesTransferred(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionRepo := mock.NewMockTransactionPersister(ctrl)
+	mockIssuedTicketRepo := mock.NewMockIssuedTicketPersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+
+	uc := NewIssuedTicketUsecase(mockTransactionRepo, mockIssuedTicketRepo, logger)
+
+	t.Setenv("TICKET_QR_SECRET", "secret")
+	email := "ani@example.com"
+	mockTransactionRepo.EXPECT().GetTransactionByTransactionID(1, email).
+		Return(model.Transaction{TransactionID: 1, OrderID: "ORDER-1", Email: "Ani@example.com"}, nil)
+	mockIssuedTicketRepo.EXPECT().GetIssuedTicketsByOrderID("ORDER-1").Return([]model.IssuedTicket{
+		{Code: "AAAA", OrderID: "ORDER-1", OwnerEmail: "ani@example.com"},
+		{Code: "BBBB", OrderID: "ORDER-1", OwnerEmail: "friend@example.com"},
+	}, nil)
+
+	tickets, err := uc.GetIssuedTickets(1, email)
+
+	assert.NoError(t, err)
+	assert.Len(t, tickets, 1)
+	assert.Equal(t, "AAAA", tickets[0].Code)
+}
+
+func TestResendTickets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionRepo := mock.NewMockTransactionPersister(ctrl)
+	mockIssuedTicketRepo := mock.NewMockIssuedTicketPersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+
+	uc := NewIssuedTicketUsecase(mockTransactionRepo, mockIssuedTicketRepo, logger)
+
+	mockTransactionRepo.EXPECT().GetTransactionByTransactionID(1, "test@example.com").Return(model.Transaction{}, sql.ErrNoRows)
+	assert.ErrorIs(t, uc.ResendTickets(1, "test@example.com"), ErrTransactionNotFound)
+
+	mockTransactionRepo.EXPECT().GetTransactionByTransactionID(2, "test@example.com").
+		Return(model.Transaction{TransactionID: 2, OrderID: "ORDER-2", PaymentStatus: "pending"}, nil)
+	assert.ErrorIs(t, uc.ResendTickets(2, "test@example.com"), ErrTransactionNotCompleted)
+}
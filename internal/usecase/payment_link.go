@@ -0,0 +1,265 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/internal/gateway"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/repository"
+	"github.com/SyamSolution/transaction-service/internal/util"
+	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/midtrans/midtrans-go/snap"
+	"go.uber.org/zap"
+)
+
+// paymentLinkOrderID is the midtrans order_id of a snap link, the first link is paid under the order_id itself
+func paymentLinkOrderID(orderID string, linkNumber int) string {
+	if linkNumber == 0 {
+		return orderID
+	}
+
+	return fmt.Sprintf("%s-R%d", orderID, linkNumber)
+}
+
+// parsePaymentLinkOrderID splits the midtrans order_id <order_id>-R<n> of a link made again
+func parsePaymentLinkOrderID(gatewayOrderID string) (string, int, bool) {
+	i := strings.LastIndex(gatewayOrderID, "-R")
+	if i <= 0 {
+		return "", 0, false
+	}
+	linkNumber, err := strconv.Atoi(gatewayOrderID[i+2:])
+	if err != nil || linkNumber < 1 {
+		return "", 0, false
+	}
+
+	return gatewayOrderID[:i], linkNumber, true
+}
+
+// gatewayOrderID is the midtrans order_id of the current link of an order that is not split
+func gatewayOrderID(transactionRepo repository.TransactionPersister, logger config.Logger, orderID string) string {
+	payment, err := transactionRepo.GetPaymentByOrderID(orderID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logger.Error("Error when getting payment by orderID", zap.Error(err))
+		}
+		return orderID
+	}
+
+	return paymentLinkOrderID(orderID, payment.LinkNumber)
+}
+
+// savePayment only logs, the payment already exists at midtrans
+func (uc *transactionUsecase) savePayment(payment model.Payment) {
+	if err := uc.transactionRepo.SavePayment(payment); err != nil {
+		uc.logger.Error("Error when saving payment", zap.Error(err))
+	}
+}
+
+func paymentResponse(payment model.Payment) *model.PaymentResponse {
+	return &model.PaymentResponse{
+		Token:                payment.Token,
+		RedirectURL:          payment.RedirectURL,
+		VANumber:             payment.VANumber,
+		QRString:             payment.QRString,
+		GatewayTransactionID: payment.GatewayTransactionID,
+		ExpiresAt:            payment.ExpiresAt,
+	}
+}
+
+// RecordGatewayPayment keeps the midtrans transaction id and the VA number from a notification of an order that
+// is not split, with snap they are only known once the customer picks a payment channel
+func (uc *transactionUsecase) RecordGatewayPayment(orderID string, status *coreapi.TransactionStatusResponse) error {
+	payment := model.Payment{
+		OrderID:              orderID,
+		GatewayTransactionID: status.TransactionID,
+		VANumber:             status.PermataVaNumber,
+	}
+	if len(status.VaNumbers) > 0 {
+		payment.VANumber = status.VaNumbers[0].VANumber
+	}
+
+	return uc.transactionRepo.SavePayment(payment)
+}
+
+// ResolvePaymentLink maps the midtrans order_id of a notification back to the order. current is false for a link
+// that was replaced by a newer one.
+func (uc *transactionUsecase) ResolvePaymentLink(gatewayOrderID string) (string, bool, error) {
+	orderID, linkNumber, ok := parsePaymentLinkOrderID(gatewayOrderID)
+	if !ok {
+		orderID = gatewayOrderID
+	}
+
+	payment, err := uc.transactionRepo.GetPaymentByOrderID(orderID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// bukan link yang dibuat ulang, order_id-nya kebetulan berakhiran -R<n>
+			return gatewayOrderID, true, nil
+		}
+		uc.logger.Error("Error when getting payment by orderID", zap.Error(err))
+		return "", false, err
+	}
+	if ok && linkNumber > payment.LinkNumber {
+		return gatewayOrderID, true, nil
+	}
+
+	return orderID, linkNumber == payment.LinkNumber, nil
+}
+
+// ResendPaymentLink returns how to pay a pending order and sends the confirmation email again. A link that was never
+// stored or has expired is made again while the payment window is open, see regeneratePaymentLink.
+func (uc *transactionUsecase) ResendPaymentLink(transactionID int, email string) (model.PaymentLinkResponse, error) {
+	owned, err := uc.transactionRepo.GetTransactionByTransactionID(transactionID, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.PaymentLinkResponse{}, ErrTransactionNotFound
+		}
+		return model.PaymentLinkResponse{}, err
+	}
+
+	transaction, err := uc.transactionRepo.GetTransactionByOrderID(owned.OrderID)
+	if err != nil {
+		uc.logger.Error("Error when getting transaction by orderID", zap.Error(err))
+		return model.PaymentLinkResponse{}, err
+	}
+	if transaction.PaymentStatus != "pending" {
+		return model.PaymentLinkResponse{}, ErrTransactionNotPending
+	}
+
+	now := time.Now()
+	response := model.PaymentLinkResponse{OrderID: transaction.OrderID}
+	if transaction.SplitCount > 0 {
+		// tiap bagian punya link sendiri yang berlaku sampai split deadline
+		if transaction.SplitDeadline != nil && !now.Before(*transaction.SplitDeadline) {
+			return model.PaymentLinkResponse{}, ErrPaymentWindowClosed
+		}
+		shares, err := uc.paymentShareRepo.GetPaymentSharesByOrderID(transaction.OrderID)
+		if err != nil {
+			uc.logger.Error("Error when getting payment shares", zap.Error(err))
+			return model.PaymentLinkResponse{}, err
+		}
+		response.Shares = paymentShareResponses(shares)
+		response.ExpiresAt = transaction.SplitDeadline
+	} else {
+		if !now.Before(transaction.CreatedAt.Add(util.PaymentWindow())) {
+			return model.PaymentLinkResponse{}, ErrPaymentWindowClosed
+		}
+
+		payment, err := uc.transactionRepo.GetPaymentByOrderID(transaction.OrderID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			uc.logger.Error("Error when getting payment by orderID", zap.Error(err))
+			return model.PaymentLinkResponse{}, err
+		}
+		if (payment.Token == "" && payment.VANumber == "" && payment.QRString == "") ||
+			(payment.ExpiresAt != nil && !now.Before(*payment.ExpiresAt)) {
+			payment, err = uc.regeneratePaymentLink(transaction, payment, now)
+			if err != nil {
+				return model.PaymentLinkResponse{}, err
+			}
+		}
+
+		response.Token = payment.Token
+		response.RedirectURL = payment.RedirectURL
+		response.VANumber = payment.VANumber
+		response.QRString = payment.QRString
+		response.ExpiresAt = payment.ExpiresAt
+	}
+
+	publishTransactionMessage(uc.logger, transactionMessage(transaction, response.RedirectURL, response.Shares))
+
+	err = uc.transactionRepo.CreateTransactionEvent(model.TransactionEvent{
+		TransactionID:  transaction.TransactionID,
+		OrderID:        transaction.OrderID,
+		Actor:          model.TransactionActorUser,
+		ActorID:        email,
+		PreviousStatus: transaction.PaymentStatus,
+		NewStatus:      transaction.PaymentStatus,
+		Note:           "resent payment link",
+	})
+	if err != nil {
+		uc.logger.Error("Error when recording payment link resend", zap.Error(err))
+	}
+
+	return response, nil
+}
+
+// regeneratePaymentLink opens a new snap payment that ends with the payment window. Midtrans does not take an
+// order_id twice, so the n-th new link is paid under <order_id>-R<n>. The previous link is cancelled first so the
+// order can not be paid twice.
+func (uc *transactionUsecase) regeneratePaymentLink(transaction model.Transaction, previous model.Payment, now time.Time) (model.Payment, error) {
+	// order lama belum menyimpan gross amount, nominalnya tidak bisa dipastikan
+	if transaction.GrossAmount == 0 {
+		return model.Payment{}, ErrPaymentLinkUnavailable
+	}
+	expiresAt := transaction.CreatedAt.Add(util.PaymentWindow())
+	window := expiresAt.Sub(now)
+	if window < time.Minute {
+		return model.Payment{}, ErrPaymentWindowClosed
+	}
+
+	previousOrderID := paymentLinkOrderID(transaction.OrderID, previous.LinkNumber)
+	statusResp, err := uc.paymentGateway.CheckStatus(previousOrderID)
+	switch {
+	case err == nil:
+		switch expectedPaymentStatus(statusResp.TransactionStatus, statusResp.FraudStatus) {
+		case "completed":
+			return model.Payment{}, ErrTransactionAlreadyPaid
+		case "pending":
+			if _, err := uc.paymentGateway.Cancel(previousOrderID); err != nil {
+				uc.logger.Error("Error when cancelling previous payment link", zap.Error(err))
+				return model.Payment{}, err
+			}
+		}
+	case gateway.StatusCode(err) != http.StatusNotFound:
+		uc.logger.Error("Error when checking transaction status", zap.Error(err))
+		return model.Payment{}, err
+	}
+
+	payment := model.Payment{OrderID: transaction.OrderID, LinkNumber: previous.LinkNumber + 1, ExpiresAt: &expiresAt}
+	items := []midtrans.ItemDetails{{
+		ID:    "ORDER",
+		Name:  "Order " + transaction.OrderID,
+		Price: transaction.GrossAmount,
+		Qty:   1,
+	}}
+	req := &snap.Request{
+		TransactionDetails: midtrans.TransactionDetails{
+			OrderID:  paymentLinkOrderID(transaction.OrderID, payment.LinkNumber),
+			GrossAmt: transaction.GrossAmount,
+		},
+		Items: &items,
+		CustomerDetail: &midtrans.CustomerDetails{
+			FName: transaction.FullName,
+			Email: transaction.Email,
+			Phone: transaction.MobileNumber,
+		},
+		EnabledPayments: gateway.EnabledPayments(transaction.PaymentMethod),
+		Expiry: &snap.ExpiryDetails{
+			StartTime: now.Format(midtransTimeFormat),
+			Unit:      "minute",
+			Duration:  int64(window.Minutes()),
+		},
+	}
+	if finishURL := os.Getenv("MIDTRANS_FINISH_URL"); finishURL != "" {
+		req.Callbacks = &snap.Callbacks{Finish: finishURL}
+	}
+
+	snapResp, err := uc.paymentGateway.CreateCharge(req)
+	if err != nil {
+		uc.logger.Error("Error when creating payment", zap.Error(err))
+		return model.Payment{}, err
+	}
+	payment.Token = snapResp.Token
+	payment.RedirectURL = snapResp.RedirectURL
+	uc.savePayment(payment)
+
+	return payment, nil
+}
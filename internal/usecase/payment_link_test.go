@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/helper"
+	"github.com/SyamSolution/transaction-service/internal/discount"
+	"github.com/SyamSolution/transaction-service/internal/gateway"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	mock "github.com/SyamSolution/transaction-service/mock"
+	mock_config "github.com/SyamSolution/transaction-service/mock/config"
+	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/midtrans/midtrans-go/snap"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestResendPaymentLink(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionRepo := mock.NewMockTransactionPersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+
+	fakeGateway := gateway.NewFakeGateway(nil)
+	uc := NewTransactionUsecase(mockTransactionRepo, nil, nil, nil, fakeGateway,
+		helper.NewULIDOrderIDGenerator("ORDER-TEST-"), discount.NewGrulePolicy(), logger)
+
+	email := "test@example.com"
+
+	t.Run("not owned", func(t *testing.T) {
+		mockTransactionRepo.EXPECT().GetTransactionByTransactionID(1, email).Return(model.Transaction{}, sql.ErrNoRows)
+
+		_, err := uc.ResendPaymentLink(1, email)
+		assert.ErrorIs(t, err, ErrTransactionNotFound)
+	})
+
+	t.Run("already paid", func(t *testing.T) {
+		mockTransactionRepo.EXPECT().GetTransactionByTransactionID(2, email).Return(model.Transaction{OrderID: "ORDER-2"}, nil)
+		mockTransactionRepo.EXPECT().GetTransactionByOrderID("ORDER-2").
+			Return(model.Transaction{TransactionID: 2, OrderID: "ORDER-2", PaymentStatus: "completed"}, nil)
+
+		_, err := uc.ResendPaymentLink(2, email)
+		assert.ErrorIs(t, err, ErrTransactionNotPending)
+	})
+
+	t.Run("payment window closed", func(t *testing.T) {
+		t.Setenv("PAYMENT_WINDOW", "1h")
+		mockTransactionRepo.EXPECT().GetTransactionByTransactionID(3, email).Return(model.Transaction{OrderID: "ORDER-3"}, nil)
+		mockTransactionRepo.EXPECT().GetTransactionByOrderID("ORDER-3").
+			Return(model.Transaction{TransactionID: 3, OrderID: "ORDER-3", PaymentStatus: "pending",
+				CreatedAt: time.Now().Add(-2 * time.Hour)}, nil)
+
+		_, err := uc.ResendPaymentLink(3, email)
+		assert.ErrorIs(t, err, ErrPaymentWindowClosed)
+	})
+
+	t.Run("legacy order without gross amount", func(t *testing.T) {
+		mockTransactionRepo.EXPECT().GetTransactionByTransactionID(4, email).Return(model.Transaction{OrderID: "ORDER-4"}, nil)
+		mockTransactionRepo.EXPECT().GetTransactionByOrderID("ORDER-4").
+			Return(model.Transaction{TransactionID: 4, OrderID: "ORDER-4", PaymentStatus: "pending", CreatedAt: time.Now()}, nil)
+		mockTransactionRepo.EXPECT().GetPaymentByOrderID("ORDER-4").Return(model.Payment{}, sql.ErrNoRows)
+
+		_, err := uc.ResendPaymentLink(4, email)
+		assert.ErrorIs(t, err, ErrPaymentLinkUnavailable)
+	})
+
+	t.Run("previous link already paid", func(t *testing.T) {
+		_, err := fakeGateway.CreateCharge(&snap.Request{TransactionDetails: midtrans.TransactionDetails{OrderID: "ORDER-6", GrossAmt: 150000}})
+		assert.Nil(t, err)
+		assert.Nil(t, fakeGateway.SetStatus("ORDER-6", "settlement", ""))
+
+		mockTransactionRepo.EXPECT().GetTransactionByTransactionID(6, email).Return(model.Transaction{OrderID: "ORDER-6"}, nil)
+		mockTransactionRepo.EXPECT().GetTransactionByOrderID("ORDER-6").
+			Return(model.Transaction{TransactionID: 6, OrderID: "ORDER-6", PaymentStatus: "pending", GrossAmount: 150000,
+				CreatedAt: time.Now()}, nil)
+		mockTransactionRepo.EXPECT().GetPaymentByOrderID("ORDER-6").Return(model.Payment{}, sql.ErrNoRows)
+
+		_, err = uc.ResendPaymentLink(6, email)
+		assert.ErrorIs(t, err, ErrTransactionAlreadyPaid)
+	})
+}
+
+func TestResendPaymentLinkRegenerates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionRepo := mock.NewMockTransactionPersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+	logger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+
+	fakeGateway := gateway.NewFakeGateway(nil)
+	uc := NewTransactionUsecase(mockTransactionRepo, nil, nil, nil, fakeGateway,
+		helper.NewULIDOrderIDGenerator("ORDER-TEST-"), discount.NewGrulePolicy(), logger)
+
+	t.Setenv("PAYMENT_WINDOW", "2h")
+	// email konfirmasi dikirim lewat SQS, jangan tunggu kredensial dari metadata EC2
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+	email := "test@example.com"
+	createdAt := time.Now().Add(-time.Hour)
+	pending := model.Transaction{TransactionID: 1, OrderID: "ORDER-1", PaymentStatus: "pending", GrossAmount: 150000,
+		PaymentMethod: "bca_va", Email: email, CreatedAt: createdAt}
+
+	t.Run("link never stored", func(t *testing.T) {
+		// link pertama tidak tersimpan tapi masih terbuka di midtrans
+		_, err := fakeGateway.CreateCharge(&snap.Request{TransactionDetails: midtrans.TransactionDetails{OrderID: "ORDER-1", GrossAmt: 150000}})
+		assert.Nil(t, err)
+		assert.Nil(t, fakeGateway.Pay("ORDER-1", "bank_transfer"))
+
+		var saved model.Payment
+		mockTransactionRepo.EXPECT().GetTransactionByTransactionID(1, email).Return(pending, nil)
+		mockTransactionRepo.EXPECT().GetTransactionByOrderID("ORDER-1").Return(pending, nil)
+		mockTransactionRepo.EXPECT().GetPaymentByOrderID("ORDER-1").Return(model.Payment{}, sql.ErrNoRows)
+		mockTransactionRepo.EXPECT().SavePayment(gomock.Any()).DoAndReturn(func(payment model.Payment) error {
+			saved = payment
+			return nil
+		})
+		mockTransactionRepo.EXPECT().CreateTransactionEvent(gomock.Any()).Return(nil)
+
+		response, err := uc.ResendPaymentLink(1, email)
+
+		assert.Nil(t, err)
+		assert.NotEmpty(t, response.Token)
+		assert.Equal(t, response.Token, saved.Token)
+		assert.Equal(t, 1, saved.LinkNumber)
+		if assert.NotNil(t, response.ExpiresAt) {
+			assert.WithinDuration(t, createdAt.Add(2*time.Hour), *response.ExpiresAt, time.Second)
+		}
+
+		previous, err := fakeGateway.CheckStatus("ORDER-1")
+		assert.Nil(t, err)
+		assert.Equal(t, "cancel", previous.TransactionStatus)
+		assert.Nil(t, fakeGateway.Pay("ORDER-1-R1", "bank_transfer"))
+		regenerated, err := fakeGateway.CheckStatus("ORDER-1-R1")
+		if assert.Nil(t, err) {
+			assert.Equal(t, "150000.00", regenerated.GrossAmount)
+		}
+	})
+
+	t.Run("stored link expired", func(t *testing.T) {
+		expiresAt := time.Now().Add(-time.Minute)
+		mockTransactionRepo.EXPECT().GetTransactionByTransactionID(1, email).Return(pending, nil)
+		mockTransactionRepo.EXPECT().GetTransactionByOrderID("ORDER-1").Return(pending, nil)
+		mockTransactionRepo.EXPECT().GetPaymentByOrderID("ORDER-1").
+			Return(model.Payment{OrderID: "ORDER-1", Token: "token-1", LinkNumber: 1, ExpiresAt: &expiresAt}, nil)
+		mockTransactionRepo.EXPECT().SavePayment(gomock.Any()).DoAndReturn(func(payment model.Payment) error {
+			assert.Equal(t, 2, payment.LinkNumber)
+			return nil
+		})
+		mockTransactionRepo.EXPECT().CreateTransactionEvent(gomock.Any()).Return(nil)
+
+		response, err := uc.ResendPaymentLink(1, email)
+
+		assert.Nil(t, err)
+		assert.NotEqual(t, "token-1", response.Token)
+		assert.Nil(t, fakeGateway.Pay("ORDER-1-R2", "bank_transfer"))
+	})
+}
+
+func TestResolvePaymentLink(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionRepo := mock.NewMockTransactionPersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+
+	uc := NewTransactionUsecase(mockTransactionRepo, nil, nil, nil, gateway.NewFakeGateway(nil),
+		helper.NewULIDOrderIDGenerator("ORDER-TEST-"), discount.NewGrulePolicy(), logger)
+
+	mockTransactionRepo.EXPECT().GetPaymentByOrderID("ORDER-1").Return(model.Payment{OrderID: "ORDER-1", LinkNumber: 2}, nil).Times(3)
+	mockTransactionRepo.EXPECT().GetPaymentByOrderID("ORDER").Return(model.Payment{}, sql.ErrNoRows)
+
+	for _, tc := range []struct {
+		gatewayOrderID, orderID string
+		current                 bool
+	}{
+		{"ORDER-1-R2", "ORDER-1", true},
+		{"ORDER-1-R1", "ORDER-1", false},
+		{"ORDER-1", "ORDER-1", false},
+		{"ORDER-R7", "ORDER-R7", true},
+	} {
+		orderID, current, err := uc.ResolvePaymentLink(tc.gatewayOrderID)
+		assert.Nil(t, err)
+		assert.Equal(t, tc.orderID, orderID, tc.gatewayOrderID)
+		assert.Equal(t, tc.current, current, tc.gatewayOrderID)
+	}
+}
+
+func TestGetTransactionByTransactionIDPendingPayment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionRepo := mock.NewMockTransactionPersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+
+	uc := NewTransactionUsecase(mockTransactionRepo, nil, nil, nil, gateway.NewFakeGateway(nil),
+		helper.NewULIDOrderIDGenerator("ORDER-TEST-"), discount.NewGrulePolicy(), logger)
+
+	ticketServiceStub(t)
+
+	expiresAt := time.Now().Add(time.Hour)
+	mockTransactionRepo.EXPECT().GetTransactionByTransactionID(1, "test@example.com").
+		Return(model.Transaction{TransactionID: 1, OrderID: "ORDER-1", PaymentStatus: "pending"}, nil)
+	mockTransactionRepo.EXPECT().GetDetailTransactionByTransactionID(1).Return([]model.DetailTransaction{{}}, nil)
+	mockTransactionRepo.EXPECT().GetPaymentByOrderID("ORDER-1").
+		Return(model.Payment{OrderID: "ORDER-1", Token: "token-1", RedirectURL: "https://example.com/snap/token-1",
+			GatewayTransactionID: "midtrans-1", ExpiresAt: &expiresAt}, nil)
+
+	transactionResponse, err := uc.GetTransactionByTransactionID(1, "test@example.com")
+
+	assert.Nil(t, err)
+	if assert.NotNil(t, transactionResponse.Payment) {
+		assert.Equal(t, "token-1", transactionResponse.Payment.Token)
+		assert.Equal(t, "midtrans-1", transactionResponse.Payment.GatewayTransactionID)
+	}
+}
+
+func TestRecordGatewayPayment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionRepo := mock.NewMockTransactionPersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+
+	uc := NewTransactionUsecase(mockTransactionRepo, nil, nil, nil, gateway.NewFakeGateway(nil),
+		helper.NewULIDOrderIDGenerator("ORDER-TEST-"), discount.NewGrulePolicy(), logger)
+
+	mockTransactionRepo.EXPECT().SavePayment(model.Payment{OrderID: "ORDER-1", GatewayTransactionID: "midtrans-1",
+		VANumber: "880012345678"}).Return(nil)
+
+	err := uc.RecordGatewayPayment("ORDER-1", &coreapi.TransactionStatusResponse{
+		TransactionID: "midtrans-1",
+		VaNumbers:     []coreapi.VANumber{{Bank: "bca", VANumber: "880012345678"}},
+	})
+
+	assert.Nil(t, err)
+}
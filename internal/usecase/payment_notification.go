@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/helper"
+	"github.com/SyamSolution/transaction-service/internal/gateway"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/midtrans/midtrans-go/coreapi"
+	"go.uber.org/zap"
+)
+
+type paymentNotificationUsecase struct {
+	transactionUsecase  TransactionExecutor
+	issuedTicketUsecase IssuedTicketExecutor
+	invoiceUsecase      InvoiceExecutor
+	paymentGateway      gateway.PaymentGateway
+	logger              config.Logger
+}
+
+type PaymentNotificationExecutor interface {
+	ApplyPaymentStatus(orderID string, statusResp *coreapi.TransactionStatusResponse, event model.TransactionEvent) error
+}
+
+func NewPaymentNotificationUsecase(transactionUsecase TransactionExecutor, issuedTicketUsecase IssuedTicketExecutor,
+	invoiceUsecase InvoiceExecutor, paymentGateway gateway.PaymentGateway, logger config.Logger) PaymentNotificationExecutor {
+	return &paymentNotificationUsecase{transactionUsecase: transactionUsecase, issuedTicketUsecase: issuedTicketUsecase,
+		invoiceUsecase: invoiceUsecase, paymentGateway: paymentGateway, logger: logger}
+}
+
+// ApplyPaymentStatus moves the order, or the share of a split order, to the midtrans status. The webhook, the
+// reconcile job and the admin resync all go through here, event only needs the actor.
+func (uc *paymentNotificationUsecase) ApplyPaymentStatus(orderID string, statusResp *coreapi.TransactionStatusResponse,
+	event model.TransactionEvent) error {
+	transactionStatus := statusResp.TransactionStatus
+	paid := transactionStatus == "settlement" || (transactionStatus == "capture" && statusResp.FraudStatus == "accept")
+	gatewayOrderID := orderID
+
+	// notifikasi untuk satu bagian split payment, order baru diproses setelah semua bagian lunas
+	shareResult, err := uc.transactionUsecase.UpdatePaymentShare(orderID, transactionStatus, statusResp.FraudStatus, event)
+	split := err == nil
+	switch {
+	case split:
+		if !shareResult.AllPaid {
+			return nil
+		}
+		orderID = shareResult.OrderID
+		transactionStatus = "settlement"
+	case !errors.Is(err, ErrPaymentShareNotFound):
+		uc.logger.Error("Error when updating payment share", zap.Error(err))
+		return err
+	default:
+		// link yang dibuat ulang punya order_id midtrans sendiri
+		paymentOrderID, current, err := uc.transactionUsecase.ResolvePaymentLink(orderID)
+		if err != nil {
+			uc.logger.Error("Error when resolving payment link", zap.Error(err))
+			return err
+		}
+		// link lama yang sudah diganti dicancel saat link baru dibuat, hanya pembayarannya yang diproses
+		if !current && !paid {
+			return nil
+		}
+		orderID = paymentOrderID
+
+		if err := uc.transactionUsecase.RecordGatewayPayment(orderID, statusResp); err != nil {
+			// hanya untuk ditampilkan ke user, status tetap diproses
+			uc.logger.Error("Error when recording gateway payment", zap.Error(err))
+		}
+	}
+
+	transactionOrder, err := uc.transactionUsecase.GetTransactionByOrderID(orderID)
+	if err != nil {
+		uc.logger.Error("Error when getting transaction by order ID", zap.Error(err))
+		return err
+	}
+
+	event.PayloadReference = statusResp.TransactionID
+	event.Note = "midtrans " + transactionStatus
+	switch transactionStatus {
+	case "capture":
+		if statusResp.FraudStatus != "accept" {
+			return nil
+		}
+		fallthrough
+	case "settlement":
+		switch transactionOrder.Status {
+		case "completed":
+			// notifikasi yang sama dikirim ulang, ticket sudah terbit dan terkirim
+			return nil
+		case "cancelled":
+			// ticket sudah dikembalikan, pembayarannya di-refund dan order tetap batal.
+			// bagian split payment sudah di-refund saat share-nya ditandai lunas
+			if split {
+				return nil
+			}
+			return uc.refundCancelledPayment(gatewayOrderID, statusResp)
+		}
+		return uc.completeTransaction(orderID, transactionOrder, event)
+	case "cancel", "expire":
+		// sudah dicancel lewat endpoint cancel, ticket sudah dikembalikan
+		if transactionOrder.Status == "cancelled" {
+			return nil
+		}
+
+		// kirim message SQS ke ticket-management-service balikin ticket
+		produceTicketMessages(uc.logger, os.Getenv("SQS_TICKET_FAILED_URL"), "Update Ticket Failed", transactionOrder.DetailTransactionResponse)
+
+		_, err = uc.transactionUsecase.UpdateTransactionStatus(orderID, "cancelled", event)
+		return err
+	case "pending":
+		_, err = uc.transactionUsecase.UpdateTransactionStatus(orderID, "pending", event)
+		return err
+	}
+
+	return nil
+}
+
+// refundCancelledPayment refunds an order that was paid after it was cancelled, gatewayOrderID is the link that was
+// paid. The refund key is the same on every retry so midtrans does not pay it out twice.
+func (uc *paymentNotificationUsecase) refundCancelledPayment(gatewayOrderID string, statusResp *coreapi.TransactionStatusResponse) error {
+	_, err := uc.paymentGateway.Refund(gatewayOrderID, &coreapi.RefundReq{
+		RefundKey: gatewayOrderID + "-refund",
+		Amount:    parseGrossAmount(statusResp.GrossAmount),
+		Reason:    "order was cancelled",
+	})
+	if err != nil {
+		uc.logger.Error("Error when refunding cancelled transaction", zap.String("order_id", gatewayOrderID), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// completeTransaction issues the tickets and settles the order. Emails and the sold message only go out for the
+// notification that really moved the order to completed, a retried notification that loses the race does nothing.
+func (uc *paymentNotificationUsecase) completeTransaction(orderID string, transactionOrder model.TransactionResponse,
+	event model.TransactionEvent) error {
+	if _, err := uc.issuedTicketUsecase.IssueTickets(orderID); err != nil {
+		uc.logger.Error("Error when issuing tickets", zap.Error(err))
+		return err
+	}
+
+	changed, err := uc.transactionUsecase.UpdateTransactionStatus(orderID, "completed", event)
+	if err != nil || !changed {
+		return err
+	}
+
+	// email yang gagal bisa dikirim ulang lewat resend tickets
+	if err := uc.issuedTicketUsecase.SendTicketEmails(orderID); err != nil {
+		uc.logger.Error("Error when sending ticket emails", zap.Error(err))
+	}
+
+	produceTicketMessages(uc.logger, os.Getenv("SQS_TICKET_SUCCESS_URL"), "Update Ticket Success", transactionOrder.DetailTransactionResponse)
+
+	// invoice yang gagal dibuat akan dibuat ulang saat diminta
+	if _, err := uc.invoiceUsecase.CreateInvoice(orderID); err != nil {
+		uc.logger.Error("Error when creating invoice", zap.Error(err))
+	}
+
+	return nil
+}
+
+// produceTicketMessages tells ticket-management-service how many units of every line were sold or returned
+func produceTicketMessages(logger config.Logger, queueURL, title string, details []model.DetailTransactionResponse) {
+	for _, dt := range details {
+		message := model.MessageOrderTicket{
+			TicketID: dt.TicketID,
+			Order:    dt.Quantity,
+		}
+
+		jsonString, err := json.Marshal(message)
+		if err != nil {
+			logger.Error("Error when proceesing message", zap.Error(err))
+			continue
+		}
+
+		if err = helper.ProduceMessageSqs(queueURL, string(jsonString), title); err != nil {
+			logger.Error("Error when producing message", zap.Error(err))
+		}
+	}
+}
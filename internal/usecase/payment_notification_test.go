@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/SyamSolution/transaction-service/internal/gateway"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/mock"
+	mock_config "github.com/SyamSolution/transaction-service/mock/config"
+	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/midtrans/midtrans-go/snap"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestApplyPaymentStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionUsecase := mock.NewMockTransactionExecutor(ctrl)
+	mockIssuedTicketUsecase := mock.NewMockIssuedTicketExecutor(ctrl)
+	mockInvoiceUsecase := mock.NewMockInvoiceExecutor(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+	logger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+
+	fakeGateway := gateway.NewFakeGateway(nil)
+	uc := NewPaymentNotificationUsecase(mockTransactionUsecase, mockIssuedTicketUsecase, mockInvoiceUsecase, fakeGateway, logger)
+	admin := model.TransactionEvent{Actor: model.TransactionActorAdmin, ActorID: "admin@example.com"}
+	resolved := func(orderID string) {
+		mockTransactionUsecase.EXPECT().ResolvePaymentLink(orderID).Return(orderID, true, nil).AnyTimes()
+	}
+
+	t.Run("settlement completes the order", func(t *testing.T) {
+		statusResp := &coreapi.TransactionStatusResponse{TransactionID: "TX-1", TransactionStatus: "settlement"}
+		completed := model.TransactionEvent{Actor: model.TransactionActorAdmin, ActorID: "admin@example.com",
+			PayloadReference: "TX-1", Note: "midtrans settlement"}
+
+		mockTransactionUsecase.EXPECT().UpdatePaymentShare("ORDER-1", "settlement", "", admin).Return(model.PaymentShareResult{}, ErrPaymentShareNotFound)
+		resolved("ORDER-1")
+		mockTransactionUsecase.EXPECT().RecordGatewayPayment("ORDER-1", statusResp).Return(nil)
+		mockTransactionUsecase.EXPECT().GetTransactionByOrderID("ORDER-1").Return(model.TransactionResponse{OrderID: "ORDER-1", Status: "pending"}, nil)
+		mockIssuedTicketUsecase.EXPECT().IssueTickets("ORDER-1").Return(nil, nil)
+		mockTransactionUsecase.EXPECT().UpdateTransactionStatus("ORDER-1", "completed", completed).Return(true, nil)
+		mockIssuedTicketUsecase.EXPECT().SendTicketEmails("ORDER-1").Return(nil)
+		mockInvoiceUsecase.EXPECT().CreateInvoice("ORDER-1").Return(model.Invoice{}, nil)
+
+		assert.NoError(t, uc.ApplyPaymentStatus("ORDER-1", statusResp, admin))
+	})
+
+	t.Run("settlement applied twice sends the tickets once", func(t *testing.T) {
+		statusResp := &coreapi.TransactionStatusResponse{TransactionID: "TX-5", TransactionStatus: "settlement"}
+		mockTransactionUsecase.EXPECT().UpdatePaymentShare("ORDER-5", "settlement", "", admin).Return(model.PaymentShareResult{}, ErrPaymentShareNotFound).Times(2)
+		resolved("ORDER-5")
+		mockTransactionUsecase.EXPECT().RecordGatewayPayment("ORDER-5", statusResp).Return(nil).Times(2)
+
+		// pertama kali order masih pending
+		mockTransactionUsecase.EXPECT().GetTransactionByOrderID("ORDER-5").Return(model.TransactionResponse{OrderID: "ORDER-5", Status: "pending"}, nil)
+		mockIssuedTicketUsecase.EXPECT().IssueTickets("ORDER-5").Return(nil, nil)
+		mockTransactionUsecase.EXPECT().UpdateTransactionStatus("ORDER-5", "completed", gomock.Any()).Return(true, nil)
+		mockIssuedTicketUsecase.EXPECT().SendTicketEmails("ORDER-5").Return(nil).Times(1)
+		mockInvoiceUsecase.EXPECT().CreateInvoice("ORDER-5").Return(model.Invoice{}, nil).Times(1)
+		assert.NoError(t, uc.ApplyPaymentStatus("ORDER-5", statusResp, admin))
+
+		// notifikasi yang sama dikirim ulang setelah order selesai
+		mockTransactionUsecase.EXPECT().GetTransactionByOrderID("ORDER-5").Return(model.TransactionResponse{OrderID: "ORDER-5", Status: "completed"}, nil)
+		assert.NoError(t, uc.ApplyPaymentStatus("ORDER-5", statusResp, admin))
+	})
+
+	t.Run("settlement that loses the race does not send the tickets", func(t *testing.T) {
+		statusResp := &coreapi.TransactionStatusResponse{TransactionID: "TX-6", TransactionStatus: "settlement"}
+		mockTransactionUsecase.EXPECT().UpdatePaymentShare("ORDER-6", "settlement", "", admin).Return(model.PaymentShareResult{}, ErrPaymentShareNotFound)
+		resolved("ORDER-6")
+		mockTransactionUsecase.EXPECT().RecordGatewayPayment("ORDER-6", statusResp).Return(nil)
+		mockTransactionUsecase.EXPECT().GetTransactionByOrderID("ORDER-6").Return(model.TransactionResponse{OrderID: "ORDER-6", Status: "pending"}, nil)
+		mockIssuedTicketUsecase.EXPECT().IssueTickets("ORDER-6").Return(nil, nil)
+		mockTransactionUsecase.EXPECT().UpdateTransactionStatus("ORDER-6", "completed", gomock.Any()).Return(false, nil)
+
+		assert.NoError(t, uc.ApplyPaymentStatus("ORDER-6", statusResp, admin))
+	})
+
+	t.Run("settlement of a cancelled order is refunded", func(t *testing.T) {
+		_, err := fakeGateway.CreateCharge(&snap.Request{TransactionDetails: midtrans.TransactionDetails{OrderID: "ORDER-7", GrossAmt: 150000}})
+		assert.Nil(t, err)
+		assert.Nil(t, fakeGateway.SetStatus("ORDER-7", "settlement", ""))
+
+		statusResp := &coreapi.TransactionStatusResponse{TransactionID: "TX-7", TransactionStatus: "settlement", GrossAmount: "150000.00"}
+		mockTransactionUsecase.EXPECT().UpdatePaymentShare("ORDER-7", "settlement", "", admin).Return(model.PaymentShareResult{}, ErrPaymentShareNotFound)
+		resolved("ORDER-7")
+		mockTransactionUsecase.EXPECT().RecordGatewayPayment("ORDER-7", statusResp).Return(nil)
+		mockTransactionUsecase.EXPECT().GetTransactionByOrderID("ORDER-7").Return(model.TransactionResponse{OrderID: "ORDER-7", Status: "cancelled"}, nil)
+
+		assert.NoError(t, uc.ApplyPaymentStatus("ORDER-7", statusResp, admin))
+
+		status, err := fakeGateway.CheckStatus("ORDER-7")
+		assert.Nil(t, err)
+		assert.Equal(t, "refund", status.TransactionStatus)
+	})
+
+	t.Run("refused refund of a cancelled order is retried", func(t *testing.T) {
+		statusResp := &coreapi.TransactionStatusResponse{TransactionID: "TX-8", TransactionStatus: "capture", FraudStatus: "accept"}
+		mockTransactionUsecase.EXPECT().UpdatePaymentShare("ORDER-8", "capture", "accept", admin).Return(model.PaymentShareResult{}, ErrPaymentShareNotFound)
+		resolved("ORDER-8")
+		mockTransactionUsecase.EXPECT().RecordGatewayPayment("ORDER-8", statusResp).Return(nil)
+		mockTransactionUsecase.EXPECT().GetTransactionByOrderID("ORDER-8").Return(model.TransactionResponse{OrderID: "ORDER-8", Status: "cancelled"}, nil)
+
+		assert.Error(t, uc.ApplyPaymentStatus("ORDER-8", statusResp, admin))
+	})
+
+	t.Run("share that is not the last one waits", func(t *testing.T) {
+		statusResp := &coreapi.TransactionStatusResponse{TransactionID: "TX-2", TransactionStatus: "settlement"}
+		mockTransactionUsecase.EXPECT().UpdatePaymentShare("ORDER-2-S1", "settlement", "", admin).
+			Return(model.PaymentShareResult{OrderID: "ORDER-2"}, nil)
+
+		assert.NoError(t, uc.ApplyPaymentStatus("ORDER-2-S1", statusResp, admin))
+	})
+
+	t.Run("last share settles the split order", func(t *testing.T) {
+		statusResp := &coreapi.TransactionStatusResponse{TransactionID: "TX-3", TransactionStatus: "capture", FraudStatus: "accept"}
+		mockTransactionUsecase.EXPECT().UpdatePaymentShare("ORDER-2-S2", "capture", "accept", admin).
+			Return(model.PaymentShareResult{OrderID: "ORDER-2", AllPaid: true}, nil)
+		mockTransactionUsecase.EXPECT().GetTransactionByOrderID("ORDER-2").Return(model.TransactionResponse{OrderID: "ORDER-2", Status: "pending"}, nil)
+		mockIssuedTicketUsecase.EXPECT().IssueTickets("ORDER-2").Return(nil, nil)
+		mockTransactionUsecase.EXPECT().UpdateTransactionStatus("ORDER-2", "completed", gomock.Any()).Return(true, nil)
+		mockIssuedTicketUsecase.EXPECT().SendTicketEmails("ORDER-2").Return(nil)
+		mockInvoiceUsecase.EXPECT().CreateInvoice("ORDER-2").Return(model.Invoice{}, nil)
+
+		assert.NoError(t, uc.ApplyPaymentStatus("ORDER-2-S2", statusResp, admin))
+	})
+
+	t.Run("last share of a cancelled split order is not completed", func(t *testing.T) {
+		statusResp := &coreapi.TransactionStatusResponse{TransactionID: "TX-9", TransactionStatus: "settlement"}
+		mockTransactionUsecase.EXPECT().UpdatePaymentShare("ORDER-9-S2", "settlement", "", admin).
+			Return(model.PaymentShareResult{OrderID: "ORDER-9", AllPaid: true}, nil)
+		mockTransactionUsecase.EXPECT().GetTransactionByOrderID("ORDER-9").Return(model.TransactionResponse{OrderID: "ORDER-9", Status: "cancelled"}, nil)
+
+		assert.NoError(t, uc.ApplyPaymentStatus("ORDER-9-S2", statusResp, admin))
+	})
+
+	t.Run("cancel of a replaced payment link is ignored", func(t *testing.T) {
+		statusResp := &coreapi.TransactionStatusResponse{TransactionID: "TX-10", TransactionStatus: "cancel"}
+		mockTransactionUsecase.EXPECT().UpdatePaymentShare("ORDER-10", "cancel", "", admin).Return(model.PaymentShareResult{}, ErrPaymentShareNotFound)
+		mockTransactionUsecase.EXPECT().ResolvePaymentLink("ORDER-10").Return("ORDER-10", false, nil)
+
+		assert.NoError(t, uc.ApplyPaymentStatus("ORDER-10", statusResp, admin))
+	})
+
+	t.Run("settlement of a link made again completes its order", func(t *testing.T) {
+		statusResp := &coreapi.TransactionStatusResponse{TransactionID: "TX-11", TransactionStatus: "settlement"}
+		mockTransactionUsecase.EXPECT().UpdatePaymentShare("ORDER-11-R1", "settlement", "", admin).Return(model.PaymentShareResult{}, ErrPaymentShareNotFound)
+		mockTransactionUsecase.EXPECT().ResolvePaymentLink("ORDER-11-R1").Return("ORDER-11", true, nil)
+		mockTransactionUsecase.EXPECT().RecordGatewayPayment("ORDER-11", statusResp).Return(nil)
+		mockTransactionUsecase.EXPECT().GetTransactionByOrderID("ORDER-11").Return(model.TransactionResponse{OrderID: "ORDER-11", Status: "pending"}, nil)
+		mockIssuedTicketUsecase.EXPECT().IssueTickets("ORDER-11").Return(nil, nil)
+		mockTransactionUsecase.EXPECT().UpdateTransactionStatus("ORDER-11", "completed", gomock.Any()).Return(true, nil)
+		mockIssuedTicketUsecase.EXPECT().SendTicketEmails("ORDER-11").Return(nil)
+		mockInvoiceUsecase.EXPECT().CreateInvoice("ORDER-11").Return(model.Invoice{}, nil)
+
+		assert.NoError(t, uc.ApplyPaymentStatus("ORDER-11-R1", statusResp, admin))
+	})
+
+	t.Run("expire of a cancelled order is left alone", func(t *testing.T) {
+		statusResp := &coreapi.TransactionStatusResponse{TransactionID: "TX-4", TransactionStatus: "expire"}
+		mockTransactionUsecase.EXPECT().UpdatePaymentShare("ORDER-4", "expire", "", admin).Return(model.PaymentShareResult{}, ErrPaymentShareNotFound)
+		resolved("ORDER-4")
+		mockTransactionUsecase.EXPECT().RecordGatewayPayment("ORDER-4", statusResp).Return(nil)
+		mockTransactionUsecase.EXPECT().GetTransactionByOrderID("ORDER-4").Return(model.TransactionResponse{OrderID: "ORDER-4", Status: "cancelled"}, nil)
+
+		assert.NoError(t, uc.ApplyPaymentStatus("ORDER-4", statusResp, admin))
+	})
+}
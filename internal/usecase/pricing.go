@@ -0,0 +1,466 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/mail"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/helper"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/util"
+	"github.com/midtrans/midtrans-go"
+	"go.uber.org/zap"
+)
+
+const defaultQuoteTTL = 15 * time.Minute
+
+// orderPricing is the outcome of the pricing pipeline, shared by quote and create
+type orderPricing struct {
+	detailTransactions []model.DetailTransaction
+	continents         []string
+	ticketPrices       map[int]int
+	totalTicket        int
+	subtotal           float32
+	discount           float32
+	discountTrace      string
+	voucher            model.Voucher
+	voucherAmount      float32
+	totalAmount        float32
+	itemDetails        []midtrans.ItemDetails
+	grossAmount        int64
+}
+
+// quoteClaims is what a quote id carries, the prices are frozen so create gives the same amount
+type quoteClaims struct {
+	Email         string      `json:"email"`
+	RequestHash   string      `json:"request_hash"`
+	TicketPrices  map[int]int `json:"ticket_prices"`
+	Discount      float32     `json:"discount"`
+	VoucherAmount float32     `json:"voucher_amount"`
+	GrossAmount   int64       `json:"gross_amount"`
+	ExpiresAt     int64       `json:"exp"`
+}
+
+func quoteSecret() []byte {
+	return []byte(os.Getenv("QUOTE_SECRET"))
+}
+
+// quoteTTL reads QUOTE_TTL (e.g. "15m")
+func quoteTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("QUOTE_TTL"))
+	if err != nil || ttl <= 0 {
+		return defaultQuoteTTL
+	}
+
+	return ttl
+}
+
+// lineContinent is the continent of one line, lines without one fall back to the order continent
+func lineContinent(request model.TransactionRequest, detail model.DetailTransactionRequest) string {
+	if detail.Continent != "" {
+		return detail.Continent
+	}
+
+	return request.Continent
+}
+
+// requestHash fingerprints the parts of a request that decide the price, line order does not matter
+func requestHash(request model.TransactionRequest) string {
+	lines := make([]string, 0, len(request.DetailTicket))
+	for _, detail := range request.DetailTicket {
+		lines = append(lines, fmt.Sprintf("%d|%s|%s|%d", detail.TicketID, detail.TicketType, lineContinent(request, detail), detail.Quantity))
+	}
+	sort.Strings(lines)
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\n%d\n%s", normalizeVoucherCode(request.VoucherCode),
+		request.TotalTicket, strings.Join(lines, "\n"))))
+	return hex.EncodeToString(sum[:])
+}
+
+func (uc *transactionUsecase) verifyQuote(request model.TransactionRequest, email string, now time.Time) (*quoteClaims, error) {
+	var claims quoteClaims
+	if len(quoteSecret()) == 0 {
+		return nil, ErrQuoteInvalid
+	}
+	if err := util.VerifyToken(quoteSecret(), request.QuoteID, &claims); err != nil {
+		return nil, ErrQuoteInvalid
+	}
+	if claims.Email != email {
+		return nil, ErrQuoteInvalid
+	}
+	if now.Unix() >= claims.ExpiresAt {
+		return nil, ErrQuoteExpired
+	}
+	if claims.RequestHash != requestHash(request) {
+		return nil, ErrQuoteMismatch
+	}
+
+	return &claims, nil
+}
+
+// orderTicketCount sums the line quantities, total_ticket from the client must agree with it
+func orderTicketCount(request model.TransactionRequest) (int, error) {
+	var count int
+	for _, detail := range request.DetailTicket {
+		count += detail.Quantity
+	}
+	if request.TotalTicket != count {
+		return 0, ErrTotalTicketMismatch
+	}
+
+	return count, nil
+}
+
+// resolveLineItems checks every line of the request against the ticket service and returns the detail
+// transactions with the matching tickets. Type, country and city must match the ticket when given and are
+// filled from it otherwise. Tickets held for other users' waitlist entries are not in stock. All invalid
+// lines are reported together.
+func resolveLineItems(request model.TransactionRequest, ticketsByContinent map[string]map[int]model.TicketResponse,
+	held map[int]int, now time.Time) ([]model.DetailTransaction, []model.TicketResponse, error) {
+	var (
+		detailTransactions []model.DetailTransaction
+		tickets            []model.TicketResponse
+		lineErrors         LineItemErrors
+	)
+
+	ordered := make(map[int]int)
+	for i, detail := range request.DetailTicket {
+		continent := lineContinent(request, detail)
+		t, ok := ticketsByContinent[continent][detail.TicketID]
+		if !ok {
+			lineErrors = append(lineErrors, LineItemError{Line: i, Field: "ticket_id", Tag: "unknown",
+				Message: fmt.Sprintf("ticket %d is not sold in %s", detail.TicketID, continent)})
+			continue
+		}
+
+		before := len(lineErrors)
+		if detail.Quantity <= 0 {
+			lineErrors = append(lineErrors, LineItemError{Line: i, Field: "quantity", Tag: "min",
+				Message: "quantity must be at least 1"})
+		}
+		for _, field := range []struct{ name, got, want string }{
+			{"ticket_type", detail.TicketType, t.Type},
+			{"country_name", detail.CountryName, t.CountryName},
+			{"city", detail.City, t.CountryCity},
+		} {
+			if field.got != "" && !strings.EqualFold(strings.TrimSpace(field.got), field.want) {
+				lineErrors = append(lineErrors, LineItemError{Line: i, Field: field.name, Tag: "mismatch",
+					Message: fmt.Sprintf("ticket %d is %s, not %s", detail.TicketID, field.want, field.got)})
+			}
+		}
+
+		// stock dihitung per ticket id, satu ticket bisa muncul di beberapa baris
+		ordered[t.TicketID] += detail.Quantity
+		if stock := t.Stock - held[t.TicketID]; ordered[t.TicketID] > stock {
+			lineErrors = append(lineErrors, LineItemError{Line: i, Field: "quantity", Tag: "stock",
+				Message: fmt.Sprintf("only %d ticket %d left, join the waitlist", max(stock, 0), detail.TicketID)})
+		}
+		holder, holderErrors := lineHolder(i, detail.Holder)
+		lineErrors = append(lineErrors, holderErrors...)
+		if len(lineErrors) > before {
+			continue
+		}
+
+		detailTransactions = append(detailTransactions, model.DetailTransaction{
+			TicketID:    t.TicketID,
+			TicketType:  t.Type,
+			Continent:   continent,
+			CountryName: t.CountryName,
+			City:        t.CountryCity,
+			Quantity:    detail.Quantity,
+			Holder:      holder,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+		tickets = append(tickets, t)
+	}
+
+	if len(lineErrors) > 0 {
+		return nil, nil, lineErrors
+	}
+
+	return detailTransactions, tickets, nil
+}
+
+// lineHolder validates the optional holder of a gifted line, the tickets and email go to this person
+func lineHolder(line int, holder *model.TicketHolder) (*model.TicketHolder, LineItemErrors) {
+	if holder == nil {
+		return nil, nil
+	}
+
+	var lineErrors LineItemErrors
+	cleaned := &model.TicketHolder{
+		FullName: strings.TrimSpace(holder.FullName),
+		Email:    strings.TrimSpace(holder.Email),
+		IDNumber: strings.TrimSpace(holder.IDNumber),
+	}
+	if cleaned.FullName == "" {
+		lineErrors = append(lineErrors, LineItemError{Line: line, Field: "holder.full_name", Tag: "required",
+			Message: "holder name is required"})
+	}
+	if address, err := mail.ParseAddress(cleaned.Email); err != nil || address.Address != cleaned.Email {
+		lineErrors = append(lineErrors, LineItemError{Line: line, Field: "holder.email", Tag: "email",
+			Message: "holder email is not valid"})
+	}
+
+	return cleaned, lineErrors
+}
+
+// priceOrder runs eligibility, ticket lookup, stock check, discount, voucher and currency conversion
+// without reserving anything. With a quote the frozen prices, discount and voucher amount are used.
+func (uc *transactionUsecase) priceOrder(request model.TransactionRequest, user model.User, quote *quoteClaims, now time.Time) (orderPricing, error) {
+	var pricing orderPricing
+
+	totalTicket, err := orderTicketCount(request)
+	if err != nil {
+		return pricing, err
+	}
+	pricing.totalTicket = totalTicket
+
+	isEligible, err := helper.CheckEligible()
+	if err != nil {
+		uc.logger.Error("Error when hit grule service", zap.Error(err))
+		return pricing, err
+	}
+
+	if !isEligible {
+		return pricing, fmt.Errorf("not eligible")
+	}
+
+	// ambil ticket per continent yang ada di order
+	ticketsByContinent := make(map[string]map[int]model.TicketResponse)
+	for _, detail := range request.DetailTicket {
+		continent := lineContinent(request, detail)
+		if _, fetched := ticketsByContinent[continent]; fetched {
+			continue
+		}
+
+		//hit check all stock ticket with same continent
+		tickets, err := helper.GetTicket(continent)
+		if err != nil {
+			uc.logger.Error("Error when getting ticket by continent", zap.Error(err))
+			return pricing, err
+		}
+		ticketsByContinent[continent] = make(map[int]model.TicketResponse, len(tickets))
+		for _, t := range tickets {
+			ticketsByContinent[continent][t.TicketID] = t
+		}
+		pricing.continents = append(pricing.continents, continent)
+	}
+	sort.Strings(pricing.continents)
+
+	// ticket yang sedang di-hold untuk waitlist user lain tidak bisa dibeli
+	ticketIDs := make([]int, 0, len(request.DetailTicket))
+	for _, detail := range request.DetailTicket {
+		ticketIDs = append(ticketIDs, detail.TicketID)
+	}
+	held, err := uc.waitlistRepo.GetHeldQuantity(ticketIDs, user.Email, now)
+	if err != nil {
+		uc.logger.Error("Error when getting waitlist hold", zap.Error(err))
+		return pricing, err
+	}
+
+	var tickets []model.TicketResponse
+	pricing.detailTransactions, tickets, err = resolveLineItems(request, ticketsByContinent, held, now)
+	if err != nil {
+		uc.logger.Error("Error when resolving ticket lines", zap.Error(err))
+		return pricing, err
+	}
+
+	pricing.ticketPrices = make(map[int]int)
+	for i, detail := range pricing.detailTransactions {
+		pricing.ticketPrices[detail.TicketID] = tickets[i].Price
+		if quote != nil {
+			pricing.ticketPrices[detail.TicketID] = quote.TicketPrices[detail.TicketID]
+		}
+		pricing.subtotal = pricing.subtotal + float32(detail.Quantity*pricing.ticketPrices[detail.TicketID])
+	}
+	pricing.totalAmount = pricing.subtotal
+
+	var discountResult model.DiscountResult
+	if quote != nil {
+		discountResult = model.DiscountResult{
+			Percent: quote.Discount,
+			Trace: []model.DiscountTrace{{
+				Rule:    "quote",
+				Matched: quote.Discount > 0,
+				Percent: quote.Discount,
+				Reason:  "discount fixed by quote",
+			}},
+		}
+	} else {
+		discountResult, err = uc.evaluateDiscount(user, pricing.continents, pricing.totalTicket, pricing.subtotal, now)
+		if err != nil {
+			return pricing, err
+		}
+	}
+	if discountResult.Percent > 0 {
+		pricing.discount = discountResult.Percent
+		pricing.totalAmount = pricing.totalAmount * ((100 - pricing.discount) / 100)
+	}
+	if trace, err := json.Marshal(discountResult.Trace); err == nil {
+		pricing.discountTrace = string(trace)
+	}
+
+	if request.VoucherCode != "" {
+		pricing.voucher, err = uc.voucherRepo.GetVoucherByCode(normalizeVoucherCode(request.VoucherCode))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return pricing, ErrVoucherNotFound
+			}
+			uc.logger.Error("Error when getting voucher by code", zap.Error(err))
+			return pricing, err
+		}
+
+		if quote != nil {
+			pricing.voucherAmount = quote.VoucherAmount
+		} else {
+			pricing.voucherAmount, err = voucherDiscount(pricing.voucher, pricing.detailTransactions, pricing.ticketPrices,
+				pricing.discount, now)
+			if err != nil {
+				return pricing, err
+			}
+		}
+		pricing.totalAmount = pricing.totalAmount - pricing.voucherAmount
+	}
+
+	allocateLineAmounts(pricing.detailTransactions, pricing.ticketPrices, pricing.discount, pricing.voucher,
+		pricing.voucherAmount, pricing.totalAmount)
+
+	// TODO request api exchange to IDR
+	pricing.itemDetails, pricing.grossAmount = buildItemDetails(pricing.detailTransactions, pricing.ticketPrices,
+		pricing.discount, pricing.voucher.Code, pricing.voucherAmount)
+
+	return pricing, nil
+}
+
+func (uc *transactionUsecase) evaluateDiscount(user model.User, continents []string, totalTicket int,
+	subtotal float32, now time.Time) (model.DiscountResult, error) {
+	// cek stock semua ticket group by continent yang sold out
+	stockTicket, err := helper.GetStockTicketGroupByContinent()
+	if err != nil {
+		uc.logger.Error("Error when getting stock ticket group by continent", zap.Error(err))
+		return model.DiscountResult{}, err
+	}
+	var continentSoldout []string
+	for _, st := range stockTicket {
+		if st.Stock == 0 {
+			continentSoldout = append(continentSoldout, st.Continent)
+		}
+	}
+
+	// cek semua continent transaksi sebelumnya
+	continentLastTransaction, err := uc.transactionRepo.GetDistinctContinentTransaction(user.Email)
+	if err != nil {
+		uc.logger.Error("Error when getting distinct continent transaction", zap.Error(err))
+		return model.DiscountResult{}, err
+	}
+
+	discountResult, err := uc.discountPolicy.Evaluate(model.DiscountInput{
+		Email:              user.Email,
+		Continents:         continents,
+		SoldOutContinents:  continentSoldout,
+		PreviousContinents: continentLastTransaction,
+		TotalTicket:        totalTicket,
+		Subtotal:           subtotal,
+		Now:                now,
+	})
+	if err != nil {
+		uc.logger.Error("Error when checking discount", zap.Error(err))
+		return model.DiscountResult{}, err
+	}
+
+	return discountResult, nil
+}
+
+func (uc *transactionUsecase) QuoteTransaction(request model.TransactionRequest, user model.User) (model.QuoteResponse, error) {
+	if len(quoteSecret()) == 0 {
+		return model.QuoteResponse{}, errors.New("QUOTE_SECRET is not set")
+	}
+
+	now := time.Now()
+	pricing, err := uc.priceOrder(request, user, nil, now)
+	if err != nil {
+		return model.QuoteResponse{}, err
+	}
+
+	expiresAt := now.Add(quoteTTL())
+	quoteID, err := util.SignToken(quoteSecret(), quoteClaims{
+		Email:         user.Email,
+		RequestHash:   requestHash(request),
+		TicketPrices:  pricing.ticketPrices,
+		Discount:      pricing.discount,
+		VoucherAmount: pricing.voucherAmount,
+		GrossAmount:   pricing.grossAmount,
+		ExpiresAt:     expiresAt.Unix(),
+	})
+	if err != nil {
+		uc.logger.Error("Error when signing quote", zap.Error(err))
+		return model.QuoteResponse{}, err
+	}
+
+	response := model.QuoteResponse{
+		QuoteID:         quoteID,
+		ExpiresAt:       time.Unix(expiresAt.Unix(), 0),
+		Subtotal:        pricing.subtotal,
+		Discount:        pricing.discount,
+		VoucherCode:     pricing.voucher.Code,
+		VoucherDiscount: pricing.voucherAmount,
+		TotalAmount:     pricing.totalAmount,
+		TotalIDR:        pricing.grossAmount,
+	}
+	for _, item := range pricing.itemDetails {
+		response.Items = append(response.Items, model.QuoteItem{
+			ID:       item.ID,
+			Name:     item.Name,
+			Quantity: int(item.Qty),
+			PriceIDR: item.Price,
+		})
+	}
+
+	return response, nil
+}
+
+// allocateLineAmounts prices every line after the discount and takes the voucher only off the lines it applies
+// to, so revenue can be reported per line. The last line takes the rounding remainder to keep the sum exact.
+func allocateLineAmounts(detailTransactions []model.DetailTransaction, ticketPrices map[int]int, discount float32,
+	voucher model.Voucher, voucherAmount, totalAmount float32) {
+	amounts := make([]float32, len(detailTransactions))
+	var eligible float32
+	for i := range detailTransactions {
+		dt := &detailTransactions[i]
+		dt.Price = float32(ticketPrices[dt.TicketID])
+		amounts[i] = dt.Price * float32(dt.Quantity) * (100 - discount) / 100
+		if voucherAmount > 0 && voucherApplies(voucher, *dt) {
+			eligible += amounts[i]
+		}
+	}
+
+	var allocated float32
+	for i := range detailTransactions {
+		dt := &detailTransactions[i]
+		if i == len(detailTransactions)-1 {
+			dt.NetAmount = roundCents(totalAmount - allocated)
+			break
+		}
+		amount := amounts[i]
+		if eligible > 0 && voucherApplies(voucher, *dt) {
+			amount -= amounts[i] / eligible * voucherAmount
+		}
+		dt.NetAmount = roundCents(amount)
+		allocated += dt.NetAmount
+	}
+}
+
+func roundCents(amount float32) float32 {
+	return float32(math.Round(float64(amount)*100) / 100)
+}
@@ -0,0 +1,256 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestHash(t *testing.T) {
+	request := model.TransactionRequest{
+		Continent:   "Asia",
+		TotalTicket: 3,
+		VoucherCode: "hemat10",
+		DetailTicket: []model.DetailTransactionRequest{
+			{TicketID: 1, TicketType: "VIP", Quantity: 2},
+			{TicketID: 2, TicketType: "Regular", Quantity: 1},
+		},
+	}
+
+	reordered := request
+	reordered.VoucherCode = "HEMAT10"
+	reordered.PaymentMethod = "bca_va"
+	reordered.DetailTicket = []model.DetailTransactionRequest{request.DetailTicket[1], request.DetailTicket[0]}
+	assert.Equal(t, requestHash(request), requestHash(reordered))
+
+	changed := request
+	changed.DetailTicket = []model.DetailTransactionRequest{{TicketID: 1, TicketType: "VIP", Quantity: 3}, request.DetailTicket[1]}
+	assert.NotEqual(t, requestHash(request), requestHash(changed))
+}
+
+func TestVerifyQuote(t *testing.T) {
+	t.Setenv("QUOTE_SECRET", "quote-secret")
+
+	uc := &transactionUsecase{}
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	email := "test@example.com"
+	request := model.TransactionRequest{
+		Continent:    "Asia",
+		TotalTicket:  1,
+		DetailTicket: []model.DetailTransactionRequest{{TicketID: 1, TicketType: "VIP", Quantity: 1}},
+	}
+
+	sign := func(claims quoteClaims) string {
+		token, err := util.SignToken(quoteSecret(), claims)
+		assert.Nil(t, err)
+		return token
+	}
+	valid := quoteClaims{
+		Email:        email,
+		RequestHash:  requestHash(request),
+		TicketPrices: map[int]int{1: 50},
+		GrossAmount:  50 * idrExchangeRate,
+		ExpiresAt:    now.Add(time.Minute).Unix(),
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		request.QuoteID = sign(valid)
+		claims, err := uc.verifyQuote(request, email, now)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 50, claims.TicketPrices[1])
+	})
+
+	t.Run("signed with another secret", func(t *testing.T) {
+		token, _ := util.SignToken([]byte("other"), valid)
+		request.QuoteID = token
+		_, err := uc.verifyQuote(request, email, now)
+
+		assert.ErrorIs(t, err, ErrQuoteInvalid)
+	})
+
+	t.Run("quote of another user", func(t *testing.T) {
+		request.QuoteID = sign(valid)
+		_, err := uc.verifyQuote(request, "other@example.com", now)
+
+		assert.ErrorIs(t, err, ErrQuoteInvalid)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		request.QuoteID = sign(valid)
+		_, err := uc.verifyQuote(request, email, now.Add(2*time.Minute))
+
+		assert.ErrorIs(t, err, ErrQuoteExpired)
+	})
+
+	t.Run("order changed after quoting", func(t *testing.T) {
+		changed := request
+		changed.QuoteID = sign(valid)
+		changed.DetailTicket = []model.DetailTransactionRequest{{TicketID: 1, TicketType: "VIP", Quantity: 4}}
+		_, err := uc.verifyQuote(changed, email, now)
+
+		assert.ErrorIs(t, err, ErrQuoteMismatch)
+	})
+}
+
+func TestResolveLineItems(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	ticketsByContinent := map[string]map[int]model.TicketResponse{
+		"Asia": {
+			1: {TicketID: 1, Type: "VIP", Price: 50, Stock: 3, CountryName: "Indonesia", CountryCity: "Jakarta"},
+			2: {TicketID: 2, Type: "Regular", Price: 20, Stock: 10, CountryName: "Indonesia", CountryCity: "Jakarta"},
+		},
+		"Europe": {
+			3: {TicketID: 3, Type: "VIP", Price: 40, Stock: 5, CountryName: "France", CountryCity: "Paris"},
+		},
+	}
+
+	t.Run("valid lines are filled from the ticket", func(t *testing.T) {
+		request := model.TransactionRequest{
+			Continent: "Asia",
+			DetailTicket: []model.DetailTransactionRequest{
+				{TicketID: 1, TicketType: "vip", Quantity: 2},
+				{TicketID: 3, Continent: "Europe", CountryName: "France", City: "Paris", Quantity: 1},
+			},
+		}
+
+		details, tickets, err := resolveLineItems(request, ticketsByContinent, nil, now)
+
+		assert.NoError(t, err)
+		assert.Len(t, tickets, 2)
+		assert.Equal(t, model.DetailTransaction{TicketID: 1, TicketType: "VIP", Continent: "Asia", CountryName: "Indonesia",
+			City: "Jakarta", Quantity: 2, CreatedAt: now, UpdatedAt: now}, details[0])
+		assert.Equal(t, "Europe", details[1].Continent)
+		assert.Equal(t, 40, tickets[1].Price)
+	})
+
+	t.Run("every invalid line is reported", func(t *testing.T) {
+		request := model.TransactionRequest{
+			Continent: "Asia",
+			DetailTicket: []model.DetailTransactionRequest{
+				{TicketID: 9, Quantity: 1},
+				{TicketID: 2, TicketType: "VIP", City: "Bandung", Quantity: 1},
+				{TicketID: 3, Quantity: 1},
+				{TicketID: 1, Quantity: 2},
+				{TicketID: 1, Quantity: 2},
+				{TicketID: 2, Quantity: 0},
+				{TicketID: 2, Quantity: 1},
+			},
+		}
+
+		details, _, err := resolveLineItems(request, ticketsByContinent, map[int]int{2: 9}, now)
+
+		assert.ErrorIs(t, err, ErrInvalidLineItem)
+		assert.Nil(t, details)
+		assert.Equal(t, LineItemErrors{
+			{Line: 0, Field: "ticket_id", Tag: "unknown", Message: "ticket 9 is not sold in Asia"},
+			{Line: 1, Field: "ticket_type", Tag: "mismatch", Message: "ticket 2 is Regular, not VIP"},
+			{Line: 1, Field: "city", Tag: "mismatch", Message: "ticket 2 is Jakarta, not Bandung"},
+			{Line: 2, Field: "ticket_id", Tag: "unknown", Message: "ticket 3 is not sold in Asia"},
+			{Line: 4, Field: "quantity", Tag: "stock", Message: "only 3 ticket 1 left, join the waitlist"},
+			{Line: 5, Field: "quantity", Tag: "min", Message: "quantity must be at least 1"},
+			{Line: 6, Field: "quantity", Tag: "stock", Message: "only 1 ticket 2 left, join the waitlist"},
+		}, err)
+	})
+	t.Run("holder of a gifted line is validated", func(t *testing.T) {
+		request := model.TransactionRequest{
+			Continent: "Asia",
+			DetailTicket: []model.DetailTransactionRequest{
+				{TicketID: 1, Quantity: 1, Holder: &model.TicketHolder{FullName: " Budi ", Email: "budi@example.com", IDNumber: "3201"}},
+				{TicketID: 2, Quantity: 1, Holder: &model.TicketHolder{Email: "Budi <budi@example.com>"}},
+			},
+		}
+
+		_, _, err := resolveLineItems(request, ticketsByContinent, nil, now)
+
+		assert.Equal(t, LineItemErrors{
+			{Line: 1, Field: "holder.full_name", Tag: "required", Message: "holder name is required"},
+			{Line: 1, Field: "holder.email", Tag: "email", Message: "holder email is not valid"},
+		}, err)
+
+		details, _, err := resolveLineItems(model.TransactionRequest{Continent: "Asia", DetailTicket: request.DetailTicket[:1]},
+			ticketsByContinent, nil, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.TicketHolder{FullName: "Budi", Email: "budi@example.com", IDNumber: "3201"}, details[0].Holder)
+	})
+}
+
+func TestOrderTicketCount(t *testing.T) {
+	request := model.TransactionRequest{
+		TotalTicket: 3,
+		DetailTicket: []model.DetailTransactionRequest{
+			{TicketID: 1, Quantity: 2},
+			{TicketID: 2, Quantity: 1},
+		},
+	}
+
+	t.Run("matches the lines", func(t *testing.T) {
+		count, err := orderTicketCount(request)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("inflated to reach a bulk discount", func(t *testing.T) {
+		inflated := request
+		inflated.TotalTicket = 10
+		_, err := orderTicketCount(inflated)
+
+		assert.ErrorIs(t, err, ErrTotalTicketMismatch)
+	})
+}
+
+func TestAllocateLineAmounts(t *testing.T) {
+	detailTransactions := []model.DetailTransaction{
+		{TicketID: 1, Quantity: 2},
+		{TicketID: 2, Quantity: 1},
+	}
+	ticketPrices := map[int]int{1: 100, 2: 50}
+
+	// 250 dengan diskon 10% lalu voucher 25 untuk semua line
+	allocateLineAmounts(detailTransactions, ticketPrices, 10, model.Voucher{Code: "ALL"}, 25, 200)
+
+	assert.Equal(t, float32(100), detailTransactions[0].Price)
+	assert.Equal(t, float32(160), detailTransactions[0].NetAmount)
+	assert.Equal(t, float32(50), detailTransactions[1].Price)
+	assert.Equal(t, float32(40), detailTransactions[1].NetAmount)
+
+	detailTransactions = []model.DetailTransaction{
+		{TicketID: 1, Quantity: 1},
+		{TicketID: 1, Quantity: 1},
+		{TicketID: 1, Quantity: 1},
+	}
+	allocateLineAmounts(detailTransactions, map[int]int{1: 10}, 0, model.Voucher{Code: "ALL"}, 20, 10)
+
+	assert.Equal(t, float32(3.33), detailTransactions[0].NetAmount)
+	assert.Equal(t, float32(3.33), detailTransactions[1].NetAmount)
+	assert.Equal(t, float32(3.34), detailTransactions[2].NetAmount)
+}
+
+func TestAllocateLineAmountsScopedVoucher(t *testing.T) {
+	detailTransactions := []model.DetailTransaction{
+		{TicketID: 1, Quantity: 2, TicketType: "VIP", Continent: "Asia"},
+		{TicketID: 2, Quantity: 1, TicketType: "Regular", Continent: "Asia"},
+	}
+	ticketPrices := map[int]int{1: 100, 2: 50}
+	voucher := model.Voucher{Code: "VIPONLY", TicketType: "vip"}
+
+	// voucher 30 hanya untuk VIP, line regular tetap harga penuh
+	allocateLineAmounts(detailTransactions, ticketPrices, 0, voucher, 30, 220)
+
+	assert.Equal(t, float32(170), detailTransactions[0].NetAmount)
+	assert.Equal(t, float32(50), detailTransactions[1].NetAmount)
+
+	detailTransactions = []model.DetailTransaction{
+		{TicketID: 2, Quantity: 1, TicketType: "Regular", Continent: "Asia"},
+		{TicketID: 1, Quantity: 2, TicketType: "VIP", Continent: "Asia"},
+	}
+	allocateLineAmounts(detailTransactions, ticketPrices, 10, voucher, 30, 195)
+
+	assert.Equal(t, float32(45), detailTransactions[0].NetAmount)
+	assert.Equal(t, float32(150), detailTransactions[1].NetAmount)
+}
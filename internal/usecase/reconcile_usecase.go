@@ -0,0 +1,300 @@
+package usecase
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/internal/gateway"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/repository"
+	"github.com/midtrans/midtrans-go/coreapi"
+	"go.uber.org/zap"
+)
+
+const reconcilePageSize = 100
+
+type reconcileUsecase struct {
+	transactionRepo            repository.TransactionPersister
+	paymentShareRepo           repository.PaymentSharePersister
+	paymentNotificationUsecase PaymentNotificationExecutor
+	paymentGateway             gateway.PaymentGateway
+	logger                     config.Logger
+}
+
+type ReconcileExecutor interface {
+	Reconcile(from, to time.Time, dryRun bool) (model.ReconcileReport, error)
+}
+
+func NewReconcileUsecase(transactionRepo repository.TransactionPersister, paymentShareRepo repository.PaymentSharePersister,
+	paymentNotificationUsecase PaymentNotificationExecutor, paymentGateway gateway.PaymentGateway, logger config.Logger) ReconcileExecutor {
+	return &reconcileUsecase{
+		transactionRepo:            transactionRepo,
+		paymentShareRepo:           paymentShareRepo,
+		paymentNotificationUsecase: paymentNotificationUsecase,
+		paymentGateway:             paymentGateway,
+		logger:                     logger,
+	}
+}
+
+// gatewayCharge is one order_id on midtrans, the order itself or a share of a split order
+type gatewayCharge struct {
+	orderID     string
+	localStatus string
+	localAmount int64
+	share       bool
+}
+
+// Reconcile compares every order made in [from, to) with midtrans, a split order share by share. A pending
+// order or share that midtrans settled, cancelled or expired is moved the way the webhook would, everything
+// else goes to the report.
+func (uc *reconcileUsecase) Reconcile(from, to time.Time, dryRun bool) (model.ReconcileReport, error) {
+	report := model.ReconcileReport{From: from, To: to, DryRun: dryRun, Discrepancies: []model.ReconcileDiscrepancy{}}
+
+	afterID := 0
+	for {
+		transactions, err := uc.transactionRepo.GetTransactionsByDateRange(from, to, afterID, reconcilePageSize)
+		if err != nil {
+			uc.logger.Error("Error when getting transactions by date range", zap.Error(err))
+			return report, err
+		}
+
+		for _, transaction := range transactions {
+			afterID = transaction.TransactionID
+			report.Checked++
+
+			charges, err := uc.gatewayCharges(transaction)
+			if err != nil {
+				return report, err
+			}
+			if len(charges) == 0 {
+				report.Skipped++
+				continue
+			}
+
+			matched := true
+			for _, charge := range charges {
+				discrepancy, found := uc.reconcileCharge(transaction, charge, dryRun)
+				if !found {
+					continue
+				}
+				matched = false
+				if discrepancy.Action == model.ReconcileFixed {
+					report.Fixed++
+				}
+				report.Discrepancies = append(report.Discrepancies, discrepancy)
+			}
+			if matched {
+				report.Matched++
+			}
+		}
+
+		if len(transactions) < reconcilePageSize {
+			return report, nil
+		}
+	}
+}
+
+func (uc *reconcileUsecase) gatewayCharges(transaction model.Transaction) ([]gatewayCharge, error) {
+	if transaction.SplitCount == 0 {
+		return []gatewayCharge{{orderID: gatewayOrderID(uc.transactionRepo, uc.logger, transaction.OrderID),
+			localStatus: transaction.PaymentStatus, localAmount: transaction.GrossAmount}}, nil
+	}
+
+	shares, err := uc.paymentShareRepo.GetPaymentSharesByOrderID(transaction.OrderID)
+	if err != nil {
+		uc.logger.Error("Error when getting payment shares", zap.Error(err))
+		return nil, err
+	}
+
+	var charges []gatewayCharge
+	for _, share := range shares {
+		// refund diurus oleh split sweeper
+		if share.Status == model.PaymentShareRefunded || share.Status == model.PaymentShareRefundFailed {
+			continue
+		}
+		charges = append(charges, gatewayCharge{orderID: share.ShareOrderID, localStatus: shareStatus(share.Status),
+			localAmount: share.Amount, share: true})
+	}
+
+	return charges, nil
+}
+
+// shareStatus maps a payment share status to the order status midtrans is compared with
+func shareStatus(status string) string {
+	if status == model.PaymentSharePaid {
+		return "completed"
+	}
+
+	return status
+}
+
+func (uc *reconcileUsecase) reconcileCharge(transaction model.Transaction, charge gatewayCharge, dryRun bool) (model.ReconcileDiscrepancy, bool) {
+	discrepancy := model.ReconcileDiscrepancy{
+		TransactionID:   transaction.TransactionID,
+		OrderID:         charge.orderID,
+		TransactionDate: transaction.TransactionDate,
+		LocalStatus:     charge.localStatus,
+		LocalAmount:     charge.localAmount,
+		Action:          model.ReconcileManual,
+	}
+
+	statusResp, err := uc.paymentGateway.CheckStatus(charge.orderID)
+	if err != nil {
+		if gateway.StatusCode(err) == http.StatusNotFound {
+			// user tidak pernah memilih metode pembayaran di snap, share yang belum dibuka menunggu deadline split
+			if charge.localStatus == "cancelled" || (charge.share && charge.localStatus == "pending") {
+				return discrepancy, false
+			}
+			discrepancy.GatewayStatus = "not_found"
+			discrepancy.Reason = "order not found on midtrans"
+			return discrepancy, true
+		}
+		uc.logger.Error("Error when checking transaction status", zap.String("order_id", charge.orderID), zap.Error(err))
+		discrepancy.Reason = "check status failed: " + err.Error()
+		return discrepancy, true
+	}
+
+	discrepancy.GatewayStatus = statusResp.TransactionStatus
+	discrepancy.FraudStatus = statusResp.FraudStatus
+	discrepancy.GatewayAmount = parseGrossAmount(statusResp.GrossAmount)
+
+	expected := expectedPaymentStatus(statusResp.TransactionStatus, statusResp.FraudStatus)
+	amountMismatch := charge.localAmount > 0 && discrepancy.GatewayAmount != charge.localAmount
+	switch {
+	case expected == charge.localStatus && !amountMismatch:
+		return discrepancy, false
+	case amountMismatch:
+		discrepancy.Reason = "gross amount differs from midtrans"
+		return discrepancy, true
+	case expected == "":
+		discrepancy.Reason = "midtrans status is not handled automatically"
+		return discrepancy, true
+	case expected == "completed" && (charge.localStatus == "cancelled" || transaction.PaymentStatus == "cancelled"):
+		// share yang masih pending dari order yang sudah batal juga di-refund
+		return uc.refundCharge(charge, statusResp, discrepancy, dryRun), true
+	case charge.localStatus != "pending":
+		discrepancy.Reason = fmt.Sprintf("order is %s but midtrans says %s", charge.localStatus, statusResp.TransactionStatus)
+		return discrepancy, true
+	case expected == "completed" && charge.localAmount == 0:
+		// order lama belum menyimpan gross amount, jangan diselesaikan tanpa dicek nominalnya
+		discrepancy.Reason = "paid on midtrans but the gross amount was not stored to verify"
+		return discrepancy, true
+	case statusResp.TransactionStatus == "deny":
+		// webhook belum menangani deny, statusnya tidak akan berubah
+		discrepancy.Reason = "denied on midtrans"
+		return discrepancy, true
+	}
+
+	discrepancy.Reason = "pending but midtrans says " + statusResp.TransactionStatus
+	if dryRun {
+		discrepancy.Action = model.ReconcileWouldFix
+		return discrepancy, true
+	}
+
+	event := model.TransactionEvent{Actor: model.TransactionActorReconcile}
+	if err := uc.paymentNotificationUsecase.ApplyPaymentStatus(charge.orderID, statusResp, event); err != nil {
+		discrepancy.Action = model.ReconcileFixFailed
+		discrepancy.Reason += ", fix failed: " + err.Error()
+		return discrepancy, true
+	}
+
+	status, err := uc.currentStatus(transaction, charge)
+	if err != nil {
+		discrepancy.Action = model.ReconcileFixFailed
+		discrepancy.Reason += ", could not read the order back: " + err.Error()
+		return discrepancy, true
+	}
+	if status != expected {
+		discrepancy.Action = model.ReconcileFixFailed
+		discrepancy.Reason += ", still " + status + " after the fix"
+		return discrepancy, true
+	}
+	discrepancy.Action = model.ReconcileFixed
+
+	return discrepancy, true
+}
+
+// refundCharge refunds an order or share that was paid after it was cancelled, the order itself stays cancelled
+func (uc *reconcileUsecase) refundCharge(charge gatewayCharge, statusResp *coreapi.TransactionStatusResponse,
+	discrepancy model.ReconcileDiscrepancy, dryRun bool) model.ReconcileDiscrepancy {
+	discrepancy.Reason = "cancelled but paid on midtrans, needs a refund"
+	if dryRun {
+		discrepancy.Action = model.ReconcileWouldFix
+		return discrepancy
+	}
+
+	event := model.TransactionEvent{Actor: model.TransactionActorReconcile}
+	if err := uc.paymentNotificationUsecase.ApplyPaymentStatus(charge.orderID, statusResp, event); err != nil {
+		discrepancy.Action = model.ReconcileFixFailed
+		discrepancy.Reason += ", refund failed: " + err.Error()
+		return discrepancy
+	}
+
+	// refund share yang ditolak hanya dicatat refund_failed, cek lagi ke midtrans
+	refundResp, err := uc.paymentGateway.CheckStatus(charge.orderID)
+	if err != nil {
+		discrepancy.Action = model.ReconcileFixFailed
+		discrepancy.Reason += ", could not read the refund back: " + err.Error()
+		return discrepancy
+	}
+	if refundResp.TransactionStatus != "refund" && refundResp.TransactionStatus != "partial_refund" {
+		discrepancy.Action = model.ReconcileFixFailed
+		discrepancy.Reason += ", still " + refundResp.TransactionStatus + " after the refund"
+		return discrepancy
+	}
+	discrepancy.Action = model.ReconcileFixed
+
+	return discrepancy
+}
+
+func (uc *reconcileUsecase) currentStatus(transaction model.Transaction, charge gatewayCharge) (string, error) {
+	if charge.share {
+		share, err := uc.paymentShareRepo.GetPaymentShareByShareOrderID(charge.orderID)
+		if err != nil {
+			uc.logger.Error("Error when getting payment share", zap.Error(err))
+			return "", err
+		}
+		return shareStatus(share.Status), nil
+	}
+
+	current, err := uc.transactionRepo.GetTransactionByOrderID(transaction.OrderID)
+	if err != nil {
+		uc.logger.Error("Error when getting transaction by orderID", zap.Error(err))
+		return "", err
+	}
+
+	return current.PaymentStatus, nil
+}
+
+// expectedPaymentStatus maps a midtrans status to ours the way the webhook does, "" when there is no mapping
+func expectedPaymentStatus(transactionStatus, fraudStatus string) string {
+	switch transactionStatus {
+	case "settlement":
+		return "completed"
+	case "capture":
+		if fraudStatus == "accept" {
+			return "completed"
+		}
+		return "pending"
+	case "pending":
+		return "pending"
+	case "cancel", "expire", "deny":
+		return "cancelled"
+	}
+
+	return ""
+}
+
+// parseGrossAmount reads midtrans' "150000.00"
+func parseGrossAmount(grossAmount string) int64 {
+	amount, err := strconv.ParseFloat(grossAmount, 64)
+	if err != nil {
+		return 0
+	}
+
+	return int64(math.Round(amount))
+}
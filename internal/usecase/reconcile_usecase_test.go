@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/internal/gateway"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/mock"
+	mock_config "github.com/SyamSolution/transaction-service/mock/config"
+	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/midtrans/midtrans-go/snap"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReconcile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionRepo := mock.NewMockTransactionPersister(ctrl)
+	mockPaymentShareRepo := mock.NewMockPaymentSharePersister(ctrl)
+	mockPaymentNotificationUsecase := mock.NewMockPaymentNotificationExecutor(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+	// tidak ada link yang dibuat ulang
+	mockTransactionRepo.EXPECT().GetPaymentByOrderID(gomock.Any()).Return(model.Payment{}, sql.ErrNoRows).AnyTimes()
+	logger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+	fakeGateway := gateway.NewFakeGateway(func(payload map[string]interface{}) {})
+
+	var fixed []string
+	reconcile := model.TransactionEvent{Actor: model.TransactionActorReconcile}
+	mockPaymentNotificationUsecase.EXPECT().ApplyPaymentStatus(gomock.Any(), gomock.Any(), reconcile).
+		DoAndReturn(func(orderID string, statusResp *coreapi.TransactionStatusResponse, event model.TransactionEvent) error {
+			fixed = append(fixed, orderID)
+			return nil
+		}).AnyTimes()
+
+	uc := NewReconcileUsecase(mockTransactionRepo, mockPaymentShareRepo, mockPaymentNotificationUsecase, fakeGateway, logger)
+
+	charge := func(orderID string, grossAmount int64, status string) {
+		_, err := fakeGateway.CreateCharge(&snap.Request{
+			TransactionDetails: midtrans.TransactionDetails{OrderID: orderID, GrossAmt: grossAmount},
+		})
+		assert.NoError(t, err)
+		if status != "" {
+			assert.NoError(t, fakeGateway.SetStatus(orderID, status, ""))
+		}
+	}
+	charge("ORDER-1", 150000, "settlement")
+	charge("ORDER-2", 150000, "settlement")
+	charge("ORDER-3", 150000, "settlement")
+	charge("ORDER-4", 150000, "expire")
+	charge("ORDER-6", 150000, "")
+	charge("ORDER-7", 150000, "expire")
+	charge("ORDER-5-S1", 75000, "settlement")
+	charge("ORDER-5-S2", 75000, "settlement")
+	charge("ORDER-8-S1", 75000, "settlement")
+
+	from := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	transactions := []model.Transaction{
+		{TransactionID: 1, OrderID: "ORDER-1", PaymentStatus: "pending", GrossAmount: 150000},
+		{TransactionID: 2, OrderID: "ORDER-2", PaymentStatus: "completed", GrossAmount: 150000},
+		{TransactionID: 3, OrderID: "ORDER-3", PaymentStatus: "pending", GrossAmount: 140000},
+		{TransactionID: 4, OrderID: "ORDER-4", PaymentStatus: "completed", GrossAmount: 150000},
+		{TransactionID: 5, OrderID: "ORDER-5", PaymentStatus: "pending", SplitCount: 2},
+		{TransactionID: 6, OrderID: "ORDER-6", PaymentStatus: "pending", GrossAmount: 150000},
+		{TransactionID: 7, OrderID: "ORDER-7", PaymentStatus: "pending", GrossAmount: 150000},
+		{TransactionID: 8, OrderID: "ORDER-8", PaymentStatus: "pending", SplitCount: 2},
+		{TransactionID: 9, OrderID: "ORDER-9", PaymentStatus: "pending", SplitCount: 2},
+	}
+	mockTransactionRepo.EXPECT().GetTransactionsByDateRange(from, to, 0, reconcilePageSize).Return(transactions, nil)
+	mockTransactionRepo.EXPECT().GetTransactionByOrderID("ORDER-1").Return(model.Transaction{PaymentStatus: "completed"}, nil)
+	mockTransactionRepo.EXPECT().GetTransactionByOrderID("ORDER-7").Return(model.Transaction{PaymentStatus: "pending"}, nil)
+
+	// share pertama sudah tercatat lunas, share kedua lunas di midtrans tapi notifikasinya hilang
+	mockPaymentShareRepo.EXPECT().GetPaymentSharesByOrderID("ORDER-5").Return([]model.PaymentShare{
+		{OrderID: "ORDER-5", ShareOrderID: "ORDER-5-S1", Amount: 75000, Status: model.PaymentSharePaid},
+		{OrderID: "ORDER-5", ShareOrderID: "ORDER-5-S2", Amount: 75000, Status: model.PaymentSharePending},
+	}, nil)
+	mockPaymentShareRepo.EXPECT().GetPaymentShareByShareOrderID("ORDER-5-S2").
+		Return(model.PaymentShare{ShareOrderID: "ORDER-5-S2", Status: model.PaymentSharePaid}, nil)
+	// share kedua belum pernah dibuka, menunggu deadline split
+	mockPaymentShareRepo.EXPECT().GetPaymentSharesByOrderID("ORDER-8").Return([]model.PaymentShare{
+		{OrderID: "ORDER-8", ShareOrderID: "ORDER-8-S1", Amount: 75000, Status: model.PaymentSharePaid},
+		{OrderID: "ORDER-8", ShareOrderID: "ORDER-8-S2", Amount: 75000, Status: model.PaymentSharePending},
+	}, nil)
+	mockPaymentShareRepo.EXPECT().GetPaymentSharesByOrderID("ORDER-9").Return([]model.PaymentShare{}, nil)
+
+	report, err := uc.Reconcile(from, to, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ORDER-1", "ORDER-5-S2", "ORDER-7"}, fixed)
+	assert.Equal(t, 9, report.Checked)
+	assert.Equal(t, 2, report.Matched)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, 2, report.Fixed)
+
+	actions := make(map[string]string)
+	for _, discrepancy := range report.Discrepancies {
+		actions[discrepancy.OrderID] = discrepancy.Action
+	}
+	assert.Equal(t, map[string]string{
+		"ORDER-1":    model.ReconcileFixed,
+		"ORDER-3":    model.ReconcileManual,
+		"ORDER-4":    model.ReconcileManual,
+		"ORDER-5-S2": model.ReconcileFixed,
+		"ORDER-6":    model.ReconcileManual,
+		"ORDER-7":    model.ReconcileFixFailed,
+	}, actions)
+}
+
+func TestReconcileRefundsCancelledOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionRepo := mock.NewMockTransactionPersister(ctrl)
+	mockPaymentShareRepo := mock.NewMockPaymentSharePersister(ctrl)
+	mockTransactionUsecase := mock.NewMockTransactionExecutor(ctrl)
+	mockIssuedTicketUsecase := mock.NewMockIssuedTicketExecutor(ctrl)
+	mockInvoiceUsecase := mock.NewMockInvoiceExecutor(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+	// tidak ada link yang dibuat ulang
+	mockTransactionRepo.EXPECT().GetPaymentByOrderID(gomock.Any()).Return(model.Payment{}, sql.ErrNoRows).AnyTimes()
+	logger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+	fakeGateway := gateway.NewFakeGateway(func(payload map[string]interface{}) {})
+
+	paymentNotificationUsecase := NewPaymentNotificationUsecase(mockTransactionUsecase, mockIssuedTicketUsecase, mockInvoiceUsecase,
+		fakeGateway, logger)
+	uc := NewReconcileUsecase(mockTransactionRepo, mockPaymentShareRepo, paymentNotificationUsecase, fakeGateway, logger)
+
+	// order sudah dibatalkan saat user baru membayar
+	_, err := fakeGateway.CreateCharge(&snap.Request{
+		TransactionDetails: midtrans.TransactionDetails{OrderID: "ORDER-1", GrossAmt: 150000},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, fakeGateway.SetStatus("ORDER-1", "settlement", ""))
+
+	from := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+
+	mockTransactionRepo.EXPECT().GetTransactionsByDateRange(from, to, 0, reconcilePageSize).
+		Return([]model.Transaction{{TransactionID: 1, OrderID: "ORDER-1", PaymentStatus: "cancelled", GrossAmount: 150000}}, nil).Times(2)
+	mockTransactionUsecase.EXPECT().UpdatePaymentShare("ORDER-1", "settlement", "", gomock.Any()).
+		Return(model.PaymentShareResult{}, ErrPaymentShareNotFound)
+	mockTransactionUsecase.EXPECT().ResolvePaymentLink("ORDER-1").Return("ORDER-1", true, nil)
+	mockTransactionUsecase.EXPECT().RecordGatewayPayment("ORDER-1", gomock.Any()).Return(nil)
+	mockTransactionUsecase.EXPECT().GetTransactionByOrderID("ORDER-1").Return(model.TransactionResponse{OrderID: "ORDER-1", Status: "cancelled"}, nil)
+
+	dryRun, err := uc.Reconcile(from, to, true)
+	assert.NoError(t, err)
+	assert.Len(t, dryRun.Discrepancies, 1)
+	assert.Equal(t, model.ReconcileWouldFix, dryRun.Discrepancies[0].Action)
+
+	report, err := uc.Reconcile(from, to, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Fixed)
+	assert.Len(t, report.Discrepancies, 1)
+	assert.Equal(t, model.ReconcileFixed, report.Discrepancies[0].Action)
+	assert.Equal(t, "cancelled but paid on midtrans, needs a refund", report.Discrepancies[0].Reason)
+
+	// order tidak pernah diselesaikan, pembayarannya dikembalikan
+	status, err := fakeGateway.CheckStatus("ORDER-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "refund", status.TransactionStatus)
+}
+
+func TestReconcileDryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionRepo := mock.NewMockTransactionPersister(ctrl)
+	mockPaymentShareRepo := mock.NewMockPaymentSharePersister(ctrl)
+	mockPaymentNotificationUsecase := mock.NewMockPaymentNotificationExecutor(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+	// tidak ada link yang dibuat ulang
+	mockTransactionRepo.EXPECT().GetPaymentByOrderID(gomock.Any()).Return(model.Payment{}, sql.ErrNoRows).AnyTimes()
+	fakeGateway := gateway.NewFakeGateway(func(payload map[string]interface{}) {})
+
+	uc := NewReconcileUsecase(mockTransactionRepo, mockPaymentShareRepo, mockPaymentNotificationUsecase, fakeGateway, logger)
+
+	_, err := fakeGateway.CreateCharge(&snap.Request{
+		TransactionDetails: midtrans.TransactionDetails{OrderID: "ORDER-1", GrossAmt: 150000},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, fakeGateway.SetStatus("ORDER-1", "capture", "accept"))
+
+	from := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+
+	mockTransactionRepo.EXPECT().GetTransactionsByDateRange(from, to, 0, reconcilePageSize).
+		Return([]model.Transaction{{TransactionID: 1, OrderID: "ORDER-1", PaymentStatus: "pending", GrossAmount: 150000}}, nil)
+
+	report, err := uc.Reconcile(from, to, true)
+
+	assert.NoError(t, err)
+	assert.Len(t, report.Discrepancies, 1)
+	assert.Equal(t, model.ReconcileWouldFix, report.Discrepancies[0].Action)
+	assert.Equal(t, int64(150000), report.Discrepancies[0].GatewayAmount)
+	assert.Equal(t, 0, report.Fixed)
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/helper"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/repository"
+	"go.uber.org/zap"
+)
+
+const maxReportRange = 366 * 24 * time.Hour
+
+type reportUsecase struct {
+	reportRepo repository.ReportPersister
+	logger     config.Logger
+}
+
+type ReportExecutor interface {
+	GetSalesReport(request model.SalesReportRequest) (model.SalesReport, error)
+}
+
+func NewReportUsecase(reportRepo repository.ReportPersister, logger config.Logger) ReportExecutor {
+	return &reportUsecase{reportRepo: reportRepo, logger: logger}
+}
+
+// GetSalesReport sums sales between From and To inclusive, net of discount and voucher
+func (uc *reportUsecase) GetSalesReport(request model.SalesReportRequest) (model.SalesReport, error) {
+	from, to, ok := parseDateRange(request.From, request.To)
+	if !ok {
+		return model.SalesReport{}, ErrReportRange
+	}
+
+	report := model.SalesReport{
+		GroupBy:  request.GroupBy,
+		From:     request.From,
+		To:       request.To,
+		Status:   request.Status,
+		Currency: request.Currency,
+		Rows:     []model.SalesReportLine{},
+	}
+	if report.Status == "" {
+		report.Status = "completed"
+	}
+	if report.Currency == "" {
+		report.Currency = "USD"
+	}
+	status := report.Status
+	if status == "all" {
+		status = ""
+	}
+
+	rows, total, err := uc.reportRepo.GetSalesReport(request.GroupBy, from, to.AddDate(0, 0, 1), status)
+	if err != nil {
+		uc.logger.Error("Error when getting sales report", zap.Error(err))
+		return model.SalesReport{}, err
+	}
+
+	if request.GroupBy == model.ReportGroupEvent {
+		rows = uc.groupByEvent(rows)
+	}
+	for _, row := range rows {
+		report.Rows = append(report.Rows, reportLine(row, report.Currency))
+	}
+	report.Total = reportLine(total, report.Currency)
+	report.Total.Key = "total"
+
+	return report, nil
+}
+
+// parseDateRange parses YYYY-MM-DD days, ok is false when to is before from or the range is over a year
+func parseDateRange(from, to string) (time.Time, time.Time, bool) {
+	start, err := time.ParseInLocation("2006-01-02", from, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	end, err := time.ParseInLocation("2006-01-02", to, time.Local)
+	if err != nil || end.Before(start) || end.Sub(start) > maxReportRange {
+		return time.Time{}, time.Time{}, false
+	}
+
+	return start, end, true
+}
+
+// groupByEvent merges the per ticket rows into their event. An order with several ticket types of one event
+// is counted once per ticket type in the event's transactions, the total counts it once.
+func (uc *reportUsecase) groupByEvent(rows []model.SalesReportRow) []model.SalesReportRow {
+	var events []model.SalesReportRow
+	eventIndex := make(map[string]int)
+	for _, row := range rows {
+		key := "ticket-" + row.Key
+		if ticketID, err := strconv.Atoi(row.Key); err == nil {
+			ticketEvent, err := helper.GetTicketEventByTicketID(ticketID)
+			if err != nil {
+				uc.logger.Error("Error when getting ticket event by ticket ID", zap.Error(err))
+			} else if ticketEvent.EventName != "" {
+				key = ticketEvent.EventName
+			}
+		}
+
+		i, ok := eventIndex[key]
+		if !ok {
+			i = len(events)
+			eventIndex[key] = i
+			events = append(events, model.SalesReportRow{Key: key})
+		}
+		events[i].Transactions += row.Transactions
+		events[i].Tickets += row.Tickets
+		events[i].GrossAmount += row.GrossAmount
+		events[i].NetAmount += row.NetAmount
+	}
+
+	return events
+}
+
+// reportLine converts a row to the report currency, IDR uses the same rate as the midtrans charge
+func reportLine(row model.SalesReportRow, currency string) model.SalesReportLine {
+	convert := func(amount float64) float64 {
+		if currency == "IDR" {
+			return math.Round(amount * idrExchangeRate)
+		}
+		return math.Round(amount*100) / 100
+	}
+
+	line := model.SalesReportLine{
+		Key:          row.Key,
+		Transactions: row.Transactions,
+		Tickets:      row.Tickets,
+		GrossAmount:  convert(row.GrossAmount),
+		NetAmount:    convert(row.NetAmount),
+	}
+	line.Discount = convert(row.GrossAmount - row.NetAmount)
+
+	return line
+}
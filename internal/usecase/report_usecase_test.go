@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/mock"
+	mock_config "github.com/SyamSolution/transaction-service/mock/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetSalesReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockReportRepo := mock.NewMockReportPersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+
+	uc := NewReportUsecase(mockReportRepo, logger)
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)
+
+	t.Run("defaults to completed orders in USD", func(t *testing.T) {
+		mockReportRepo.EXPECT().GetSalesReport(model.ReportGroupTicketType, from, to, "completed").Return(
+			[]model.SalesReportRow{{Key: "VIP", Transactions: 2, Tickets: 3, GrossAmount: 300, NetAmount: 269.999}},
+			model.SalesReportRow{Transactions: 2, Tickets: 3, GrossAmount: 300, NetAmount: 269.999}, nil)
+
+		report, err := uc.GetSalesReport(model.SalesReportRequest{GroupBy: model.ReportGroupTicketType, From: "2026-10-01", To: "2026-10-18"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "completed", report.Status)
+		assert.Equal(t, "USD", report.Currency)
+		assert.Equal(t, []model.SalesReportLine{{Key: "VIP", Transactions: 2, Tickets: 3, GrossAmount: 300, Discount: 30, NetAmount: 270}}, report.Rows)
+		assert.Equal(t, "total", report.Total.Key)
+	})
+
+	t.Run("all statuses in IDR", func(t *testing.T) {
+		mockReportRepo.EXPECT().GetSalesReport(model.ReportGroupDay, from, to, "").Return(
+			[]model.SalesReportRow{{Key: "2026-10-02", Transactions: 1, Tickets: 1, GrossAmount: 10, NetAmount: 10}},
+			model.SalesReportRow{Transactions: 1, Tickets: 1, GrossAmount: 10, NetAmount: 10}, nil)
+
+		report, err := uc.GetSalesReport(model.SalesReportRequest{GroupBy: model.ReportGroupDay, From: "2026-10-01", To: "2026-10-18",
+			Status: "all", Currency: "IDR"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, float64(10*idrExchangeRate), report.Rows[0].NetAmount)
+		assert.Equal(t, float64(0), report.Rows[0].Discount)
+	})
+
+	t.Run("range ends before it starts", func(t *testing.T) {
+		_, err := uc.GetSalesReport(model.SalesReportRequest{GroupBy: model.ReportGroupDay, From: "2026-10-18", To: "2026-10-01"})
+
+		assert.ErrorIs(t, err, ErrReportRange)
+	})
+
+	t.Run("range over a year", func(t *testing.T) {
+		_, err := uc.GetSalesReport(model.SalesReportRequest{GroupBy: model.ReportGroupDay, From: "2025-01-01", To: "2026-10-01"})
+
+		assert.ErrorIs(t, err, ErrReportRange)
+	})
+}
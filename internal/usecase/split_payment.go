@@ -0,0 +1,276 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/internal/gateway"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/midtrans/midtrans-go/snap"
+	"go.uber.org/zap"
+)
+
+const defaultMaxPaymentShares = 10
+
+// maxPaymentShares is the largest split allowed, configured with SPLIT_MAX_SHARES
+func maxPaymentShares() int {
+	max, err := strconv.Atoi(os.Getenv("SPLIT_MAX_SHARES"))
+	if err != nil || max < 2 {
+		return defaultMaxPaymentShares
+	}
+
+	return max
+}
+
+func shareOrderID(orderID string, number int) string {
+	return fmt.Sprintf("%s-S%d", orderID, number)
+}
+
+// splitAmount divides gross into n shares, the first share carries the remainder
+func splitAmount(gross int64, n int) []int64 {
+	amounts := make([]int64, n)
+	for i := range amounts {
+		amounts[i] = gross / int64(n)
+	}
+	amounts[0] += gross % int64(n)
+
+	return amounts
+}
+
+func paymentShareResponses(shares []model.PaymentShare) []model.PaymentShareResponse {
+	responses := make([]model.PaymentShareResponse, 0, len(shares))
+	for _, share := range shares {
+		responses = append(responses, model.PaymentShareResponse{
+			ShareOrderID: share.ShareOrderID,
+			ShareNumber:  share.ShareNumber,
+			Amount:       share.Amount,
+			Status:       share.Status,
+			Token:        share.Token,
+			RedirectURL:  share.RedirectURL,
+		})
+	}
+
+	return responses
+}
+
+// createPaymentShares opens one snap payment per share, every share expires at the split deadline
+func (uc *transactionUsecase) createPaymentShares(transaction model.Transaction, grossAmount int64, paymentMethod model.PaymentMethod,
+	customerDetail *midtrans.CustomerDetails, now time.Time) ([]model.PaymentShare, error) {
+	amounts := splitAmount(grossAmount, transaction.SplitCount)
+	shares := make([]model.PaymentShare, 0, len(amounts))
+	for i, amount := range amounts {
+		shares = append(shares, model.PaymentShare{
+			OrderID:      transaction.OrderID,
+			ShareOrderID: shareOrderID(transaction.OrderID, i+1),
+			ShareNumber:  i + 1,
+			Amount:       amount,
+			Status:       model.PaymentSharePending,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		})
+	}
+
+	if err := uc.paymentShareRepo.CreatePaymentShares(shares); err != nil {
+		uc.logger.Error("Error when creating payment shares", zap.Error(err))
+		return nil, err
+	}
+
+	window := transaction.SplitDeadline.Sub(now)
+	for i := range shares {
+		items := []midtrans.ItemDetails{{
+			ID:    "SHARE",
+			Name:  fmt.Sprintf("Share %d/%d %s", shares[i].ShareNumber, len(shares), transaction.OrderID),
+			Price: shares[i].Amount,
+			Qty:   1,
+		}}
+		req := &snap.Request{
+			TransactionDetails: midtrans.TransactionDetails{
+				OrderID:  shares[i].ShareOrderID,
+				GrossAmt: shares[i].Amount,
+			},
+			Items:           &items,
+			CustomerDetail:  customerDetail,
+			EnabledPayments: gateway.EnabledPayments(paymentMethod.Code),
+			Expiry: &snap.ExpiryDetails{
+				StartTime: now.Format(midtransTimeFormat),
+				Unit:      "minute",
+				Duration:  int64(window.Minutes()),
+			},
+		}
+		if finishURL := os.Getenv("MIDTRANS_FINISH_URL"); finishURL != "" {
+			req.Callbacks = &snap.Callbacks{Finish: finishURL}
+		}
+
+		snapResp, err := uc.paymentGateway.CreateCharge(req)
+		if err != nil {
+			uc.logger.Error("Error when creating payment share", zap.Error(err))
+			return nil, err
+		}
+		shares[i].Token = snapResp.Token
+		shares[i].RedirectURL = snapResp.RedirectURL
+
+		if err = uc.paymentShareRepo.UpdatePaymentShareLink(shares[i].ShareOrderID, snapResp.Token, snapResp.RedirectURL); err != nil {
+			uc.logger.Error("Error when saving payment share link", zap.Error(err))
+		}
+	}
+
+	return shares, nil
+}
+
+// UpdatePaymentShare applies a midtrans notification of one share. AllPaid is set once, for the
+// notification that pays the last share, that is when the order is settled and tickets are issued.
+func (uc *transactionUsecase) UpdatePaymentShare(shareOrderID, transactionStatus, fraudStatus string,
+	event model.TransactionEvent) (model.PaymentShareResult, error) {
+	share, err := uc.paymentShareRepo.GetPaymentShareByShareOrderID(shareOrderID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.PaymentShareResult{}, ErrPaymentShareNotFound
+		}
+		uc.logger.Error("Error when getting payment share", zap.Error(err))
+		return model.PaymentShareResult{}, err
+	}
+
+	result := model.PaymentShareResult{OrderID: share.OrderID}
+	switch transactionStatus {
+	case "capture":
+		if fraudStatus != "accept" {
+			break
+		}
+		fallthrough
+	case "settlement":
+		result.AllPaid, err = uc.paymentShareRepo.MarkPaymentSharePaid(shareOrderID, time.Now())
+		if err != nil {
+			uc.logger.Error("Error when marking payment share paid", zap.Error(err))
+			return result, err
+		}
+
+		// order sudah gagal duluan, bagian yang baru dibayar langsung di-refund. Share yang sudah dicancel juga,
+		// link snap masih bisa dibayar kalau cancel ke gateway tidak menemukan transaksinya
+		transaction, err := uc.transactionRepo.GetTransactionByOrderID(share.OrderID)
+		if err != nil {
+			uc.logger.Error("Error when getting transaction by orderID", zap.Error(err))
+			return result, err
+		}
+		if transaction.PaymentStatus == "cancelled" &&
+			(share.Status == model.PaymentSharePending || share.Status == model.PaymentShareCancelled) {
+			uc.refundPaymentShare(share, "order was cancelled")
+		}
+	case "cancel", "expire":
+		event.PayloadReference = shareOrderID
+		event.Note = "share " + shareOrderID + " was not paid"
+		if err = uc.failSplitTransaction(share.OrderID, event); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// ExpireSplitTransactions fails every split order that is still not fully paid after its deadline and
+// retries the refunds the gateway refused before
+func (uc *transactionUsecase) ExpireSplitTransactions() error {
+	orderIDs, err := uc.paymentShareRepo.GetOverdueSplitOrderIDs(time.Now())
+	if err != nil {
+		uc.logger.Error("Error when getting overdue split transactions", zap.Error(err))
+		return err
+	}
+
+	for _, orderID := range orderIDs {
+		if err := uc.failSplitTransaction(orderID, model.TransactionEvent{Actor: model.TransactionActorSweeper,
+			Note: "split payment deadline passed"}); err != nil {
+			uc.logger.Error("Error when expiring split transaction", zap.String("order_id", orderID), zap.Error(err))
+		}
+	}
+
+	shares, err := uc.paymentShareRepo.GetRefundFailedPaymentShares()
+	if err != nil {
+		uc.logger.Error("Error when getting payment shares to refund", zap.Error(err))
+		return err
+	}
+	for _, share := range shares {
+		uc.refundPaymentShare(share, "retry refund of failed split payment")
+	}
+
+	return nil
+}
+
+// failSplitTransaction cancels a split order: paid shares are refunded, open shares cancelled and the
+// tickets released. Only the call that moves the order out of pending does the work.
+func (uc *transactionUsecase) failSplitTransaction(orderID string, event model.TransactionEvent) error {
+	cancelled, err := uc.transactionRepo.CancelTransaction(orderID, event)
+	if err != nil {
+		uc.logger.Error("Error when cancelling transaction", zap.Error(err))
+		return err
+	}
+	if !cancelled {
+		return nil
+	}
+
+	shares, err := uc.paymentShareRepo.GetPaymentSharesByOrderID(orderID)
+	if err != nil {
+		uc.logger.Error("Error when getting payment shares", zap.Error(err))
+		return err
+	}
+	for _, share := range shares {
+		switch share.Status {
+		case model.PaymentSharePaid:
+			uc.refundPaymentShare(share, event.Note)
+		case model.PaymentSharePending:
+			if _, err := uc.paymentGateway.Cancel(share.ShareOrderID); err != nil && gateway.StatusCode(err) != http.StatusNotFound {
+				// bisa jadi baru saja dibayar, notifikasi settlement akan me-refund bagian ini
+				uc.logger.Error("Error when cancelling payment share", zap.Error(err))
+				continue
+			}
+			if err := uc.paymentShareRepo.UpdatePaymentShareStatus(share.ShareOrderID, model.PaymentShareCancelled); err != nil {
+				uc.logger.Error("Error when updating payment share status", zap.Error(err))
+			}
+		}
+	}
+
+	transaction, err := uc.transactionRepo.GetTransactionByOrderID(orderID)
+	if err != nil {
+		uc.logger.Error("Error when getting transaction by orderID", zap.Error(err))
+		return err
+	}
+	detailTransactions, err := uc.transactionRepo.GetDetailTransactionByTransactionID(transaction.TransactionID)
+	if err != nil {
+		uc.logger.Error("Error when getting detail transaction by transactionID", zap.Error(err))
+		return err
+	}
+
+	uc.releaseVoucher(orderID)
+	uc.releaseTickets(detailTransactions)
+	uc.promoteWaitlist(detailTransactions)
+
+	return nil
+}
+
+// refundPaymentShare refunds a paid share. A refused refund is kept as refund_failed so the sweeper retries
+// it, the refund key stays the same so midtrans does not pay it out twice.
+func (uc *transactionUsecase) refundPaymentShare(share model.PaymentShare, reason string) {
+	_, err := uc.paymentGateway.Refund(share.ShareOrderID, &coreapi.RefundReq{
+		RefundKey: share.ShareOrderID + "-refund",
+		Amount:    share.Amount,
+		Reason:    reason,
+	})
+	if err != nil {
+		uc.logger.Error("Error when refunding payment share", zap.String("share_order_id", share.ShareOrderID), zap.Error(err))
+		if share.Status != model.PaymentShareRefundFailed {
+			if err = uc.paymentShareRepo.UpdatePaymentShareStatus(share.ShareOrderID, model.PaymentShareRefundFailed); err != nil {
+				uc.logger.Error("Error when updating payment share status", zap.Error(err))
+			}
+		}
+		return
+	}
+
+	if err = uc.paymentShareRepo.UpdatePaymentShareStatus(share.ShareOrderID, model.PaymentShareRefunded); err != nil {
+		uc.logger.Error("Error when updating payment share status", zap.Error(err))
+	}
+}
@@ -0,0 +1,202 @@
+package usecase
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/SyamSolution/transaction-service/helper"
+	"github.com/SyamSolution/transaction-service/internal/discount"
+	"github.com/SyamSolution/transaction-service/internal/gateway"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/mock"
+	mock_config "github.com/SyamSolution/transaction-service/mock/config"
+	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/snap"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSplitAmount(t *testing.T) {
+	assert.Equal(t, []int64{34, 33, 33}, splitAmount(100, 3))
+	assert.Equal(t, []int64{50, 50}, splitAmount(100, 2))
+}
+
+func TestUpdatePaymentShare(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionRepo := mock.NewMockTransactionPersister(ctrl)
+	mockVoucherRepo := mock.NewMockVoucherPersister(ctrl)
+	mockWaitlistRepo := mock.NewMockWaitlistPersister(ctrl)
+	mockPaymentShareRepo := mock.NewMockPaymentSharePersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+	logger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+
+	fakeGateway := gateway.NewFakeGateway(nil)
+	uc := NewTransactionUsecase(mockTransactionRepo, mockVoucherRepo, mockWaitlistRepo, mockPaymentShareRepo, fakeGateway,
+		helper.NewULIDOrderIDGenerator("ORDER-TEST-"), discount.NewGrulePolicy(), logger)
+
+	orderID := "ORDER-1"
+	first := model.PaymentShare{OrderID: orderID, ShareOrderID: "ORDER-1-S1", ShareNumber: 1, Amount: 50, Status: model.PaymentSharePending}
+	second := model.PaymentShare{OrderID: orderID, ShareOrderID: "ORDER-1-S2", ShareNumber: 2, Amount: 50, Status: model.PaymentSharePending}
+	webhook := model.TransactionEvent{Actor: model.TransactionActorWebhook}
+
+	t.Run("not a share", func(t *testing.T) {
+		mockPaymentShareRepo.EXPECT().GetPaymentShareByShareOrderID(orderID).Return(model.PaymentShare{}, sql.ErrNoRows)
+
+		_, err := uc.UpdatePaymentShare(orderID, "settlement", "", webhook)
+
+		assert.ErrorIs(t, err, ErrPaymentShareNotFound)
+	})
+
+	t.Run("last share settles the order", func(t *testing.T) {
+		mockPaymentShareRepo.EXPECT().GetPaymentShareByShareOrderID(second.ShareOrderID).Return(second, nil)
+		mockPaymentShareRepo.EXPECT().MarkPaymentSharePaid(second.ShareOrderID, gomock.Any()).Return(true, nil)
+		mockTransactionRepo.EXPECT().GetTransactionByOrderID(orderID).Return(model.Transaction{OrderID: orderID, PaymentStatus: "pending"}, nil)
+
+		result, err := uc.UpdatePaymentShare(second.ShareOrderID, "settlement", "", webhook)
+
+		assert.Nil(t, err)
+		assert.Equal(t, model.PaymentShareResult{OrderID: orderID, AllPaid: true}, result)
+	})
+
+	t.Run("challenged capture waits", func(t *testing.T) {
+		mockPaymentShareRepo.EXPECT().GetPaymentShareByShareOrderID(first.ShareOrderID).Return(first, nil)
+
+		result, err := uc.UpdatePaymentShare(first.ShareOrderID, "capture", "challenge", webhook)
+
+		assert.Nil(t, err)
+		assert.False(t, result.AllPaid)
+	})
+
+	t.Run("expired share refunds the paid ones", func(t *testing.T) {
+		// share pertama sudah lunas di gateway, share kedua belum pernah dibuka
+		_, err := fakeGateway.CreateCharge(&snap.Request{TransactionDetails: midtrans.TransactionDetails{OrderID: first.ShareOrderID, GrossAmt: 50}})
+		assert.Nil(t, err)
+		assert.Nil(t, fakeGateway.SetStatus(first.ShareOrderID, "settlement", ""))
+		paid := first
+		paid.Status = model.PaymentSharePaid
+
+		mockPaymentShareRepo.EXPECT().GetPaymentShareByShareOrderID(second.ShareOrderID).Return(second, nil)
+		mockTransactionRepo.EXPECT().CancelTransaction(orderID, model.TransactionEvent{Actor: model.TransactionActorWebhook,
+			PayloadReference: second.ShareOrderID, Note: "share " + second.ShareOrderID + " was not paid"}).Return(true, nil)
+		mockPaymentShareRepo.EXPECT().GetPaymentSharesByOrderID(orderID).Return([]model.PaymentShare{paid, second}, nil)
+		mockPaymentShareRepo.EXPECT().UpdatePaymentShareStatus(first.ShareOrderID, model.PaymentShareRefunded).Return(nil)
+		mockPaymentShareRepo.EXPECT().UpdatePaymentShareStatus(second.ShareOrderID, model.PaymentShareCancelled).Return(nil)
+		mockTransactionRepo.EXPECT().GetTransactionByOrderID(orderID).Return(model.Transaction{TransactionID: 1, OrderID: orderID}, nil)
+		mockTransactionRepo.EXPECT().GetDetailTransactionByTransactionID(1).Return([]model.DetailTransaction{}, nil)
+		mockVoucherRepo.EXPECT().ReleaseVoucher(orderID).Return(nil)
+
+		result, err := uc.UpdatePaymentShare(second.ShareOrderID, "expire", "", webhook)
+
+		assert.Nil(t, err)
+		assert.False(t, result.AllPaid)
+		status, err := fakeGateway.CheckStatus(first.ShareOrderID)
+		assert.Nil(t, err)
+		assert.Equal(t, "refund", status.TransactionStatus)
+	})
+
+	t.Run("share paid while the order is cancelled is refunded", func(t *testing.T) {
+		// share kedua dibayar tepat saat order dibatalkan sehingga cancel ke gateway ditolak,
+		// share pertama belum pernah dibuka
+		unopened := model.PaymentShare{OrderID: "ORDER-3", ShareOrderID: "ORDER-3-S1", ShareNumber: 1, Amount: 50, Status: model.PaymentSharePending}
+		justPaid := model.PaymentShare{OrderID: "ORDER-3", ShareOrderID: "ORDER-3-S2", ShareNumber: 2, Amount: 50, Status: model.PaymentSharePending}
+		_, err := fakeGateway.CreateCharge(&snap.Request{TransactionDetails: midtrans.TransactionDetails{OrderID: justPaid.ShareOrderID, GrossAmt: 50}})
+		assert.Nil(t, err)
+		assert.Nil(t, fakeGateway.SetStatus(justPaid.ShareOrderID, "settlement", ""))
+
+		mockPaymentShareRepo.EXPECT().GetPaymentShareByShareOrderID(unopened.ShareOrderID).Return(unopened, nil)
+		mockTransactionRepo.EXPECT().CancelTransaction("ORDER-3", gomock.Any()).Return(true, nil)
+		mockPaymentShareRepo.EXPECT().GetPaymentSharesByOrderID("ORDER-3").Return([]model.PaymentShare{unopened, justPaid}, nil)
+		mockPaymentShareRepo.EXPECT().UpdatePaymentShareStatus(unopened.ShareOrderID, model.PaymentShareCancelled).Return(nil)
+		mockTransactionRepo.EXPECT().GetTransactionByOrderID("ORDER-3").Return(model.Transaction{TransactionID: 3, OrderID: "ORDER-3"}, nil)
+		mockTransactionRepo.EXPECT().GetDetailTransactionByTransactionID(3).Return([]model.DetailTransaction{}, nil)
+		mockVoucherRepo.EXPECT().ReleaseVoucher("ORDER-3").Return(nil)
+
+		_, err = uc.UpdatePaymentShare(unopened.ShareOrderID, "expire", "", webhook)
+		assert.Nil(t, err)
+
+		// share kedua tetap pending, notifikasi settlement-nya yang me-refund
+		mockPaymentShareRepo.EXPECT().GetPaymentShareByShareOrderID(justPaid.ShareOrderID).Return(justPaid, nil)
+		mockPaymentShareRepo.EXPECT().MarkPaymentSharePaid(justPaid.ShareOrderID, gomock.Any()).Return(false, nil)
+		mockTransactionRepo.EXPECT().GetTransactionByOrderID("ORDER-3").Return(model.Transaction{OrderID: "ORDER-3", PaymentStatus: "cancelled"}, nil)
+		mockPaymentShareRepo.EXPECT().UpdatePaymentShareStatus(justPaid.ShareOrderID, model.PaymentShareRefunded).Return(nil)
+
+		result, err := uc.UpdatePaymentShare(justPaid.ShareOrderID, "settlement", "", webhook)
+
+		assert.Nil(t, err)
+		assert.False(t, result.AllPaid)
+		status, err := fakeGateway.CheckStatus(justPaid.ShareOrderID)
+		assert.Nil(t, err)
+		assert.Equal(t, "refund", status.TransactionStatus)
+
+		// share pertama sudah dicancel tapi link snap-nya masih dibayar
+		unopened.Status = model.PaymentShareCancelled
+		_, err = fakeGateway.CreateCharge(&snap.Request{TransactionDetails: midtrans.TransactionDetails{OrderID: unopened.ShareOrderID, GrossAmt: 50}})
+		assert.Nil(t, err)
+		assert.Nil(t, fakeGateway.SetStatus(unopened.ShareOrderID, "settlement", ""))
+
+		mockPaymentShareRepo.EXPECT().GetPaymentShareByShareOrderID(unopened.ShareOrderID).Return(unopened, nil)
+		mockPaymentShareRepo.EXPECT().MarkPaymentSharePaid(unopened.ShareOrderID, gomock.Any()).Return(false, nil)
+		mockTransactionRepo.EXPECT().GetTransactionByOrderID("ORDER-3").Return(model.Transaction{OrderID: "ORDER-3", PaymentStatus: "cancelled"}, nil)
+		mockPaymentShareRepo.EXPECT().UpdatePaymentShareStatus(unopened.ShareOrderID, model.PaymentShareRefunded).Return(nil)
+
+		_, err = uc.UpdatePaymentShare(unopened.ShareOrderID, "settlement", "", webhook)
+		assert.Nil(t, err)
+	})
+
+	t.Run("already failed order is left alone", func(t *testing.T) {
+		mockPaymentShareRepo.EXPECT().GetPaymentShareByShareOrderID(second.ShareOrderID).Return(second, nil)
+		mockTransactionRepo.EXPECT().CancelTransaction(orderID, gomock.Any()).Return(false, nil)
+
+		result, err := uc.UpdatePaymentShare(second.ShareOrderID, "cancel", "", webhook)
+
+		assert.Nil(t, err)
+		assert.False(t, result.AllPaid)
+	})
+}
+
+func TestExpireSplitTransactionsRetriesRefunds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionRepo := mock.NewMockTransactionPersister(ctrl)
+	mockVoucherRepo := mock.NewMockVoucherPersister(ctrl)
+	mockWaitlistRepo := mock.NewMockWaitlistPersister(ctrl)
+	mockPaymentShareRepo := mock.NewMockPaymentSharePersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+	logger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+
+	fakeGateway := gateway.NewFakeGateway(nil)
+	uc := NewTransactionUsecase(mockTransactionRepo, mockVoucherRepo, mockWaitlistRepo, mockPaymentShareRepo, fakeGateway,
+		helper.NewULIDOrderIDGenerator("ORDER-TEST-"), discount.NewGrulePolicy(), logger)
+
+	// gateway sudah siap me-refund share pertama, share kedua masih ditolak
+	refundable := model.PaymentShare{OrderID: "ORDER-1", ShareOrderID: "ORDER-1-S1", Amount: 50, Status: model.PaymentShareRefundFailed}
+	refused := model.PaymentShare{OrderID: "ORDER-2", ShareOrderID: "ORDER-2-S1", Amount: 50, Status: model.PaymentShareRefundFailed}
+	_, err := fakeGateway.CreateCharge(&snap.Request{TransactionDetails: midtrans.TransactionDetails{OrderID: refundable.ShareOrderID, GrossAmt: 50}})
+	assert.Nil(t, err)
+	assert.Nil(t, fakeGateway.SetStatus(refundable.ShareOrderID, "settlement", ""))
+
+	mockPaymentShareRepo.EXPECT().GetOverdueSplitOrderIDs(gomock.Any()).Return(nil, nil)
+	mockPaymentShareRepo.EXPECT().GetRefundFailedPaymentShares().Return([]model.PaymentShare{refundable, refused}, nil)
+	mockPaymentShareRepo.EXPECT().UpdatePaymentShareStatus(refundable.ShareOrderID, model.PaymentShareRefunded).Return(nil)
+
+	assert.Nil(t, uc.ExpireSplitTransactions())
+}
+
+func TestRefundPaymentShareFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPaymentShareRepo := mock.NewMockPaymentSharePersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+	logger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+
+	uc := &transactionUsecase{paymentShareRepo: mockPaymentShareRepo, paymentGateway: gateway.NewFakeGateway(nil), logger: logger}
+
+	share := model.PaymentShare{OrderID: "ORDER-1", ShareOrderID: "ORDER-1-S1", Amount: 50, Status: model.PaymentSharePaid}
+	mockPaymentShareRepo.EXPECT().UpdatePaymentShareStatus(share.ShareOrderID, model.PaymentShareRefundFailed).Return(nil)
+
+	uc.refundPaymentShare(share, "order was cancelled")
+}
@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/helper"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/repository"
+	"go.uber.org/zap"
+)
+
+// sendTicketEmails publishes the EmailPDFMessage of every recipient of a settled order to SQS_MAIL_URL
+func sendTicketEmails(transactionRepo repository.TransactionPersister, issuedTicketRepo repository.IssuedTicketPersister,
+	logger config.Logger, transaction model.Transaction) error {
+	detailTransactions, err := transactionRepo.GetDetailTransactionByTransactionID(transaction.TransactionID)
+	if err != nil {
+		logger.Error("Error when getting detail transaction by transactionID", zap.Error(err))
+		return err
+	}
+	if len(detailTransactions) == 0 {
+		return errors.New("transaction has no detail")
+	}
+
+	issuedTickets, err := issuedTicketRepo.GetIssuedTicketsByOrderID(transaction.OrderID)
+	if err != nil {
+		logger.Error("Error when getting issued tickets", zap.Error(err))
+		return err
+	}
+	if issuedTickets, err = withQRPayload(logger, heldTickets(issuedTickets, transaction.Email, detailTransactions)); err != nil {
+		return err
+	}
+
+	ticketEvent, err := helper.GetTicketEventByTicketID(detailTransactions[0].TicketID)
+	if err != nil {
+		logger.Error("Error when getting ticket event by ticket ID", zap.Error(err))
+		return err
+	}
+
+	// pembeli dapat salinan lengkap, tiap holder dapat ticket miliknya sendiri
+	for _, emailPDF := range ticketEmails(transaction, detailTransactions, ticketEvent, issuedTickets) {
+		jsonString, err := json.Marshal(emailPDF)
+		if err != nil {
+			logger.Error("Error when proceesing message", zap.Error(err))
+			continue
+		}
+
+		if err = helper.ProduceMessageSqs(os.Getenv("SQS_MAIL_URL"), string(jsonString), "Send Email PDF Success"); err != nil {
+			logger.Error("Error when producing message", zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// heldTickets keeps the tickets still owned by the buyer or by the holder of their line, tickets
+// transferred to someone else are not sent again
+func heldTickets(tickets []model.IssuedTicket, buyerEmail string, detailTransactions []model.DetailTransaction) []model.IssuedTicket {
+	holderEmails := make(map[int]string)
+	for _, dt := range detailTransactions {
+		if dt.Holder != nil {
+			holderEmails[dt.DetailTransactionID] = dt.Holder.Email
+		}
+	}
+
+	held := make([]model.IssuedTicket, 0, len(tickets))
+	for _, ticket := range tickets {
+		holderEmail, gifted := holderEmails[ticket.DetailTransactionID]
+		if strings.EqualFold(ticket.OwnerEmail, buyerEmail) || (gifted && strings.EqualFold(ticket.OwnerEmail, holderEmail)) {
+			held = append(held, ticket)
+		}
+	}
+
+	return held
+}
+
+// ticketEmails builds the buyer's email with every line, plus one email per ticket holder with only
+// the lines and issued tickets gifted to that holder
+func ticketEmails(transaction model.Transaction, detailTransactions []model.DetailTransaction, ticketEvent model.TicketEvent,
+	issuedTickets []model.IssuedTicket) []model.EmailPDFMessage {
+	newEmail := func(email, customerName string) model.EmailPDFMessage {
+		return model.EmailPDFMessage{
+			Email:        email,
+			OrderId:      transaction.OrderID,
+			EventName:    ticketEvent.EventName,
+			EventDate:    ticketEvent.Date.Format("2006-01-02"),
+			EventTime:    ticketEvent.Date.Format("15:04:05"),
+			Venue:        ticketEvent.CountryPlace,
+			CustomerName: customerName,
+			PurchaseDate: transaction.CreatedAt.Format("2006-01-02 15:04:05"),
+		}
+	}
+
+	buyer := newEmail(transaction.Email, transaction.FullName)
+	buyer.Price = transaction.TotalAmount
+	buyer.NumberOfTicket = transaction.TotalTicket
+	buyer.Tickets = issuedTickets
+
+	ticketsByLine := make(map[int][]model.IssuedTicket)
+	for _, issued := range issuedTickets {
+		ticketsByLine[issued.DetailTransactionID] = append(ticketsByLine[issued.DetailTransactionID], issued)
+	}
+
+	var holderEmails []model.EmailPDFMessage
+	holderIndex := make(map[string]int)
+	for _, dt := range detailTransactions {
+		detailTicket := model.DetailTicket{
+			TicketType:  dt.TicketType,
+			TotalTicket: dt.Quantity,
+		}
+		if dt.Holder != nil {
+			detailTicket.HolderName = dt.Holder.FullName
+		}
+		buyer.DetailTickets = append(buyer.DetailTickets, detailTicket)
+
+		if dt.Holder == nil || strings.EqualFold(dt.Holder.Email, transaction.Email) {
+			continue
+		}
+		key := strings.ToLower(dt.Holder.Email)
+		i, ok := holderIndex[key]
+		if !ok {
+			i = len(holderEmails)
+			holderIndex[key] = i
+			holderEmails = append(holderEmails, newEmail(dt.Holder.Email, dt.Holder.FullName))
+		}
+		holderEmails[i].NumberOfTicket += dt.Quantity
+		holderEmails[i].DetailTickets = append(holderEmails[i].DetailTickets, detailTicket)
+		holderEmails[i].Tickets = append(holderEmails[i].Tickets, ticketsByLine[dt.DetailTransactionID]...)
+	}
+
+	return append([]model.EmailPDFMessage{buyer}, holderEmails...)
+}
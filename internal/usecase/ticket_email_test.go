@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTicketEmails(t *testing.T) {
+	holder := &model.TicketHolder{FullName: "Budi", Email: "budi@example.com"}
+	transaction := model.Transaction{
+		OrderID:     "ORDER-1",
+		FullName:    "Ani",
+		Email:       "ani@example.com",
+		TotalAmount: 150,
+		TotalTicket: 4,
+		CreatedAt:   time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC),
+	}
+	detailTransactions := []model.DetailTransaction{
+		{DetailTransactionID: 1, TicketType: "VIP", Quantity: 1},
+		{DetailTransactionID: 2, TicketType: "Regular", Quantity: 2, Holder: holder},
+		{TicketType: "VIP", Quantity: 1, Holder: &model.TicketHolder{FullName: "Budi", Email: "BUDI@example.com"}},
+		{TicketType: "Regular", Quantity: 1, Holder: &model.TicketHolder{FullName: "Ani", Email: "Ani@example.com"}},
+	}
+
+	issuedTickets := []model.IssuedTicket{
+		{DetailTransactionID: 1, UnitNumber: 1, Code: "AAAA"},
+		{DetailTransactionID: 2, UnitNumber: 1, Code: "BBBB"},
+		{DetailTransactionID: 2, UnitNumber: 2, Code: "CCCC"},
+	}
+
+	emails := ticketEmails(transaction, detailTransactions, model.TicketEvent{EventName: "Concert"}, issuedTickets)
+
+	assert.Len(t, emails, 2)
+	assert.Equal(t, "ani@example.com", emails[0].Email)
+	assert.Equal(t, 4, emails[0].NumberOfTicket)
+	assert.Len(t, emails[0].DetailTickets, 4)
+	assert.Equal(t, "Budi", emails[0].DetailTickets[1].HolderName)
+	assert.Len(t, emails[0].Tickets, 3)
+
+	assert.Equal(t, "budi@example.com", emails[1].Email)
+	assert.Equal(t, "Budi", emails[1].CustomerName)
+	assert.Equal(t, "ORDER-1", emails[1].OrderId)
+	assert.Equal(t, 3, emails[1].NumberOfTicket)
+	assert.Equal(t, []model.DetailTicket{
+		{TicketType: "Regular", TotalTicket: 2, HolderName: "Budi"},
+		{TicketType: "VIP", TotalTicket: 1, HolderName: "Budi"},
+	}, emails[1].DetailTickets)
+	assert.Equal(t, issuedTickets[1:], emails[1].Tickets)
+}
+
+func TestHeldTickets(t *testing.T) {
+	detailTransactions := []model.DetailTransaction{
+		{DetailTransactionID: 1, TicketType: "VIP", Quantity: 2},
+		{DetailTransactionID: 2, TicketType: "Regular", Quantity: 2,
+			Holder: &model.TicketHolder{FullName: "Budi", Email: "budi@example.com"}},
+	}
+	tickets := []model.IssuedTicket{
+		{DetailTransactionID: 1, UnitNumber: 1, Code: "AAAA", OwnerEmail: "ani@example.com"},
+		{DetailTransactionID: 1, UnitNumber: 2, Code: "BBBB", OwnerEmail: "citra@example.com"},
+		{DetailTransactionID: 2, UnitNumber: 1, Code: "CCCC", OwnerEmail: "Budi@example.com"},
+		{DetailTransactionID: 2, UnitNumber: 2, Code: "DDDD", OwnerEmail: "dodi@example.com"},
+	}
+
+	held := heldTickets(tickets, "ani@example.com", detailTransactions)
+
+	assert.Equal(t, []model.IssuedTicket{tickets[0], tickets[2]}, held)
+}
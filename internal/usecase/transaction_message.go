@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/helper"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/util"
+	"go.uber.org/zap"
+)
+
+// transactionMessage is the order confirmation notification-service emails, with the link to pay
+func transactionMessage(transaction model.Transaction, url string, shares []model.PaymentShareResponse) model.Message {
+	deadline := transaction.CreatedAt.Add(util.PaymentWindow())
+	if transaction.SplitDeadline != nil {
+		deadline = *transaction.SplitDeadline
+	}
+
+	return model.Message{
+		OrderID:      transaction.OrderID,
+		Email:        transaction.Email,
+		URL:          url,
+		Name:         transaction.FullName,
+		Date:         transaction.CreatedAt.Format("02 January 2006 15:04:05"),
+		DeadlineDate: deadline.Format("02 January 2006 15:04:05"),
+		Total:        transaction.TotalAmount,
+		Shares:       shares,
+	}
+}
+
+func publishTransactionMessage(logger config.Logger, message model.Message) {
+	jsonString, err := json.Marshal(message)
+	if err != nil {
+		logger.Error("Error when proceesing message", zap.Error(err))
+		return
+	}
+
+	if err = helper.ProduceMessageSqs(os.Getenv("SQS_TRANSACTION_URL"), string(jsonString), "Create Transaction"); err != nil {
+		logger.Error("Error when producing message", zap.Error(err))
+	}
+}
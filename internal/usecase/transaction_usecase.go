@@ -1,213 +1,293 @@
 package usecase
 
 import (
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/SyamSolution/transaction-service/config"
 	"github.com/SyamSolution/transaction-service/helper"
+	"github.com/SyamSolution/transaction-service/internal/discount"
+	"github.com/SyamSolution/transaction-service/internal/gateway"
 	"github.com/SyamSolution/transaction-service/internal/model"
 	"github.com/SyamSolution/transaction-service/internal/repository"
+	"github.com/SyamSolution/transaction-service/internal/util"
 	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/coreapi"
 	"github.com/midtrans/midtrans-go/snap"
 	"go.uber.org/zap"
 )
 
+const (
+	idrExchangeRate    = 16045
+	midtransTimeFormat = "2006-01-02 15:04:05 -0700"
+	orderIDAttempts    = 3
+	// midtrans menolak gross_amount 0, voucher selalu menyisakan minimal ini
+	minGrossAmount = 1
+)
+
 type transactionUsecase struct {
-	transactionRepo repository.TransactionPersister
-	logger          config.Logger
+	transactionRepo  repository.TransactionPersister
+	voucherRepo      repository.VoucherPersister
+	waitlistRepo     repository.WaitlistPersister
+	paymentShareRepo repository.PaymentSharePersister
+	paymentGateway   gateway.PaymentGateway
+	orderIDGenerator helper.OrderIDGenerator
+	discountPolicy   discount.DiscountPolicy
+	logger           config.Logger
 }
 
 type TransactionExecutor interface {
-	CreateTransaction(request model.TransactionRequest, user model.User) (*snap.Response, float32, float32, error)
+	CreateTransaction(request model.TransactionRequest, user model.User) (model.CreateTransactionResponse, error)
+	QuoteTransaction(request model.TransactionRequest, user model.User) (model.QuoteResponse, error)
 	GetTransactionByTransactionID(transactionID int, email string) (model.TransactionResponse, error)
 	GetTransactionByOrderID(orderID string) (model.TransactionResponse, error)
-	UpdateTransactionStatus(orderID, status, email string) error
+	GetOrder(orderID, email string) (model.TransactionResponse, error)
+	UpdateTransactionStatus(orderID, status string, event model.TransactionEvent) (bool, error)
+	CancelTransaction(orderID, email string) error
+	GetTransactionHistory(transactionID int, email string) ([]model.TransactionEvent, error)
+	GetPaymentStatus(orderID string) (*coreapi.TransactionStatusResponse, error)
 	GetListTransaction(request model.TransactionListRequest) ([]model.TransactionListResponse, error)
+	ExportTransactions(email, from, to string) ([]model.TransactionExport, error)
+	GetPaymentMethods() []model.PaymentMethod
+	ResendPaymentLink(transactionID int, email string) (model.PaymentLinkResponse, error)
+	RecordGatewayPayment(orderID string, status *coreapi.TransactionStatusResponse) error
+	ResolvePaymentLink(gatewayOrderID string) (string, bool, error)
+	UpdatePaymentShare(shareOrderID, transactionStatus, fraudStatus string, event model.TransactionEvent) (model.PaymentShareResult, error)
+	ExpireSplitTransactions() error
 }
 
-func NewTransactionUsecase(transactionRepo repository.TransactionPersister, logger config.Logger) TransactionExecutor {
-	return &transactionUsecase{transactionRepo: transactionRepo, logger: logger}
+func NewTransactionUsecase(transactionRepo repository.TransactionPersister, voucherRepo repository.VoucherPersister,
+	waitlistRepo repository.WaitlistPersister, paymentShareRepo repository.PaymentSharePersister, paymentGateway gateway.PaymentGateway, orderIDGenerator helper.OrderIDGenerator, discountPolicy discount.DiscountPolicy,
+	logger config.Logger) TransactionExecutor {
+	return &transactionUsecase{
+		transactionRepo:  transactionRepo,
+		voucherRepo:      voucherRepo,
+		waitlistRepo:     waitlistRepo,
+		paymentShareRepo: paymentShareRepo,
+		paymentGateway:   paymentGateway,
+		orderIDGenerator: orderIDGenerator,
+		discountPolicy:   discountPolicy,
+		logger:           logger,
+	}
 }
 
-func (uc *transactionUsecase) CreateTransaction(request model.TransactionRequest, user model.User) (*snap.Response, float32, float32, error) {
-	isEligible, err := helper.CheckEligible()
-	if err != nil {
-		uc.logger.Error("Error when hit grule service", zap.Error(err))
-		return nil, 0, 0, err
+func (uc *transactionUsecase) CreateTransaction(request model.TransactionRequest, user model.User) (model.CreateTransactionResponse, error) {
+	paymentMethod, ok := gateway.FindPaymentMethod(request.PaymentMethod)
+	if !ok {
+		return model.CreateTransactionResponse{}, ErrInvalidPaymentMethod
 	}
-
-	if !isEligible {
-		return nil, 0, 0, fmt.Errorf("not eligible")
+	if request.DirectCharge && !paymentMethod.DirectCharge {
+		return model.CreateTransactionResponse{}, ErrDirectChargeUnsupported
 	}
-
-	//hit check all stock ticket with same continent
-	tickets, err := helper.GetTicket(request.Continent)
-	if err != nil {
-		uc.logger.Error("Error when getting ticket by continent", zap.Error(err))
-		return nil, 0, 0, err
+	split := request.SplitCount > 1
+	if request.SplitCount < 0 || request.SplitCount > maxPaymentShares() {
+		return model.CreateTransactionResponse{}, fmt.Errorf("%w: split_count must be at most %d", ErrInvalidSplitCount, maxPaymentShares())
+	}
+	if split && request.DirectCharge {
+		return model.CreateTransactionResponse{}, ErrSplitDirectCharge
 	}
 
-	var totalAmount float32
-	var detailTransactions []model.DetailTransaction
-	for _, detail := range request.DetailTicket {
-		for _, t := range tickets {
-			if detail.TicketID == t.TicketID {
-				totalAmount = totalAmount + float32(detail.Quantity*t.Price)
-			}
-		}
-
-		detailTransaction := model.DetailTransaction{
-			TicketID:    detail.TicketID,
-			TicketType:  detail.TicketType,
-			CountryName: detail.CountryName,
-			City:        detail.City,
-			Quantity:    detail.Quantity,
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
-		}
-
-		message := model.MessageOrderTicket{
-			TicketID: detailTransaction.TicketID,
-			Order:    detailTransaction.Quantity,
-		}
-
-		//check stock
-		for _, t := range tickets {
-			if detail.TicketType == t.Type {
-				if detail.Quantity > t.Stock {
-					uc.logger.Error("Error stock is not enough", zap.Error(errors.New("error stock is not enough")))
-					return nil, 0, 0, errors.New("error stock is not enough")
-				}
-			}
-		}
-
-		jsonString, err := json.Marshal(message)
-		if err != nil {
-			fmt.Println("Error:", err)
-			uc.logger.Error("Error when proceesing message", zap.Error(err))
-		
-		}
-		
-		if err = helper.ProduceMessageSqs(os.Getenv("SQS_TICKET_URL"), string(jsonString), "Update Create Order Status"); err != nil {
-			uc.logger.Error("Error when producing message", zap.Error(err))
+	now := time.Now()
+	var quote *quoteClaims
+	if request.QuoteID != "" {
+		var err error
+		if quote, err = uc.verifyQuote(request, user.Email, now); err != nil {
+			return model.CreateTransactionResponse{}, err
 		}
+	}
 
-		detailTransactions = append(detailTransactions, detailTransaction)
+	pricing, err := uc.priceOrder(request, user, quote, now)
+	if err != nil {
+		return model.CreateTransactionResponse{}, err
+	}
+	if quote != nil && pricing.grossAmount != quote.GrossAmount {
+		return model.CreateTransactionResponse{}, ErrQuoteMismatch
+	}
+	if split && pricing.grossAmount < int64(request.SplitCount) {
+		return model.CreateTransactionResponse{}, fmt.Errorf("%w: order total is too small to split", ErrInvalidSplitCount)
 	}
+	detailTransactions := pricing.detailTransactions
+	voucher := pricing.voucher
 
-	orderID := fmt.Sprintf("ORDER-%s", helper.RandomOrderID(5))
+	orderID := uc.orderIDGenerator.NewOrderID()
 	transaction := model.Transaction{
 		UserID:          user.UserID,
 		OrderID:         orderID,
-		TransactionDate: time.Now(),
+		TransactionDate: now,
 		PaymentMethod:   request.PaymentMethod,
-		Continent:       request.Continent,
-		TotalAmount:     totalAmount,
-		TotalTicket:     request.TotalTicket,
+		Continent:       strings.Join(pricing.continents, ","),
+		TotalAmount:     pricing.totalAmount,
+		GrossAmount:     pricing.grossAmount,
+		TotalTicket:     pricing.totalTicket,
+		Discount:        pricing.discount,
+		DiscountTrace:   pricing.discountTrace,
+		VoucherCode:     voucher.Code,
+		VoucherDiscount: pricing.voucherAmount,
 		FullName:        user.FullName,
 		MobileNumber:    user.PhoneNumber,
 		Email:           user.Email,
 		PaymentStatus:   "pending",
-		CreatedAt:       time.Now(),
-		UpdatedAt:       time.Now(),
+		CreatedAt:       now,
+		UpdatedAt:       now,
 	}
+	paymentWindow := util.PaymentWindow()
+	if split {
+		// semua bagian harus lunas sebelum deadline, kalau tidak yang sudah bayar di-refund
+		paymentWindow = util.SplitPaymentWindow()
+		deadline := now.Add(paymentWindow)
+		transaction.SplitCount = request.SplitCount
+		transaction.SplitDeadline = &deadline
+	}
+
+	// semua validasi lolos, baru hold ticket di ticket-management-service
+	uc.holdTickets(detailTransactions)
 
-	// cek stock semua ticket group by continent yang sold out
-	stockTicket, err := helper.GetStockTicketGroupByContinent()
+	// order id dibuat ulang kalau bentrok dengan unique index
+	for attempt := 1; ; attempt++ {
+		err = uc.transactionRepo.CreateTransaction(transaction, detailTransactions)
+		if !errors.Is(err, repository.ErrDuplicateOrderID) || attempt == orderIDAttempts {
+			break
+		}
+		orderID = uc.orderIDGenerator.NewOrderID()
+		transaction.OrderID = orderID
+	}
 	if err != nil {
-		uc.logger.Error("Error when getting stock ticket group by continent", zap.Error(err))
-		return nil, 0, 0, err
+		uc.logger.Error("Error when creating transaction", zap.Error(err))
+		uc.releaseTickets(detailTransactions)
+		return model.CreateTransactionResponse{}, err
 	}
-	var continentSoldout []string
-	for _, st := range stockTicket {
-		if st.Stock == 0 {
-			continentSoldout = append(continentSoldout, st.Continent)
-		}
+
+	// hold waitlist milik user ini sudah terpakai
+	ticketIDs := make([]int, 0, len(detailTransactions))
+	for _, dt := range detailTransactions {
+		ticketIDs = append(ticketIDs, dt.TicketID)
+	}
+	if err := uc.waitlistRepo.ConsumeWaitlistHold(user.Email, ticketIDs, orderID, now); err != nil {
+		uc.logger.Error("Error when consuming waitlist hold", zap.Error(err))
 	}
 
-	if len(continentSoldout) > 0 {
-		// cek semua continent transaksi sebelumnya
-		continentLastTransaction, err := uc.transactionRepo.GetDistinctContinentTransaction(user.Email)
+	if transaction.VoucherCode != "" {
+		err = uc.voucherRepo.ReserveVoucher(model.VoucherRedemption{
+			VoucherID: voucher.VoucherID,
+			OrderID:   orderID,
+			Email:     user.Email,
+			Amount:    transaction.VoucherDiscount,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		})
 		if err != nil {
-			uc.logger.Error("Error when getting distinct continent transaction", zap.Error(err))
-			return nil, 0, 0, err
-		}
-
-		// cek continent dengan continent transaksi sekarang
-		// jika beda kasih discount 20%
-	outer:
-		for _, cs := range continentSoldout {
-			for _, continent := range continentLastTransaction {
-				if continent == cs && continent != request.Continent {
-					discountModel := model.Discount{
-						IsContinentSoldOut: true,
-						IsContinentDiff:    true,
-					}
-					discount, err := helper.CheckDiscount(discountModel)
-					if err != nil {
-						uc.logger.Error("Error when checking discount", zap.Error(err))
-						return nil, 0, 0, err
-					}
-					transaction.Discount = discount
-					transaction.TotalAmount = transaction.TotalAmount * ((100 - transaction.Discount) / 100)
-					break outer
-				}
+			uc.cancelUnpayableTransaction(orderID, user.Email, detailTransactions)
+			switch {
+			case errors.Is(err, repository.ErrVoucherExhausted):
+				return model.CreateTransactionResponse{}, ErrVoucherExhausted
+			case errors.Is(err, repository.ErrVoucherUserLimit):
+				return model.CreateTransactionResponse{}, ErrVoucherUserLimit
 			}
+			uc.logger.Error("Error when reserving voucher", zap.Error(err))
+			return model.CreateTransactionResponse{}, err
 		}
 	}
 
-	err = uc.transactionRepo.CreateTransaction(transaction, detailTransactions)
-	if err != nil {
-		uc.logger.Error("Error when creating transaction", zap.Error(err))
-		return nil, 0, 0, err
-	}
-
 	// request ke midtrans
-	var s = snap.Client{}
-	s.New(os.Getenv("MIDTRANS_SERVER_KEY"), midtrans.Sandbox)
+	itemDetails, grossAmount := pricing.itemDetails, pricing.grossAmount
+	transactionDetails := midtrans.TransactionDetails{
+		OrderID:  orderID,
+		GrossAmt: grossAmount,
+	}
+	customerDetail := &midtrans.CustomerDetails{
+		FName: user.FullName,
+		Email: user.Email,
+		Phone: user.PhoneNumber,
+	}
 
-	// TODO request api exchange to IDR
-	req := &snap.Request{
-		TransactionDetails: midtrans.TransactionDetails{
-			OrderID:  orderID,
-			GrossAmt: int64(transaction.TotalAmount) * 16045,
-		},
-		CustomerDetail: &midtrans.CustomerDetails{
-			FName: user.FullName,
-			Email: user.Email,
-			Phone: user.PhoneNumber,
-		},
+	response := model.CreateTransactionResponse{
+		OrderID:          orderID,
+		PaymentMethod:    paymentMethod.Code,
+		Discount:         transaction.Discount,
+		VoucherCode:      transaction.VoucherCode,
+		VoucherDiscount:  transaction.VoucherDiscount,
+		TotalTransaction: float32(grossAmount),
+		SplitDeadline:    transaction.SplitDeadline,
 	}
 
-	snapResp, _ := s.CreateTransaction(req)
+	if split {
+		shares, err := uc.createPaymentShares(transaction, grossAmount, paymentMethod, customerDetail, now)
+		if err != nil {
+			uc.failSplitTransaction(orderID, model.TransactionEvent{Actor: model.TransactionActorUser, ActorID: user.Email,
+				Note: "payment share could not be created"})
+			return model.CreateTransactionResponse{}, err
+		}
+		response.Shares = paymentShareResponses(shares)
+	} else if request.DirectCharge {
+		chargeReq, _ := gateway.NewChargeRequest(paymentMethod.Code, transactionDetails, customerDetail)
+		chargeReq.Items = &itemDetails
+		chargeReq.CustomExpiry = &coreapi.CustomExpiry{
+			OrderTime:      now.Format(midtransTimeFormat),
+			ExpiryDuration: int(paymentWindow.Minutes()),
+			Unit:           "minute",
+		}
+		chargeResp, err := uc.paymentGateway.DirectCharge(chargeReq)
+		if err != nil {
+			uc.logger.Error("Error when charging payment", zap.Error(err))
+			uc.cancelUnpayableTransaction(orderID, user.Email, detailTransactions)
+			return model.CreateTransactionResponse{}, err
+		}
 
-	// message ke notification-service untuk send email
-	message := model.Message{
-		OrderID:      orderID,
-		Email:        user.Email,
-		URL:          snapResp.RedirectURL,
-		Name:         user.FullName,
-		Date:         time.Now().Format("02 January 2006 15:04:05"),
-		DeadlineDate: time.Now().AddDate(0, 0, 1).Format("02 January 2006 15:04:05"),
-		Total:        transaction.TotalAmount,
-	}
+		if len(chargeResp.VaNumbers) > 0 {
+			response.VANumber = chargeResp.VaNumbers[0].VANumber
+		} else {
+			response.VANumber = chargeResp.PermataVaNumber
+		}
+		response.QRString = chargeResp.QRString
+
+		// charge sudah terbuat di midtrans, cukup di-log kalau gagal simpan
+		expiresAt := now.Add(paymentWindow)
+		uc.savePayment(model.Payment{OrderID: orderID, VANumber: response.VANumber, QRString: response.QRString,
+			GatewayTransactionID: chargeResp.TransactionID, ExpiresAt: &expiresAt})
+	} else {
+		req := &snap.Request{
+			TransactionDetails: transactionDetails,
+			Items:              &itemDetails,
+			CustomerDetail:     customerDetail,
+			EnabledPayments:    gateway.EnabledPayments(paymentMethod.Code),
+			Expiry: &snap.ExpiryDetails{
+				StartTime: now.Format(midtransTimeFormat),
+				Unit:      "minute",
+				Duration:  int64(paymentWindow.Minutes()),
+			},
+		}
+		if finishURL := os.Getenv("MIDTRANS_FINISH_URL"); finishURL != "" {
+			req.Callbacks = &snap.Callbacks{Finish: finishURL}
+			req.Gopay = &snap.GopayDetails{EnableCallback: true, CallbackUrl: finishURL}
+			req.ShopeePay = &snap.ShopeePayDetails{CallbackUrl: finishURL}
+		}
 
-	jsonString, err := json.Marshal(message)
-	if err != nil {
-		fmt.Println("Error:", err)
-		uc.logger.Error("Error when proceesing message", zap.Error(err))
-	
-	}
-	
-	if err = helper.ProduceMessageSqs(os.Getenv("SQS_TRANSACTION_URL"), string(jsonString), "Create Transaction"); err != nil {
-		uc.logger.Error("Error when producing message", zap.Error(err))
+		snapResp, err := uc.paymentGateway.CreateCharge(req)
+		if err != nil {
+			uc.logger.Error("Error when creating payment", zap.Error(err))
+			uc.cancelUnpayableTransaction(orderID, user.Email, detailTransactions)
+			return model.CreateTransactionResponse{}, err
+		}
+		response.Token = snapResp.Token
+		response.RedirectURL = snapResp.RedirectURL
+
+		// disimpan supaya link bisa dikirim ulang
+		expiresAt := now.Add(paymentWindow)
+		uc.savePayment(model.Payment{OrderID: orderID, Token: snapResp.Token, RedirectURL: snapResp.RedirectURL, ExpiresAt: &expiresAt})
 	}
 
-	return snapResp, transaction.Discount, transaction.TotalAmount * 16045, nil
+	// message ke notification-service untuk send email
+	publishTransactionMessage(uc.logger, transactionMessage(transaction, response.RedirectURL, response.Shares))
+
+	return response, nil
 }
 
 func (uc *transactionUsecase) GetTransactionByTransactionID(transactionID int, email string) (model.TransactionResponse, error) {
@@ -217,27 +297,70 @@ func (uc *transactionUsecase) GetTransactionByTransactionID(transactionID int, e
 		return model.TransactionResponse{}, err
 	}
 
+	return uc.customerTransactionResponse(transaction)
+}
+
+// GetOrder is the order the customer knows from emails and midtrans redirects, someone else's order is not found
+func (uc *transactionUsecase) GetOrder(orderID, email string) (model.TransactionResponse, error) {
+	transaction, err := uc.transactionRepo.GetTransactionByOrderID(orderID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.TransactionResponse{}, ErrTransactionNotFound
+		}
+		return model.TransactionResponse{}, err
+	}
+	if !strings.EqualFold(transaction.Email, email) {
+		return model.TransactionResponse{}, ErrTransactionNotFound
+	}
+
+	return uc.customerTransactionResponse(transaction)
+}
+
+// customerTransactionResponse is the order as shown to its buyer, lines carry the event they are for
+func (uc *transactionUsecase) customerTransactionResponse(transaction model.Transaction) (model.TransactionResponse, error) {
+	transactionID := transaction.TransactionID
 	detailTransactions, err := uc.transactionRepo.GetDetailTransactionByTransactionID(transactionID)
 	if err != nil {
 		uc.logger.Error("Error when getting detail transaction by transactionID", zap.Error(err))
 		return model.TransactionResponse{}, err
 	}
 
+	ticketIDs := make([]int, 0, len(detailTransactions))
+	for _, detail := range detailTransactions {
+		ticketIDs = append(ticketIDs, detail.TicketID)
+	}
+	// tanpa data event detail tetap ditampilkan
+	ticketEvents, err := helper.GetTicketEventsByTicketIDs(ticketIDs)
+	if err != nil {
+		uc.logger.Error("Error when getting ticket events", zap.Error(err))
+	}
+
 	var detailTransactionResponses []model.DetailTransactionResponse
 	for _, detail := range detailTransactions {
 		detailTransactionResponse := model.DetailTransactionResponse{
 			DetailTransactionID: detail.DetailTransactionID,
 			TicketID:            detail.TicketID,
 			TicketType:          detail.TicketType,
+			Continent:           detail.Continent,
 			CountryName:         detail.CountryName,
 			City:                detail.City,
 			Quantity:            detail.Quantity,
+			Holder:              detail.Holder,
+		}
+		if ticketEvent, ok := ticketEvents[detail.TicketID]; ok {
+			detailTransactionResponse.EventName = ticketEvent.EventName
+			detailTransactionResponse.Venue = ticketEvent.CountryPlace
+			if !ticketEvent.Date.IsZero() {
+				eventDate := ticketEvent.Date
+				detailTransactionResponse.EventDate = &eventDate
+			}
 		}
 		detailTransactionResponses = append(detailTransactionResponses, detailTransactionResponse)
 	}
 
 	transactionResponse := model.TransactionResponse{
 		TransactionID:             transaction.TransactionID,
+		OrderID:                   transaction.OrderID,
 		TransactionDate:           transaction.TransactionDate,
 		PaymentMethod:             transaction.PaymentMethod,
 		TotalAmount:               transaction.TotalAmount,
@@ -247,6 +370,24 @@ func (uc *transactionUsecase) GetTransactionByTransactionID(transactionID int, e
 		Continent:                 transaction.Continent,
 	}
 
+	if transaction.SplitCount > 0 {
+		shares, err := uc.paymentShareRepo.GetPaymentSharesByOrderID(transaction.OrderID)
+		if err != nil {
+			uc.logger.Error("Error when getting payment shares", zap.Error(err))
+			return model.TransactionResponse{}, err
+		}
+		transactionResponse.PaymentShares = paymentShareResponses(shares)
+	} else if transaction.PaymentStatus == "pending" {
+		// selama belum lunas user bisa lanjut bayar dari detail transaksi
+		payment, err := uc.transactionRepo.GetPaymentByOrderID(transaction.OrderID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return model.TransactionResponse{}, err
+		}
+		if err == nil {
+			transactionResponse.Payment = paymentResponse(payment)
+		}
+	}
+
 	return transactionResponse, nil
 }
 
@@ -269,9 +410,11 @@ func (uc *transactionUsecase) GetTransactionByOrderID(orderID string) (model.Tra
 			DetailTransactionID: detail.DetailTransactionID,
 			TicketID:            detail.TicketID,
 			TicketType:          detail.TicketType,
+			Continent:           detail.Continent,
 			CountryName:         detail.CountryName,
 			City:                detail.City,
 			Quantity:            detail.Quantity,
+			Holder:              detail.Holder,
 		}
 		detailTransactionResponses = append(detailTransactionResponses, detailTransactionResponse)
 	}
@@ -317,12 +460,284 @@ func (uc *transactionUsecase) GetListTransaction(request model.TransactionListRe
 	return transactionListResponses, nil
 }
 
-func (uc *transactionUsecase) UpdateTransactionStatus(orderID, status, email string) error {
-	err := uc.transactionRepo.UpdateTransactionStatus(orderID, status)
+// ExportTransactions returns the user's orders between from and to inclusive with their lines
+func (uc *transactionUsecase) ExportTransactions(email, from, to string) ([]model.TransactionExport, error) {
+	start, end, ok := parseDateRange(from, to)
+	if !ok {
+		return nil, ErrExportRange
+	}
+
+	exports, err := uc.transactionRepo.GetTransactionExport(email, start, end.AddDate(0, 0, 1))
+	if err != nil {
+		uc.logger.Error("Error when getting transaction export", zap.Error(err))
+		return nil, err
+	}
+
+	for i := range exports {
+		// order lama belum menyimpan gross amount, hitung dengan kurs yang sama saat charge
+		if exports[i].TotalIDR == 0 {
+			exports[i].TotalIDR = int64(math.Round(float64(exports[i].TotalAmount) * idrExchangeRate))
+		}
+	}
+
+	return exports, nil
+}
+
+// UpdateTransactionStatus tells whether the status changed, the voucher and waitlist only follow a real change
+func (uc *transactionUsecase) UpdateTransactionStatus(orderID, status string, event model.TransactionEvent) (bool, error) {
+	changed, err := uc.transactionRepo.UpdateTransactionStatus(orderID, status, event)
 	if err != nil {
 		uc.logger.Error("Error when updating transaction status", zap.Error(err))
+		return false, err
+	}
+	if !changed {
+		return false, nil
+	}
+
+	switch status {
+	case "completed":
+		if err = uc.voucherRepo.RedeemVoucher(orderID); err != nil {
+			uc.logger.Error("Error when redeeming voucher", zap.Error(err))
+		}
+	case "cancelled":
+		uc.releaseVoucher(orderID)
+
+		// ticket sudah dikembalikan oleh webhook, tawarkan ke waitlist
+		transaction, err := uc.transactionRepo.GetTransactionByOrderID(orderID)
+		if err != nil {
+			uc.logger.Error("Error when getting transaction by orderID", zap.Error(err))
+			return true, nil
+		}
+		detailTransactions, err := uc.transactionRepo.GetDetailTransactionByTransactionID(transaction.TransactionID)
+		if err != nil {
+			uc.logger.Error("Error when getting detail transaction by transactionID", zap.Error(err))
+			return true, nil
+		}
+		uc.promoteWaitlist(detailTransactions)
+	}
+
+	return true, nil
+}
+
+func (uc *transactionUsecase) GetTransactionHistory(transactionID int, email string) ([]model.TransactionEvent, error) {
+	if _, err := uc.transactionRepo.GetTransactionByTransactionID(transactionID, email); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTransactionNotFound
+		}
+		uc.logger.Error("Error when getting transaction by transactionID", zap.Error(err))
+		return nil, err
+	}
+
+	events, err := uc.transactionRepo.GetTransactionEvents(transactionID)
+	if err != nil {
+		uc.logger.Error("Error when getting transaction events", zap.Error(err))
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (uc *transactionUsecase) CancelTransaction(orderID, email string) error {
+	transaction, err := uc.transactionRepo.GetTransactionByOrderID(orderID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrTransactionNotFound
+		}
+		uc.logger.Error("Error when getting transaction by orderID", zap.Error(err))
 		return err
 	}
 
+	// jangan bocorkan order milik user lain
+	if transaction.Email != email {
+		return ErrTransactionNotFound
+	}
+
+	if transaction.PaymentStatus != "pending" {
+		return ErrTransactionNotPending
+	}
+
+	// split order tidak punya charge sendiri di midtrans, batalkan per bagian
+	if transaction.SplitCount > 0 {
+		return uc.failSplitTransaction(orderID, model.TransactionEvent{Actor: model.TransactionActorUser, ActorID: email,
+			Note: "cancelled by customer"})
+	}
+
+	paymentOrderID := gatewayOrderID(uc.transactionRepo, uc.logger, orderID)
+	if _, err := uc.paymentGateway.Cancel(paymentOrderID); err != nil {
+		switch gateway.StatusCode(err) {
+		case http.StatusNotFound:
+			// user belum memilih metode pembayaran di snap, cukup cancel di sisi kita
+		case http.StatusPreconditionFailed:
+			// midtrans menolak perubahan status, cek apakah sudah dibayar
+			statusResp, err := uc.paymentGateway.CheckStatus(paymentOrderID)
+			if err != nil {
+				uc.logger.Error("Error when checking transaction status", zap.Error(err))
+				return err
+			}
+			switch statusResp.TransactionStatus {
+			case "settlement", "capture":
+				return ErrTransactionAlreadyPaid
+			case "cancel", "expire", "deny":
+			default:
+				return ErrTransactionNotPending
+			}
+		default:
+			uc.logger.Error("Error when cancelling transaction on midtrans", zap.Error(err))
+			return err
+		}
+	}
+
+	cancelled, err := uc.transactionRepo.CancelTransaction(orderID, model.TransactionEvent{Actor: model.TransactionActorUser, ActorID: email,
+		Note: "cancelled by customer"})
+	if err != nil {
+		uc.logger.Error("Error when cancelling transaction", zap.Error(err))
+		return err
+	}
+	if !cancelled {
+		// status sudah diubah webhook di antara pengecekan dan update
+		return ErrTransactionNotPending
+	}
+
+	detailTransactions, err := uc.transactionRepo.GetDetailTransactionByTransactionID(transaction.TransactionID)
+	if err != nil {
+		uc.logger.Error("Error when getting detail transaction by transactionID", zap.Error(err))
+		return err
+	}
+
+	uc.releaseVoucher(orderID)
+	uc.releaseTickets(detailTransactions)
+	uc.promoteWaitlist(detailTransactions)
+
 	return nil
 }
+
+func (uc *transactionUsecase) GetPaymentStatus(orderID string) (*coreapi.TransactionStatusResponse, error) {
+	statusResp, err := uc.paymentGateway.CheckStatus(orderID)
+	if err != nil {
+		uc.logger.Error("Error when checking transaction status", zap.Error(err))
+		return nil, err
+	}
+
+	return statusResp, nil
+}
+
+// buildItemDetails prices every line in IDR for midtrans. The discount and voucher go in as negative
+// lines so the items always add up to the returned gross amount, otherwise midtrans rejects the order.
+func buildItemDetails(detailTransactions []model.DetailTransaction, ticketPrices map[int]int, discount float32,
+	voucherCode string, voucherAmount float32) ([]midtrans.ItemDetails, int64) {
+	var itemDetails []midtrans.ItemDetails
+	var subtotal int64
+	for _, dt := range detailTransactions {
+		price := int64(ticketPrices[dt.TicketID]) * idrExchangeRate
+		name := fmt.Sprintf("%s Ticket - %s", dt.TicketType, dt.City)
+		if len(name) > 50 {
+			name = name[:50]
+		}
+		itemDetails = append(itemDetails, midtrans.ItemDetails{
+			ID:       strconv.Itoa(dt.TicketID),
+			Name:     name,
+			Price:    price,
+			Qty:      int32(dt.Quantity),
+			Category: "ticket",
+		})
+		subtotal += price * int64(dt.Quantity)
+	}
+
+	grossAmount := subtotal
+	if discount > 0 {
+		grossAmount = int64(math.Round(float64(subtotal) * float64(100-discount) / 100))
+		itemDetails = append(itemDetails, midtrans.ItemDetails{
+			ID:    "DISCOUNT",
+			Name:  fmt.Sprintf("Discount %g%%", discount),
+			Price: grossAmount - subtotal,
+			Qty:   1,
+		})
+	}
+
+	if voucherAmount > 0 {
+		voucherIDR := int64(math.Round(float64(voucherAmount) * idrExchangeRate))
+		if voucherIDR > grossAmount-minGrossAmount {
+			voucherIDR = max(grossAmount-minGrossAmount, 0)
+		}
+		grossAmount -= voucherIDR
+		itemDetails = append(itemDetails, midtrans.ItemDetails{
+			ID:    "VOUCHER",
+			Name:  "Voucher " + voucherCode,
+			Price: -voucherIDR,
+			Qty:   1,
+		})
+	}
+
+	return itemDetails, grossAmount
+}
+
+func (uc *transactionUsecase) GetPaymentMethods() []model.PaymentMethod {
+	return gateway.PaymentMethods()
+}
+
+// order tidak bisa dibayar, batalkan dan balikin ticket
+func (uc *transactionUsecase) cancelUnpayableTransaction(orderID, email string, detailTransactions []model.DetailTransaction) {
+	event := model.TransactionEvent{Actor: model.TransactionActorUser, ActorID: email, Note: "payment could not be created"}
+	if _, err := uc.transactionRepo.CancelTransaction(orderID, event); err != nil {
+		uc.logger.Error("Error when cancelling transaction", zap.Error(err))
+	}
+	uc.releaseVoucher(orderID)
+	uc.releaseTickets(detailTransactions)
+	uc.promoteWaitlist(detailTransactions)
+}
+
+func (uc *transactionUsecase) releaseVoucher(orderID string) {
+	if err := uc.voucherRepo.ReleaseVoucher(orderID); err != nil {
+		uc.logger.Error("Error when releasing voucher", zap.Error(err))
+	}
+}
+
+// kirim message SQS ke ticket-management-service untuk hold ticket
+func (uc *transactionUsecase) holdTickets(detailTransactions []model.DetailTransaction) {
+	for _, dt := range detailTransactions {
+		message := model.MessageOrderTicket{
+			TicketID: dt.TicketID,
+			Order:    dt.Quantity,
+		}
+
+		jsonString, err := json.Marshal(message)
+		if err != nil {
+			uc.logger.Error("Error when proceesing message", zap.Error(err))
+			continue
+		}
+
+		if err = helper.ProduceMessageSqs(os.Getenv("SQS_TICKET_URL"), string(jsonString), "Update Create Order Status"); err != nil {
+			uc.logger.Error("Error when producing message", zap.Error(err))
+		}
+	}
+}
+
+// kirim message SQS ke ticket-management-service balikin ticket
+func (uc *transactionUsecase) releaseTickets(detailTransactions []model.DetailTransaction) {
+	for _, dt := range detailTransactions {
+		message := model.MessageOrderTicket{
+			TicketID: dt.TicketID,
+			Order:    dt.Quantity,
+		}
+
+		jsonString, err := json.Marshal(message)
+		if err != nil {
+			uc.logger.Error("Error when proceesing message", zap.Error(err))
+			continue
+		}
+
+		if err = helper.ProduceMessageSqs(os.Getenv("SQS_TICKET_FAILED_URL"), string(jsonString), "Update Ticket Failed"); err != nil {
+			uc.logger.Error("Error when producing message", zap.Error(err))
+		}
+	}
+}
+
+// tawarkan ticket yang dikembalikan ke waitlist paling lama
+func (uc *transactionUsecase) promoteWaitlist(detailTransactions []model.DetailTransaction) {
+	released := make(map[int]int)
+	for _, dt := range detailTransactions {
+		released[dt.TicketID] += dt.Quantity
+	}
+
+	promoteWaitlist(uc.waitlistRepo, uc.logger, released)
+}
@@ -1,27 +1,59 @@
 package usecase
 
 import (
+	"database/sql"
+	"encoding/json"
+	"github.com/SyamSolution/transaction-service/helper"
+	"github.com/SyamSolution/transaction-service/internal/discount"
+	"github.com/SyamSolution/transaction-service/internal/gateway"
 	"github.com/SyamSolution/transaction-service/internal/model"
 	mock "github.com/SyamSolution/transaction-service/mock"
 	mock_config "github.com/SyamSolution/transaction-service/mock/config"
+	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/snap"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
 	"testing"
+	"time"
 )
 
+// ticketServiceStub answers the ticket event lookups of ticket-management-service
+func ticketServiceStub(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ticketID, _ := strconv.Atoi(path.Base(r.URL.Path))
+		_ = json.NewEncoder(w).Encode(model.ResponseTicketEvent{Data: model.TicketEvent{
+			TicketID:     ticketID,
+			EventName:    "Event " + strconv.Itoa(ticketID),
+			CountryPlace: "Gelora Bung Karno",
+			Date:         time.Date(2026, 12, 1, 19, 0, 0, 0, time.UTC),
+		}})
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("TICKET_MANAGEMENT_SERVICE_URL", server.URL)
+}
+
 func TestGetTransactionByTransactionID(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockTransactionRepo := mock.NewMockTransactionPersister(ctrl)
+	mockVoucherRepo := mock.NewMockVoucherPersister(ctrl)
+	mockWaitlistRepo := mock.NewMockWaitlistPersister(ctrl)
+	mockPaymentShareRepo := mock.NewMockPaymentSharePersister(ctrl)
 	logger := mock_config.NewMockLogger(ctrl)
 
-	uc := NewTransactionUsecase(mockTransactionRepo, logger)
+	uc := NewTransactionUsecase(mockTransactionRepo, mockVoucherRepo, mockWaitlistRepo, mockPaymentShareRepo, gateway.NewFakeGateway(nil), helper.NewULIDOrderIDGenerator("ORDER-TEST-"), discount.NewGrulePolicy(), logger)
+
+	ticketServiceStub(t)
 
 	transactionID := 1
 	email := "test@example.com"
 
-	mockTransaction := model.Transaction{}
+	mockTransaction := model.Transaction{TransactionID: transactionID}
 	mockDetailTransactions := []model.DetailTransaction{{}}
 
 	mockTransactionRepo.EXPECT().GetTransactionByTransactionID(transactionID, email).Return(mockTransaction, nil)
@@ -33,14 +65,64 @@ func TestGetTransactionByTransactionID(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestGetOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionRepo := mock.NewMockTransactionPersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+
+	uc := NewTransactionUsecase(mockTransactionRepo, nil, nil, nil, gateway.NewFakeGateway(nil), helper.NewULIDOrderIDGenerator("ORDER-TEST-"), discount.NewGrulePolicy(), logger)
+
+	ticketServiceStub(t)
+
+	email := "test@example.com"
+	mockTransaction := model.Transaction{TransactionID: 1, OrderID: "ORDER-1", Email: email, PaymentStatus: "completed"}
+
+	t.Run("owner", func(t *testing.T) {
+		mockTransactionRepo.EXPECT().GetTransactionByOrderID("ORDER-1").Return(mockTransaction, nil)
+		mockTransactionRepo.EXPECT().GetDetailTransactionByTransactionID(1).
+			Return([]model.DetailTransaction{{TicketID: 7, Quantity: 1}, {TicketID: 7, Quantity: 2}, {TicketID: 8, Quantity: 1}}, nil)
+
+		// email dari token bisa beda huruf besar kecil dengan yang tersimpan
+		transactionResponse, err := uc.GetOrder("ORDER-1", "Test@Example.com")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "ORDER-1", transactionResponse.OrderID)
+		if assert.Len(t, transactionResponse.DetailTransactionResponse, 3) {
+			line := transactionResponse.DetailTransactionResponse[2]
+			assert.Equal(t, "Event 8", line.EventName)
+			assert.Equal(t, "Gelora Bung Karno", line.Venue)
+			assert.NotNil(t, line.EventDate)
+		}
+	})
+
+	t.Run("someone else's order", func(t *testing.T) {
+		mockTransactionRepo.EXPECT().GetTransactionByOrderID("ORDER-1").Return(mockTransaction, nil)
+
+		_, err := uc.GetOrder("ORDER-1", "other@example.com")
+		assert.ErrorIs(t, err, ErrTransactionNotFound)
+	})
+
+	t.Run("unknown order", func(t *testing.T) {
+		mockTransactionRepo.EXPECT().GetTransactionByOrderID("ORDER-2").Return(model.Transaction{}, sql.ErrNoRows)
+
+		_, err := uc.GetOrder("ORDER-2", email)
+		assert.ErrorIs(t, err, ErrTransactionNotFound)
+	})
+}
+
 func TestGetTransactionByOrderID(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockTransactionRepo := mock.NewMockTransactionPersister(ctrl)
+	mockVoucherRepo := mock.NewMockVoucherPersister(ctrl)
+	mockWaitlistRepo := mock.NewMockWaitlistPersister(ctrl)
+	mockPaymentShareRepo := mock.NewMockPaymentSharePersister(ctrl)
 	logger := mock_config.NewMockLogger(ctrl)
 
-	uc := NewTransactionUsecase(mockTransactionRepo, logger)
+	uc := NewTransactionUsecase(mockTransactionRepo, mockVoucherRepo, mockWaitlistRepo, mockPaymentShareRepo, gateway.NewFakeGateway(nil), helper.NewULIDOrderIDGenerator("ORDER-TEST-"), discount.NewGrulePolicy(), logger)
 
 	orderID := "testOrderID"
 
@@ -61,9 +143,12 @@ func TestGetListTransaction(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockTransactionRepo := mock.NewMockTransactionPersister(ctrl)
+	mockVoucherRepo := mock.NewMockVoucherPersister(ctrl)
+	mockWaitlistRepo := mock.NewMockWaitlistPersister(ctrl)
+	mockPaymentShareRepo := mock.NewMockPaymentSharePersister(ctrl)
 	logger := mock_config.NewMockLogger(ctrl)
 
-	uc := NewTransactionUsecase(mockTransactionRepo, logger)
+	uc := NewTransactionUsecase(mockTransactionRepo, mockVoucherRepo, mockWaitlistRepo, mockPaymentShareRepo, gateway.NewFakeGateway(nil), helper.NewULIDOrderIDGenerator("ORDER-TEST-"), discount.NewGrulePolicy(), logger)
 
 	request := model.TransactionListRequest{}
 
@@ -77,22 +162,331 @@ func TestGetListTransaction(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestExportTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionRepo := mock.NewMockTransactionPersister(ctrl)
+	mockVoucherRepo := mock.NewMockVoucherPersister(ctrl)
+	mockWaitlistRepo := mock.NewMockWaitlistPersister(ctrl)
+	mockPaymentShareRepo := mock.NewMockPaymentSharePersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+
+	uc := NewTransactionUsecase(mockTransactionRepo, mockVoucherRepo, mockWaitlistRepo, mockPaymentShareRepo, gateway.NewFakeGateway(nil), helper.NewULIDOrderIDGenerator("ORDER-TEST-"), discount.NewGrulePolicy(), logger)
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(2026, 11, 1, 0, 0, 0, 0, time.Local)
+	mockTransactionRepo.EXPECT().GetTransactionExport("test@example.com", from, to).Return([]model.TransactionExport{
+		{OrderID: "ORDER-1", TotalAmount: 180, TotalIDR: 2888000},
+		{OrderID: "ORDER-2", TotalAmount: 50},
+	}, nil)
+
+	exports, err := uc.ExportTransactions("test@example.com", "2026-10-01", "2026-10-31")
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2888000), exports[0].TotalIDR)
+	assert.Equal(t, int64(50*idrExchangeRate), exports[1].TotalIDR)
+
+	_, err = uc.ExportTransactions("test@example.com", "2026-10-31", "2026-10-01")
+	assert.ErrorIs(t, err, ErrExportRange)
+}
+
 func TestUpdateTransactionStatus(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockTransactionRepo := mock.NewMockTransactionPersister(ctrl)
+	mockVoucherRepo := mock.NewMockVoucherPersister(ctrl)
+	mockWaitlistRepo := mock.NewMockWaitlistPersister(ctrl)
+	mockPaymentShareRepo := mock.NewMockPaymentSharePersister(ctrl)
 	logger := mock_config.NewMockLogger(ctrl)
 
-	uc := NewTransactionUsecase(mockTransactionRepo, logger)
+	uc := NewTransactionUsecase(mockTransactionRepo, mockVoucherRepo, mockWaitlistRepo, mockPaymentShareRepo, gateway.NewFakeGateway(nil), helper.NewULIDOrderIDGenerator("ORDER-TEST-"), discount.NewGrulePolicy(), logger)
 
 	orderID := "testOrderID"
 	status := "testStatus"
-	email := "test@example.com"
+	event := model.TransactionEvent{Actor: model.TransactionActorWebhook, PayloadReference: "midtrans-1"}
 
-	mockTransactionRepo.EXPECT().UpdateTransactionStatus(orderID, status).Return(nil)
+	mockTransactionRepo.EXPECT().UpdateTransactionStatus(orderID, status, event).Return(true, nil)
 
-	err := uc.UpdateTransactionStatus(orderID, status, email)
+	changed, err := uc.UpdateTransactionStatus(orderID, status, event)
 
 	assert.Nil(t, err)
+	assert.True(t, changed)
+
+	t.Run("completed redeems the voucher", func(t *testing.T) {
+		mockTransactionRepo.EXPECT().UpdateTransactionStatus(orderID, "completed", event).Return(true, nil)
+		mockVoucherRepo.EXPECT().RedeemVoucher(orderID).Return(nil)
+
+		_, err := uc.UpdateTransactionStatus(orderID, "completed", event)
+		assert.Nil(t, err)
+	})
+
+	t.Run("same status again does nothing", func(t *testing.T) {
+		mockTransactionRepo.EXPECT().UpdateTransactionStatus(orderID, "cancelled", event).Return(false, nil)
+
+		changed, err := uc.UpdateTransactionStatus(orderID, "cancelled", event)
+		assert.Nil(t, err)
+		assert.False(t, changed)
+	})
+
+	t.Run("cancelled releases the voucher and promotes the waitlist", func(t *testing.T) {
+		mockTransactionRepo.EXPECT().UpdateTransactionStatus(orderID, "cancelled", event).Return(true, nil)
+		mockVoucherRepo.EXPECT().ReleaseVoucher(orderID).Return(nil)
+		mockTransactionRepo.EXPECT().GetTransactionByOrderID(orderID).Return(model.Transaction{TransactionID: 1, OrderID: orderID}, nil)
+		mockTransactionRepo.EXPECT().GetDetailTransactionByTransactionID(1).Return([]model.DetailTransaction{
+			{TicketID: 7, Quantity: 2},
+			{TicketID: 7, Quantity: 1},
+		}, nil)
+		mockWaitlistRepo.EXPECT().PromoteWaitlist(7, 3, gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		_, err := uc.UpdateTransactionStatus(orderID, "cancelled", event)
+		assert.Nil(t, err)
+	})
+}
+
+func TestGetTransactionHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionRepo := mock.NewMockTransactionPersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+
+	uc := NewTransactionUsecase(mockTransactionRepo, nil, nil, nil, gateway.NewFakeGateway(nil), helper.NewULIDOrderIDGenerator("ORDER-TEST-"), discount.NewGrulePolicy(), logger)
+
+	t.Run("own transaction", func(t *testing.T) {
+		events := []model.TransactionEvent{{TransactionID: 1, Actor: model.TransactionActorUser, NewStatus: "pending"}}
+		mockTransactionRepo.EXPECT().GetTransactionByTransactionID(1, "test@example.com").Return(model.Transaction{TransactionID: 1}, nil)
+		mockTransactionRepo.EXPECT().GetTransactionEvents(1).Return(events, nil)
+
+		result, err := uc.GetTransactionHistory(1, "test@example.com")
+
+		assert.Nil(t, err)
+		assert.Equal(t, events, result)
+	})
+
+	t.Run("other user's transaction", func(t *testing.T) {
+		mockTransactionRepo.EXPECT().GetTransactionByTransactionID(1, "other@example.com").Return(model.Transaction{}, sql.ErrNoRows)
+
+		_, err := uc.GetTransactionHistory(1, "other@example.com")
+
+		assert.ErrorIs(t, err, ErrTransactionNotFound)
+	})
+}
+
+func TestCancelTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionRepo := mock.NewMockTransactionPersister(ctrl)
+	mockVoucherRepo := mock.NewMockVoucherPersister(ctrl)
+	mockWaitlistRepo := mock.NewMockWaitlistPersister(ctrl)
+	mockPaymentShareRepo := mock.NewMockPaymentSharePersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+
+	uc := NewTransactionUsecase(mockTransactionRepo, mockVoucherRepo, mockWaitlistRepo, mockPaymentShareRepo, gateway.NewFakeGateway(nil), helper.NewULIDOrderIDGenerator("ORDER-TEST-"), discount.NewGrulePolicy(), logger)
+
+	orderID := "testOrderID"
+	email := "test@example.com"
+
+	t.Run("not found", func(t *testing.T) {
+		mockTransactionRepo.EXPECT().GetTransactionByOrderID(orderID).Return(model.Transaction{}, sql.ErrNoRows)
+
+		err := uc.CancelTransaction(orderID, email)
+
+		assert.ErrorIs(t, err, ErrTransactionNotFound)
+	})
+
+	t.Run("owned by another user", func(t *testing.T) {
+		mockTransactionRepo.EXPECT().GetTransactionByOrderID(orderID).
+			Return(model.Transaction{OrderID: orderID, Email: "other@example.com", PaymentStatus: "pending"}, nil)
+
+		err := uc.CancelTransaction(orderID, email)
+
+		assert.ErrorIs(t, err, ErrTransactionNotFound)
+	})
+
+	t.Run("not pending", func(t *testing.T) {
+		mockTransactionRepo.EXPECT().GetTransactionByOrderID(orderID).
+			Return(model.Transaction{OrderID: orderID, Email: email, PaymentStatus: "completed"}, nil)
+
+		err := uc.CancelTransaction(orderID, email)
+
+		assert.ErrorIs(t, err, ErrTransactionNotPending)
+	})
+}
+
+func TestCancelTransactionWithGateway(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionRepo := mock.NewMockTransactionPersister(ctrl)
+	mockVoucherRepo := mock.NewMockVoucherPersister(ctrl)
+	mockWaitlistRepo := mock.NewMockWaitlistPersister(ctrl)
+	mockPaymentShareRepo := mock.NewMockPaymentSharePersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+
+	var notifications []map[string]interface{}
+	fakeGateway := gateway.NewFakeGateway(func(payload map[string]interface{}) {
+		notifications = append(notifications, payload)
+	})
+
+	uc := NewTransactionUsecase(mockTransactionRepo, mockVoucherRepo, mockWaitlistRepo, mockPaymentShareRepo, fakeGateway, helper.NewULIDOrderIDGenerator("ORDER-TEST-"), discount.NewGrulePolicy(), logger)
+
+	email := "test@example.com"
+	pendingTransaction := func(orderID string) model.Transaction {
+		return model.Transaction{TransactionID: 1, OrderID: orderID, Email: email, PaymentStatus: "pending"}
+	}
+	charge := func(orderID string) {
+		_, err := fakeGateway.CreateCharge(&snap.Request{
+			TransactionDetails: midtrans.TransactionDetails{OrderID: orderID, GrossAmt: 160450},
+		})
+		assert.Nil(t, err)
+		mockTransactionRepo.EXPECT().GetPaymentByOrderID(orderID).Return(model.Payment{OrderID: orderID}, nil)
+	}
+
+	t.Run("payment channel not chosen yet", func(t *testing.T) {
+		orderID := "ORDER-1"
+		charge(orderID)
+		mockTransactionRepo.EXPECT().GetTransactionByOrderID(orderID).Return(pendingTransaction(orderID), nil)
+		mockTransactionRepo.EXPECT().CancelTransaction(orderID, gomock.Any()).Return(true, nil)
+		mockTransactionRepo.EXPECT().GetDetailTransactionByTransactionID(1).Return([]model.DetailTransaction{}, nil)
+		mockVoucherRepo.EXPECT().ReleaseVoucher(orderID).Return(nil)
+
+		err := uc.CancelTransaction(orderID, email)
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("pending on gateway", func(t *testing.T) {
+		orderID := "ORDER-2"
+		charge(orderID)
+		assert.Nil(t, fakeGateway.Pay(orderID, "bank_transfer"))
+		mockTransactionRepo.EXPECT().GetTransactionByOrderID(orderID).Return(pendingTransaction(orderID), nil)
+		mockTransactionRepo.EXPECT().CancelTransaction(orderID, gomock.Any()).Return(true, nil)
+		mockTransactionRepo.EXPECT().GetDetailTransactionByTransactionID(1).Return([]model.DetailTransaction{}, nil)
+		mockVoucherRepo.EXPECT().ReleaseVoucher(orderID).Return(nil)
+
+		err := uc.CancelTransaction(orderID, email)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "cancel", notifications[len(notifications)-1]["transaction_status"])
+	})
+
+	t.Run("already settled on gateway", func(t *testing.T) {
+		orderID := "ORDER-3"
+		charge(orderID)
+		assert.Nil(t, fakeGateway.Pay(orderID, "bank_transfer"))
+		assert.Nil(t, fakeGateway.SetStatus(orderID, "settlement", "accept"))
+		mockTransactionRepo.EXPECT().GetTransactionByOrderID(orderID).Return(pendingTransaction(orderID), nil)
+
+		err := uc.CancelTransaction(orderID, email)
+
+		assert.ErrorIs(t, err, ErrTransactionAlreadyPaid)
+	})
+
+	t.Run("link made again is cancelled", func(t *testing.T) {
+		orderID := "ORDER-5"
+		_, err := fakeGateway.CreateCharge(&snap.Request{
+			TransactionDetails: midtrans.TransactionDetails{OrderID: "ORDER-5-R1", GrossAmt: 160450},
+		})
+		assert.Nil(t, err)
+		assert.Nil(t, fakeGateway.Pay("ORDER-5-R1", "bank_transfer"))
+		mockTransactionRepo.EXPECT().GetTransactionByOrderID(orderID).Return(pendingTransaction(orderID), nil)
+		mockTransactionRepo.EXPECT().GetPaymentByOrderID(orderID).Return(model.Payment{OrderID: orderID, LinkNumber: 1}, nil)
+		mockTransactionRepo.EXPECT().CancelTransaction(orderID, gomock.Any()).Return(true, nil)
+		mockTransactionRepo.EXPECT().GetDetailTransactionByTransactionID(1).Return([]model.DetailTransaction{}, nil)
+		mockVoucherRepo.EXPECT().ReleaseVoucher(orderID).Return(nil)
+
+		err = uc.CancelTransaction(orderID, email)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "ORDER-5-R1", notifications[len(notifications)-1]["order_id"])
+		assert.Equal(t, "cancel", notifications[len(notifications)-1]["transaction_status"])
+	})
+
+	t.Run("settled by webhook in the meantime", func(t *testing.T) {
+		orderID := "ORDER-4"
+		charge(orderID)
+		mockTransactionRepo.EXPECT().GetTransactionByOrderID(orderID).Return(pendingTransaction(orderID), nil)
+		mockTransactionRepo.EXPECT().CancelTransaction(orderID, gomock.Any()).Return(false, nil)
+
+		err := uc.CancelTransaction(orderID, email)
+
+		assert.ErrorIs(t, err, ErrTransactionNotPending)
+	})
+}
+
+func TestCreateTransactionPaymentMethod(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionRepo := mock.NewMockTransactionPersister(ctrl)
+	mockVoucherRepo := mock.NewMockVoucherPersister(ctrl)
+	mockWaitlistRepo := mock.NewMockWaitlistPersister(ctrl)
+	mockPaymentShareRepo := mock.NewMockPaymentSharePersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+
+	uc := NewTransactionUsecase(mockTransactionRepo, mockVoucherRepo, mockWaitlistRepo, mockPaymentShareRepo, gateway.NewFakeGateway(nil), helper.NewULIDOrderIDGenerator("ORDER-TEST-"), discount.NewGrulePolicy(), logger)
+
+	t.Run("unknown payment method", func(t *testing.T) {
+		_, err := uc.CreateTransaction(model.TransactionRequest{PaymentMethod: "cash"}, model.User{})
+
+		assert.ErrorIs(t, err, ErrInvalidPaymentMethod)
+	})
+
+	t.Run("direct charge on snap only method", func(t *testing.T) {
+		_, err := uc.CreateTransaction(model.TransactionRequest{PaymentMethod: "credit_card", DirectCharge: true}, model.User{})
+
+		assert.ErrorIs(t, err, ErrDirectChargeUnsupported)
+	})
+}
+
+func TestBuildItemDetails(t *testing.T) {
+	detailTransactions := []model.DetailTransaction{
+		{TicketID: 1, TicketType: "VIP", City: "Jakarta", Quantity: 3},
+		{TicketID: 2, TicketType: "Regular", City: "Jakarta", Quantity: 1},
+	}
+	ticketPrices := map[int]int{1: 33, 2: 17}
+
+	sum := func(items []midtrans.ItemDetails) int64 {
+		var total int64
+		for _, item := range items {
+			total += item.Price * int64(item.Qty)
+		}
+		return total
+	}
+
+	t.Run("without discount", func(t *testing.T) {
+		items, gross := buildItemDetails(detailTransactions, ticketPrices, 0, "", 0)
+
+		assert.Len(t, items, 2)
+		assert.Equal(t, int64((3*33+17)*idrExchangeRate), gross)
+		assert.Equal(t, gross, sum(items))
+	})
+
+	t.Run("with discount line", func(t *testing.T) {
+		items, gross := buildItemDetails(detailTransactions, ticketPrices, 17.5, "", 0)
+
+		assert.Len(t, items, 3)
+		assert.Equal(t, "DISCOUNT", items[2].ID)
+		assert.Less(t, items[2].Price, int64(0))
+		assert.Equal(t, gross, sum(items))
+	})
+
+	t.Run("with discount and voucher line", func(t *testing.T) {
+		items, gross := buildItemDetails(detailTransactions, ticketPrices, 10, "HEMAT", 5.5)
+
+		assert.Len(t, items, 4)
+		assert.Equal(t, "VOUCHER", items[3].ID)
+		assert.Equal(t, int64(-88248), items[3].Price)
+		assert.Equal(t, gross, sum(items))
+	})
+
+	t.Run("voucher always leaves something to pay", func(t *testing.T) {
+		items, gross := buildItemDetails(detailTransactions, ticketPrices, 0, "GRATIS", 1000)
+
+		assert.Equal(t, int64(minGrossAmount), gross)
+		assert.Equal(t, gross, sum(items))
+	})
 }
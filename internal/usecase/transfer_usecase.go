@@ -0,0 +1,242 @@
+package usecase
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/helper"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/repository"
+	"github.com/SyamSolution/transaction-service/internal/util"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultTransferTTL    = 72 * time.Hour
+	defaultTransferCutoff = 24 * time.Hour
+)
+
+type transferUsecase struct {
+	ownershipRepo repository.TicketOwnershipPersister
+	logger        config.Logger
+}
+
+type TransferExecutor interface {
+	CreateTransfer(request model.TransferRequest, email string) (model.TransferResponse, error)
+	AcceptTransfer(token, email string) (model.TicketOwnership, error)
+	CancelTransfer(transferID int, email string) error
+	GetListTransfer(email string) ([]model.TicketOwnership, error)
+}
+
+func NewTransferUsecase(ownershipRepo repository.TicketOwnershipPersister, logger config.Logger) TransferExecutor {
+	return &transferUsecase{ownershipRepo: ownershipRepo, logger: logger}
+}
+
+// transferClaims is what the token sent to the recipient carries
+type transferClaims struct {
+	TransferID int    `json:"transfer_id"`
+	Email      string `json:"email"`
+	ExpiresAt  int64  `json:"exp"`
+}
+
+func transferSecret() []byte {
+	return []byte(os.Getenv("TRANSFER_SECRET"))
+}
+
+// transferTTL is how long the recipient has to accept, configured with TRANSFER_TTL
+func transferTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("TRANSFER_TTL"))
+	if err != nil || ttl <= 0 {
+		return defaultTransferTTL
+	}
+
+	return ttl
+}
+
+// transferCutoff is how long before the event transfers close, configured with TRANSFER_CUTOFF
+func transferCutoff() time.Duration {
+	cutoff, err := time.ParseDuration(os.Getenv("TRANSFER_CUTOFF"))
+	if err != nil || cutoff < 0 {
+		return defaultTransferCutoff
+	}
+
+	return cutoff
+}
+
+func (uc *transferUsecase) CreateTransfer(request model.TransferRequest, email string) (model.TransferResponse, error) {
+	if len(transferSecret()) == 0 {
+		return model.TransferResponse{}, errors.New("TRANSFER_SECRET is not set")
+	}
+	recipient := strings.TrimSpace(request.RecipientEmail)
+	if strings.EqualFold(recipient, email) {
+		return model.TransferResponse{}, ErrTransferToSelf
+	}
+
+	line, err := uc.ownershipRepo.GetTicketLine(request.DetailTransactionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.TransferResponse{}, ErrTransferLineNotFound
+		}
+		uc.logger.Error("Error when getting ticket line", zap.Error(err))
+		return model.TransferResponse{}, err
+	}
+	if line.PaymentStatus != "completed" {
+		return model.TransferResponse{}, ErrTransferNotAllowed
+	}
+
+	ticketEvent, err := helper.GetTicketEventByTicketID(line.TicketID)
+	if err != nil {
+		uc.logger.Error("Error when getting ticket event by ticket ID", zap.Error(err))
+		return model.TransferResponse{}, err
+	}
+
+	// transfer ditutup menjelang event, token juga tidak boleh berlaku lewat dari batas itu
+	now := time.Now()
+	closesAt := ticketEvent.Date.Add(-transferCutoff())
+	if !now.Before(closesAt) {
+		return model.TransferResponse{}, ErrTransferClosed
+	}
+	expiresAt := now.Add(transferTTL())
+	if expiresAt.After(closesAt) {
+		expiresAt = closesAt
+	}
+
+	transfer := model.TicketOwnership{
+		DetailTransactionID: line.DetailTransactionID,
+		OrderID:             line.OrderID,
+		TicketID:            line.TicketID,
+		Email:               recipient,
+		Quantity:            request.Quantity,
+		Status:              model.OwnershipPending,
+		TransferredFrom:     email,
+		EventDate:           &ticketEvent.Date,
+		ExpiresAt:           &expiresAt,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+	id, created, err := uc.ownershipRepo.CreateTransfer(transfer, line)
+	if err != nil {
+		uc.logger.Error("Error when creating ticket transfer", zap.Error(err))
+		return model.TransferResponse{}, err
+	}
+	if !created {
+		return model.TransferResponse{}, ErrTransferQuantity
+	}
+	transfer.TicketOwnershipID = int(id)
+
+	token, err := util.SignToken(transferSecret(), transferClaims{
+		TransferID: transfer.TicketOwnershipID,
+		Email:      recipient,
+		ExpiresAt:  expiresAt.Unix(),
+	})
+	if err != nil {
+		uc.logger.Error("Error when signing transfer token", zap.Error(err))
+		return model.TransferResponse{}, err
+	}
+
+	uc.sendTransferMessage(os.Getenv("SQS_TRANSFER_URL"), transfer, token, "Ticket Transfer Offered")
+
+	return model.TransferResponse{
+		TransferID:          transfer.TicketOwnershipID,
+		DetailTransactionID: transfer.DetailTransactionID,
+		TicketID:            transfer.TicketID,
+		RecipientEmail:      recipient,
+		Quantity:            transfer.Quantity,
+		Status:              transfer.Status,
+		Token:               token,
+		ExpiresAt:           expiresAt,
+	}, nil
+}
+
+func (uc *transferUsecase) AcceptTransfer(token, email string) (model.TicketOwnership, error) {
+	var claims transferClaims
+	if len(transferSecret()) == 0 {
+		return model.TicketOwnership{}, ErrTransferInvalid
+	}
+	if err := util.VerifyToken(transferSecret(), token, &claims); err != nil {
+		return model.TicketOwnership{}, ErrTransferInvalid
+	}
+	if !strings.EqualFold(claims.Email, email) {
+		return model.TicketOwnership{}, ErrTransferInvalid
+	}
+
+	now := time.Now()
+	if now.Unix() >= claims.ExpiresAt {
+		return model.TicketOwnership{}, ErrTransferExpired
+	}
+
+	transfer, accepted, err := uc.ownershipRepo.AcceptTransfer(claims.TransferID, email, now, newTicketCode)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrTransferOrderNotCompleted):
+			return model.TicketOwnership{}, ErrTransferNotAllowed
+		case errors.Is(err, repository.ErrTransferTicketsUnavailable):
+			return model.TicketOwnership{}, ErrTransferQuantity
+		}
+		uc.logger.Error("Error when accepting ticket transfer", zap.Error(err))
+		return model.TicketOwnership{}, err
+	}
+	if !accepted {
+		return model.TicketOwnership{}, ErrTransferNotFound
+	}
+
+	// ticket service mencatat pemilik baru, notification service mengabari kedua pihak
+	uc.sendTransferMessage(os.Getenv("SQS_TICKET_TRANSFER_URL"), transfer, "", "Ticket Transfer")
+	uc.sendTransferMessage(os.Getenv("SQS_TRANSFER_URL"), transfer, "", "Ticket Transfer Accepted")
+
+	return transfer, nil
+}
+
+func (uc *transferUsecase) CancelTransfer(transferID int, email string) error {
+	cancelled, err := uc.ownershipRepo.CancelTransfer(transferID, email)
+	if err != nil {
+		uc.logger.Error("Error when cancelling ticket transfer", zap.Error(err))
+		return err
+	}
+	if !cancelled {
+		return ErrTransferNotFound
+	}
+
+	return nil
+}
+
+func (uc *transferUsecase) GetListTransfer(email string) ([]model.TicketOwnership, error) {
+	transfers, err := uc.ownershipRepo.GetListTransferByEmail(email)
+	if err != nil {
+		uc.logger.Error("Error when getting ticket transfer by email", zap.Error(err))
+		return nil, err
+	}
+
+	return transfers, nil
+}
+
+func (uc *transferUsecase) sendTransferMessage(url string, transfer model.TicketOwnership, token, messageType string) {
+	message := model.MessageTicketTransfer{
+		TransferID: transfer.TicketOwnershipID,
+		OrderID:    transfer.OrderID,
+		TicketID:   transfer.TicketID,
+		Quantity:   transfer.Quantity,
+		FromEmail:  transfer.TransferredFrom,
+		ToEmail:    transfer.Email,
+		Status:     transfer.Status,
+		Token:      token,
+	}
+	if transfer.ExpiresAt != nil {
+		message.ExpiresAt = *transfer.ExpiresAt
+	}
+
+	jsonString, err := json.Marshal(message)
+	if err != nil {
+		uc.logger.Error("Error when proceesing message", zap.Error(err))
+		return
+	}
+
+	if err = helper.ProduceMessageSqs(url, string(jsonString), messageType); err != nil {
+		uc.logger.Error("Error when producing message", zap.Error(err))
+	}
+}
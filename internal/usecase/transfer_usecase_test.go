@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/repository"
+	"github.com/SyamSolution/transaction-service/internal/util"
+	"github.com/SyamSolution/transaction-service/mock"
+	mock_config "github.com/SyamSolution/transaction-service/mock/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateTransfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOwnershipRepo := mock.NewMockTicketOwnershipPersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+
+	uc := NewTransferUsecase(mockOwnershipRepo, logger)
+
+	t.Setenv("TRANSFER_SECRET", "secret")
+	email := "buyer@example.com"
+
+	t.Run("to yourself", func(t *testing.T) {
+		_, err := uc.CreateTransfer(model.TransferRequest{DetailTransactionID: 1, Quantity: 1, RecipientEmail: "Buyer@example.com"}, email)
+
+		assert.ErrorIs(t, err, ErrTransferToSelf)
+	})
+
+	t.Run("line not found", func(t *testing.T) {
+		mockOwnershipRepo.EXPECT().GetTicketLine(1).Return(model.TicketLine{}, sql.ErrNoRows)
+
+		_, err := uc.CreateTransfer(model.TransferRequest{DetailTransactionID: 1, Quantity: 1, RecipientEmail: "friend@example.com"}, email)
+
+		assert.ErrorIs(t, err, ErrTransferLineNotFound)
+	})
+
+	t.Run("order not completed", func(t *testing.T) {
+		mockOwnershipRepo.EXPECT().GetTicketLine(2).Return(model.TicketLine{DetailTransactionID: 2, PaymentStatus: "pending"}, nil)
+
+		_, err := uc.CreateTransfer(model.TransferRequest{DetailTransactionID: 2, Quantity: 1, RecipientEmail: "friend@example.com"}, email)
+
+		assert.ErrorIs(t, err, ErrTransferNotAllowed)
+	})
+}
+
+func TestAcceptTransfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOwnershipRepo := mock.NewMockTicketOwnershipPersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+
+	uc := NewTransferUsecase(mockOwnershipRepo, logger)
+
+	t.Setenv("TRANSFER_SECRET", "secret")
+	email := "friend@example.com"
+	sign := func(claims transferClaims) string {
+		token, err := util.SignToken(transferSecret(), claims)
+		assert.NoError(t, err)
+		return token
+	}
+	valid := transferClaims{TransferID: 5, Email: email, ExpiresAt: time.Now().Add(time.Hour).Unix()}
+
+	t.Run("tampered token", func(t *testing.T) {
+		_, err := uc.AcceptTransfer(sign(valid)+"x", email)
+
+		assert.ErrorIs(t, err, ErrTransferInvalid)
+	})
+
+	t.Run("token of someone else", func(t *testing.T) {
+		_, err := uc.AcceptTransfer(sign(valid), "other@example.com")
+
+		assert.ErrorIs(t, err, ErrTransferInvalid)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		expired := valid
+		expired.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+
+		_, err := uc.AcceptTransfer(sign(expired), email)
+
+		assert.ErrorIs(t, err, ErrTransferExpired)
+	})
+
+	t.Run("transfer no longer pending", func(t *testing.T) {
+		mockOwnershipRepo.EXPECT().AcceptTransfer(5, email, gomock.Any(), gomock.Any()).Return(model.TicketOwnership{}, false, nil)
+
+		_, err := uc.AcceptTransfer(sign(valid), email)
+
+		assert.ErrorIs(t, err, ErrTransferNotFound)
+	})
+
+	t.Run("order no longer completed", func(t *testing.T) {
+		mockOwnershipRepo.EXPECT().AcceptTransfer(5, email, gomock.Any(), gomock.Any()).
+			Return(model.TicketOwnership{}, false, repository.ErrTransferOrderNotCompleted)
+
+		_, err := uc.AcceptTransfer(sign(valid), email)
+
+		assert.ErrorIs(t, err, ErrTransferNotAllowed)
+	})
+}
+
+func TestCancelTransfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOwnershipRepo := mock.NewMockTicketOwnershipPersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+
+	uc := NewTransferUsecase(mockOwnershipRepo, logger)
+
+	email := "buyer@example.com"
+
+	t.Run("success", func(t *testing.T) {
+		mockOwnershipRepo.EXPECT().CancelTransfer(1, email).Return(true, nil)
+
+		assert.Nil(t, uc.CancelTransfer(1, email))
+	})
+
+	t.Run("not pending or not the sender", func(t *testing.T) {
+		mockOwnershipRepo.EXPECT().CancelTransfer(2, email).Return(false, nil)
+
+		assert.ErrorIs(t, uc.CancelTransfer(2, email), ErrTransferNotFound)
+	})
+}
+
+func TestTransferCutoff(t *testing.T) {
+	t.Setenv("TRANSFER_CUTOFF", "")
+	assert.Equal(t, defaultTransferCutoff, transferCutoff())
+
+	t.Setenv("TRANSFER_CUTOFF", "6h")
+	assert.Equal(t, 6*time.Hour, transferCutoff())
+}
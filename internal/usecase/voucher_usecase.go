@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/repository"
+	"go.uber.org/zap"
+)
+
+type voucherUsecase struct {
+	voucherRepo repository.VoucherPersister
+	logger      config.Logger
+}
+
+type VoucherExecutor interface {
+	CreateVoucher(request model.VoucherRequest) (model.Voucher, error)
+	UpdateVoucher(code string, request model.VoucherRequest) (model.Voucher, error)
+	GetVoucherByCode(code string) (model.Voucher, error)
+	GetListVoucher() ([]model.Voucher, error)
+	DeactivateVoucher(code string) error
+}
+
+func NewVoucherUsecase(voucherRepo repository.VoucherPersister, logger config.Logger) VoucherExecutor {
+	return &voucherUsecase{voucherRepo: voucherRepo, logger: logger}
+}
+
+func normalizeVoucherCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func validateVoucherRequest(request model.VoucherRequest) error {
+	if request.DiscountType == model.VoucherTypePercentage && request.Amount > 100 {
+		return fmt.Errorf("%w: percentage amount can not be more than 100", ErrInvalidVoucher)
+	}
+	if request.ValidFrom != nil && request.ValidUntil != nil && !request.ValidUntil.After(*request.ValidFrom) {
+		return fmt.Errorf("%w: valid_until must be after valid_from", ErrInvalidVoucher)
+	}
+
+	return nil
+}
+
+func (uc *voucherUsecase) CreateVoucher(request model.VoucherRequest) (model.Voucher, error) {
+	if err := validateVoucherRequest(request); err != nil {
+		return model.Voucher{}, err
+	}
+
+	now := time.Now()
+	voucher := model.Voucher{
+		Code:          normalizeVoucherCode(request.Code),
+		Description:   request.Description,
+		DiscountType:  request.DiscountType,
+		Amount:        request.Amount,
+		MinSpend:      request.MinSpend,
+		ValidFrom:     request.ValidFrom,
+		ValidUntil:    request.ValidUntil,
+		MaxRedemption: request.MaxRedemption,
+		MaxPerUser:    request.MaxPerUser,
+		Continent:     request.Continent,
+		TicketType:    request.TicketType,
+		IsActive:      request.IsActive == nil || *request.IsActive,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	id, err := uc.voucherRepo.CreateVoucher(voucher)
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicateVoucherCode) {
+			return model.Voucher{}, ErrVoucherCodeTaken
+		}
+		uc.logger.Error("Error when creating voucher", zap.Error(err))
+		return model.Voucher{}, err
+	}
+	voucher.VoucherID = int(id)
+
+	return voucher, nil
+}
+
+func (uc *voucherUsecase) UpdateVoucher(code string, request model.VoucherRequest) (model.Voucher, error) {
+	if err := validateVoucherRequest(request); err != nil {
+		return model.Voucher{}, err
+	}
+
+	voucher, err := uc.GetVoucherByCode(code)
+	if err != nil {
+		return model.Voucher{}, err
+	}
+
+	voucher.Description = request.Description
+	voucher.DiscountType = request.DiscountType
+	voucher.Amount = request.Amount
+	voucher.MinSpend = request.MinSpend
+	voucher.ValidFrom = request.ValidFrom
+	voucher.ValidUntil = request.ValidUntil
+	voucher.MaxRedemption = request.MaxRedemption
+	voucher.MaxPerUser = request.MaxPerUser
+	voucher.Continent = request.Continent
+	voucher.TicketType = request.TicketType
+	if request.IsActive != nil {
+		voucher.IsActive = *request.IsActive
+	}
+	voucher.UpdatedAt = time.Now()
+
+	if err = uc.voucherRepo.UpdateVoucher(voucher); err != nil {
+		uc.logger.Error("Error when updating voucher", zap.Error(err))
+		return model.Voucher{}, err
+	}
+
+	return voucher, nil
+}
+
+func (uc *voucherUsecase) GetVoucherByCode(code string) (model.Voucher, error) {
+	voucher, err := uc.voucherRepo.GetVoucherByCode(normalizeVoucherCode(code))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Voucher{}, ErrVoucherNotFound
+		}
+		uc.logger.Error("Error when getting voucher by code", zap.Error(err))
+		return model.Voucher{}, err
+	}
+
+	return voucher, nil
+}
+
+func (uc *voucherUsecase) GetListVoucher() ([]model.Voucher, error) {
+	vouchers, err := uc.voucherRepo.GetListVoucher()
+	if err != nil {
+		uc.logger.Error("Error when getting list voucher", zap.Error(err))
+		return nil, err
+	}
+
+	return vouchers, nil
+}
+
+// DeactivateVoucher keeps the row so existing redemptions still point to it
+func (uc *voucherUsecase) DeactivateVoucher(code string) error {
+	voucher, err := uc.GetVoucherByCode(code)
+	if err != nil {
+		return err
+	}
+
+	voucher.IsActive = false
+	voucher.UpdatedAt = time.Now()
+	if err = uc.voucherRepo.UpdateVoucher(voucher); err != nil {
+		uc.logger.Error("Error when deactivating voucher", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// voucherApplies tells whether the voucher is scoped to the ticket type and continent of the line
+func voucherApplies(voucher model.Voucher, dt model.DetailTransaction) bool {
+	return (voucher.TicketType == "" || strings.EqualFold(voucher.TicketType, dt.TicketType)) &&
+		(voucher.Continent == "" || strings.EqualFold(voucher.Continent, dt.Continent))
+}
+
+// voucherDiscount returns the amount the voucher takes off the order, in the same currency as
+// the ticket price. Only lines matching the voucher continent and ticket type count, after the automatic
+// discount percentage, and the order always keeps minGrossAmount to pay. Caps are not checked here,
+// ReserveVoucher does that under a row lock.
+func voucherDiscount(voucher model.Voucher, detailTransactions []model.DetailTransaction, ticketPrices map[int]int,
+	discount float32, now time.Time) (float32, error) {
+	if !voucher.IsActive {
+		return 0, ErrVoucherInactive
+	}
+	if voucher.ValidFrom != nil && now.Before(*voucher.ValidFrom) {
+		return 0, ErrVoucherInactive
+	}
+	if voucher.ValidUntil != nil && !now.Before(*voucher.ValidUntil) {
+		return 0, ErrVoucherInactive
+	}
+
+	var total, eligible float32
+	for _, dt := range detailTransactions {
+		amount := float32(dt.Quantity*ticketPrices[dt.TicketID]) * (100 - discount) / 100
+		total += amount
+		if voucherApplies(voucher, dt) {
+			eligible += amount
+		}
+	}
+	if eligible == 0 {
+		return 0, ErrVoucherNotApplicable
+	}
+	if total < voucher.MinSpend {
+		return 0, ErrVoucherMinSpend
+	}
+
+	amount := voucher.Amount
+	if voucher.DiscountType == model.VoucherTypePercentage {
+		amount = eligible * voucher.Amount / 100
+	}
+	if amount > eligible {
+		amount = eligible
+	}
+	// the gateway needs something to charge, a voucher covering the whole order leaves the minimum
+	if payable := total - float32(minGrossAmount)/idrExchangeRate; amount > payable {
+		amount = max(payable, 0)
+	}
+
+	return amount, nil
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/repository"
+	"github.com/SyamSolution/transaction-service/mock"
+	mock_config "github.com/SyamSolution/transaction-service/mock/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateVoucher(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockVoucherRepo := mock.NewMockVoucherPersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+
+	uc := NewVoucherUsecase(mockVoucherRepo, logger)
+
+	t.Run("code is normalized", func(t *testing.T) {
+		mockVoucherRepo.EXPECT().CreateVoucher(gomock.Any()).DoAndReturn(func(v model.Voucher) (int64, error) {
+			assert.Equal(t, "HEMAT10", v.Code)
+			assert.True(t, v.IsActive)
+			return 7, nil
+		})
+
+		voucher, err := uc.CreateVoucher(model.VoucherRequest{Code: " hemat10 ", DiscountType: model.VoucherTypePercentage, Amount: 10})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 7, voucher.VoucherID)
+	})
+
+	t.Run("duplicate code", func(t *testing.T) {
+		mockVoucherRepo.EXPECT().CreateVoucher(gomock.Any()).Return(int64(0), repository.ErrDuplicateVoucherCode)
+
+		_, err := uc.CreateVoucher(model.VoucherRequest{Code: "HEMAT10", DiscountType: model.VoucherTypeFixed, Amount: 5})
+
+		assert.ErrorIs(t, err, ErrVoucherCodeTaken)
+	})
+
+	t.Run("percentage over 100", func(t *testing.T) {
+		_, err := uc.CreateVoucher(model.VoucherRequest{Code: "X", DiscountType: model.VoucherTypePercentage, Amount: 120})
+
+		assert.ErrorIs(t, err, ErrInvalidVoucher)
+	})
+
+	t.Run("window ends before it starts", func(t *testing.T) {
+		from := time.Now()
+		until := from.Add(-time.Hour)
+		_, err := uc.CreateVoucher(model.VoucherRequest{Code: "X", DiscountType: model.VoucherTypeFixed, Amount: 5, ValidFrom: &from, ValidUntil: &until})
+
+		assert.ErrorIs(t, err, ErrInvalidVoucher)
+	})
+}
+
+func TestDeactivateVoucher(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockVoucherRepo := mock.NewMockVoucherPersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+
+	uc := NewVoucherUsecase(mockVoucherRepo, logger)
+
+	mockVoucherRepo.EXPECT().GetVoucherByCode("HEMAT10").Return(model.Voucher{Code: "HEMAT10", IsActive: true}, nil)
+	mockVoucherRepo.EXPECT().UpdateVoucher(gomock.Any()).DoAndReturn(func(v model.Voucher) error {
+		assert.False(t, v.IsActive)
+		return nil
+	})
+	assert.Nil(t, uc.DeactivateVoucher("hemat10"))
+
+	mockVoucherRepo.EXPECT().GetVoucherByCode("NOPE").Return(model.Voucher{}, sql.ErrNoRows)
+	assert.ErrorIs(t, uc.DeactivateVoucher("nope"), ErrVoucherNotFound)
+}
+
+func TestVoucherDiscount(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	yesterday := now.Add(-24 * time.Hour)
+	tomorrow := now.Add(24 * time.Hour)
+
+	detailTransactions := []model.DetailTransaction{
+		{TicketID: 1, TicketType: "VIP", Continent: "Asia", Quantity: 2},
+		{TicketID: 2, TicketType: "Regular", Continent: "Asia", Quantity: 1},
+	}
+	ticketPrices := map[int]int{1: 50, 2: 20}
+
+	tests := []struct {
+		name     string
+		voucher  model.Voucher
+		discount float32
+		amount   float32
+		err      error
+	}{
+		{
+			name:    "percentage of whole order",
+			voucher: model.Voucher{IsActive: true, DiscountType: model.VoucherTypePercentage, Amount: 10},
+			amount:  12,
+		},
+		{
+			name:     "percentage after automatic discount",
+			voucher:  model.Voucher{IsActive: true, DiscountType: model.VoucherTypePercentage, Amount: 10},
+			discount: 50,
+			amount:   6,
+		},
+		{
+			name:    "fixed scoped to ticket type is capped by the lines",
+			voucher: model.Voucher{IsActive: true, DiscountType: model.VoucherTypeFixed, Amount: 30, TicketType: "regular"},
+			amount:  20,
+		},
+		{
+			name:    "inactive",
+			voucher: model.Voucher{DiscountType: model.VoucherTypeFixed, Amount: 5},
+			err:     ErrVoucherInactive,
+		},
+		{
+			name:    "not started",
+			voucher: model.Voucher{IsActive: true, DiscountType: model.VoucherTypeFixed, Amount: 5, ValidFrom: &tomorrow},
+			err:     ErrVoucherInactive,
+		},
+		{
+			name:    "expired",
+			voucher: model.Voucher{IsActive: true, DiscountType: model.VoucherTypeFixed, Amount: 5, ValidUntil: &yesterday},
+			err:     ErrVoucherInactive,
+		},
+		{
+			name:    "other continent",
+			voucher: model.Voucher{IsActive: true, DiscountType: model.VoucherTypeFixed, Amount: 5, Continent: "Europe"},
+			err:     ErrVoucherNotApplicable,
+		},
+		{
+			name:    "ticket type not in order",
+			voucher: model.Voucher{IsActive: true, DiscountType: model.VoucherTypeFixed, Amount: 5, TicketType: "VVIP"},
+			err:     ErrVoucherNotApplicable,
+		},
+		{
+			name:    "min spend",
+			voucher: model.Voucher{IsActive: true, DiscountType: model.VoucherTypeFixed, Amount: 5, MinSpend: 150},
+			err:     ErrVoucherMinSpend,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, err := voucherDiscount(tt.voucher, detailTransactions, ticketPrices, tt.discount, now)
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.amount, amount)
+		})
+	}
+
+	t.Run("full voucher leaves the minimum to pay", func(t *testing.T) {
+		voucher := model.Voucher{IsActive: true, DiscountType: model.VoucherTypePercentage, Amount: 100}
+
+		amount, err := voucherDiscount(voucher, detailTransactions, ticketPrices, 0, now)
+		assert.NoError(t, err)
+		assert.Less(t, amount, float32(120))
+
+		_, gross := buildItemDetails(detailTransactions, ticketPrices, 0, "GRATIS", amount)
+		assert.Equal(t, int64(minGrossAmount), gross)
+	})
+
+	t.Run("only lines of the voucher continent", func(t *testing.T) {
+		mixed := []model.DetailTransaction{
+			{TicketID: 1, TicketType: "VIP", Continent: "Asia", Quantity: 2},
+			{TicketID: 3, TicketType: "VIP", Continent: "Europe", Quantity: 1},
+		}
+		voucher := model.Voucher{IsActive: true, DiscountType: model.VoucherTypePercentage, Amount: 10, Continent: "europe"}
+
+		amount, err := voucherDiscount(voucher, mixed, map[int]int{1: 50, 3: 40}, 0, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, float32(4), amount)
+	})
+}
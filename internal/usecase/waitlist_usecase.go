@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/config"
+	"github.com/SyamSolution/transaction-service/helper"
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/internal/repository"
+	"go.uber.org/zap"
+)
+
+const defaultWaitlistHoldTTL = 30 * time.Minute
+
+type waitlistUsecase struct {
+	waitlistRepo repository.WaitlistPersister
+	logger       config.Logger
+}
+
+type WaitlistExecutor interface {
+	JoinWaitlist(request model.WaitlistRequest, email string) (model.Waitlist, error)
+	GetListWaitlist(email string) ([]model.Waitlist, error)
+	LeaveWaitlist(waitlistID int, email string) error
+	ExpireWaitlistHolds() error
+}
+
+func NewWaitlistUsecase(waitlistRepo repository.WaitlistPersister, logger config.Logger) WaitlistExecutor {
+	return &waitlistUsecase{waitlistRepo: waitlistRepo, logger: logger}
+}
+
+// waitlistHoldTTL is how long a promoted user can buy the held tickets, configured with WAITLIST_HOLD_TTL
+func waitlistHoldTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("WAITLIST_HOLD_TTL"))
+	if err != nil || ttl <= 0 {
+		return defaultWaitlistHoldTTL
+	}
+
+	return ttl
+}
+
+func (uc *waitlistUsecase) JoinWaitlist(request model.WaitlistRequest, email string) (model.Waitlist, error) {
+	waitlists, err := uc.waitlistRepo.GetListWaitlistByEmail(email)
+	if err != nil {
+		uc.logger.Error("Error when getting waitlist by email", zap.Error(err))
+		return model.Waitlist{}, err
+	}
+	for _, w := range waitlists {
+		if w.TicketID == request.TicketID && (w.Status == model.WaitlistWaiting || w.Status == model.WaitlistNotified) {
+			return model.Waitlist{}, ErrWaitlistAlreadyJoined
+		}
+	}
+
+	tickets, err := helper.GetTicket(request.Continent)
+	if err != nil {
+		uc.logger.Error("Error when getting ticket by continent", zap.Error(err))
+		return model.Waitlist{}, err
+	}
+	found := false
+	for _, t := range tickets {
+		if t.TicketID != request.TicketID {
+			continue
+		}
+		found = true
+		if t.Stock >= request.Quantity {
+			return model.Waitlist{}, ErrTicketAvailable
+		}
+	}
+	if !found {
+		return model.Waitlist{}, ErrWaitlistTicketNotFound
+	}
+
+	now := time.Now()
+	waitlist := model.Waitlist{
+		TicketID:  request.TicketID,
+		Continent: request.Continent,
+		Email:     email,
+		Quantity:  request.Quantity,
+		Status:    model.WaitlistWaiting,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	id, err := uc.waitlistRepo.CreateWaitlist(waitlist)
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicateWaitlist) {
+			return model.Waitlist{}, ErrWaitlistAlreadyJoined
+		}
+		uc.logger.Error("Error when creating waitlist", zap.Error(err))
+		return model.Waitlist{}, err
+	}
+	waitlist.WaitlistID = int(id)
+
+	return waitlist, nil
+}
+
+func (uc *waitlistUsecase) GetListWaitlist(email string) ([]model.Waitlist, error) {
+	waitlists, err := uc.waitlistRepo.GetListWaitlistByEmail(email)
+	if err != nil {
+		uc.logger.Error("Error when getting waitlist by email", zap.Error(err))
+		return nil, err
+	}
+
+	return waitlists, nil
+}
+
+// LeaveWaitlist closes the entry, a running hold goes to the next people in line
+func (uc *waitlistUsecase) LeaveWaitlist(waitlistID int, email string) error {
+	waitlist, err := uc.waitlistRepo.LeaveWaitlist(waitlistID, email, time.Now())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrWaitlistNotFound
+		}
+		uc.logger.Error("Error when leaving waitlist", zap.Error(err))
+		return err
+	}
+
+	if waitlist.Status == model.WaitlistNotified {
+		promoteWaitlist(uc.waitlistRepo, uc.logger, map[int]int{waitlist.TicketID: waitlist.Quantity})
+	}
+
+	return nil
+}
+
+// ExpireWaitlistHolds hands holds that ran out without a purchase to the next people in line
+func (uc *waitlistUsecase) ExpireWaitlistHolds() error {
+	ticketIDs, err := uc.waitlistRepo.GetExpiredHoldTicketIDs(time.Now())
+	if err != nil {
+		uc.logger.Error("Error when getting expired waitlist holds", zap.Error(err))
+		return err
+	}
+
+	// PromoteWaitlist menutup hold yang expired dan meneruskan quantity-nya sendiri
+	released := make(map[int]int, len(ticketIDs))
+	for _, ticketID := range ticketIDs {
+		released[ticketID] = 0
+	}
+	promoteWaitlist(uc.waitlistRepo, uc.logger, released)
+
+	return nil
+}
+
+// promoteWaitlist offers released quantity per ticket to the oldest waiting entries and notifies them over SQS
+func promoteWaitlist(waitlistRepo repository.WaitlistPersister, logger config.Logger, released map[int]int) {
+	ticketIDs := make([]int, 0, len(released))
+	for ticketID := range released {
+		ticketIDs = append(ticketIDs, ticketID)
+	}
+	sort.Ints(ticketIDs)
+
+	now := time.Now()
+	holdUntil := now.Add(waitlistHoldTTL())
+	for _, ticketID := range ticketIDs {
+		promoted, err := waitlistRepo.PromoteWaitlist(ticketID, released[ticketID], now, holdUntil)
+		if err != nil {
+			logger.Error("Error when promoting waitlist", zap.Error(err))
+			continue
+		}
+
+		for _, w := range promoted {
+			message := model.MessageWaitlistHold{
+				WaitlistID: w.WaitlistID,
+				Email:      w.Email,
+				TicketID:   w.TicketID,
+				Continent:  w.Continent,
+				Quantity:   w.Quantity,
+				HoldUntil:  holdUntil,
+			}
+
+			jsonString, err := json.Marshal(message)
+			if err != nil {
+				logger.Error("Error when proceesing message", zap.Error(err))
+				continue
+			}
+
+			if err = helper.ProduceMessageSqs(os.Getenv("SQS_WAITLIST_URL"), string(jsonString), "Waitlist Hold"); err != nil {
+				logger.Error("Error when producing message", zap.Error(err))
+			}
+		}
+	}
+}
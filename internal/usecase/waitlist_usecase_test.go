@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/SyamSolution/transaction-service/internal/model"
+	"github.com/SyamSolution/transaction-service/mock"
+	mock_config "github.com/SyamSolution/transaction-service/mock/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestJoinWaitlistAlreadyJoined(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWaitlistRepo := mock.NewMockWaitlistPersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+
+	uc := NewWaitlistUsecase(mockWaitlistRepo, logger)
+
+	email := "test@example.com"
+	mockWaitlistRepo.EXPECT().GetListWaitlistByEmail(email).Return([]model.Waitlist{
+		{WaitlistID: 1, TicketID: 5, Status: model.WaitlistLeft},
+		{WaitlistID: 2, TicketID: 5, Status: model.WaitlistNotified},
+	}, nil)
+
+	_, err := uc.JoinWaitlist(model.WaitlistRequest{TicketID: 5, Continent: "Asia", Quantity: 1}, email)
+
+	assert.ErrorIs(t, err, ErrWaitlistAlreadyJoined)
+}
+
+func TestLeaveWaitlist(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWaitlistRepo := mock.NewMockWaitlistPersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+
+	uc := NewWaitlistUsecase(mockWaitlistRepo, logger)
+
+	email := "test@example.com"
+
+	t.Run("waiting entry", func(t *testing.T) {
+		mockWaitlistRepo.EXPECT().LeaveWaitlist(1, email, gomock.Any()).
+			Return(model.Waitlist{WaitlistID: 1, TicketID: 5, Quantity: 2, Status: model.WaitlistWaiting}, nil)
+
+		assert.Nil(t, uc.LeaveWaitlist(1, email))
+	})
+
+	t.Run("notified entry hands its hold on", func(t *testing.T) {
+		mockWaitlistRepo.EXPECT().LeaveWaitlist(3, email, gomock.Any()).
+			Return(model.Waitlist{WaitlistID: 3, TicketID: 5, Quantity: 2, Status: model.WaitlistNotified}, nil)
+		mockWaitlistRepo.EXPECT().PromoteWaitlist(5, 2, gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		assert.Nil(t, uc.LeaveWaitlist(3, email))
+	})
+
+	t.Run("not owned or not active", func(t *testing.T) {
+		mockWaitlistRepo.EXPECT().LeaveWaitlist(2, email, gomock.Any()).Return(model.Waitlist{}, sql.ErrNoRows)
+
+		assert.ErrorIs(t, uc.LeaveWaitlist(2, email), ErrWaitlistNotFound)
+	})
+}
+
+func TestExpireWaitlistHolds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWaitlistRepo := mock.NewMockWaitlistPersister(ctrl)
+	logger := mock_config.NewMockLogger(ctrl)
+
+	uc := NewWaitlistUsecase(mockWaitlistRepo, logger)
+
+	mockWaitlistRepo.EXPECT().GetExpiredHoldTicketIDs(gomock.Any()).Return([]int{5, 7}, nil)
+	mockWaitlistRepo.EXPECT().PromoteWaitlist(5, 0, gomock.Any(), gomock.Any()).Return(nil, nil)
+	mockWaitlistRepo.EXPECT().PromoteWaitlist(7, 0, gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	assert.Nil(t, uc.ExpireWaitlistHolds())
+}
+
+func TestWaitlistHoldTTL(t *testing.T) {
+	t.Setenv("WAITLIST_HOLD_TTL", "")
+	assert.Equal(t, defaultWaitlistHoldTTL, waitlistHoldTTL())
+
+	t.Setenv("WAITLIST_HOLD_TTL", "10m")
+	assert.Equal(t, 10*time.Minute, waitlistHoldTTL())
+
+	t.Setenv("WAITLIST_HOLD_TTL", "-1m")
+	assert.Equal(t, defaultWaitlistHoldTTL, waitlistHoldTTL())
+}
@@ -20,3 +20,23 @@ func LoadLocation() *time.Location {
 
 	return loc
 }
+
+// PaymentWindow is how long an order stays payable, read from PAYMENT_WINDOW (e.g. "24h")
+func PaymentWindow() time.Duration {
+	window, err := time.ParseDuration(os.Getenv("PAYMENT_WINDOW"))
+	if err != nil || window <= 0 {
+		return 24 * time.Hour
+	}
+
+	return window
+}
+
+// SplitPaymentWindow is how long every share of a split order has to be paid, read from SPLIT_PAYMENT_WINDOW
+func SplitPaymentWindow() time.Duration {
+	window, err := time.ParseDuration(os.Getenv("SPLIT_PAYMENT_WINDOW"))
+	if err != nil || window <= 0 {
+		return 48 * time.Hour
+	}
+
+	return window
+}
@@ -29,3 +29,37 @@ func TestTimeUtilLoadLocation(t *testing.T) {
 		assert.Equal(t, time.Local.String(), loc.String())
 	})
 }
+
+func TestTimeUtilPaymentWindow(t *testing.T) {
+	t.Run("return configured window", func(t *testing.T) {
+		t.Setenv("PAYMENT_WINDOW", "90m")
+
+		assert.Equal(t, 90*time.Minute, PaymentWindow())
+	})
+
+	t.Run("return default window", func(t *testing.T) {
+		t.Setenv("PAYMENT_WINDOW", "")
+
+		assert.Equal(t, 24*time.Hour, PaymentWindow())
+	})
+
+	t.Run("return default window when value is invalid", func(t *testing.T) {
+		t.Setenv("PAYMENT_WINDOW", "one day")
+
+		assert.Equal(t, 24*time.Hour, PaymentWindow())
+	})
+}
+
+func TestTimeUtilSplitPaymentWindow(t *testing.T) {
+	t.Run("return configured window", func(t *testing.T) {
+		t.Setenv("SPLIT_PAYMENT_WINDOW", "6h")
+
+		assert.Equal(t, 6*time.Hour, SplitPaymentWindow())
+	})
+
+	t.Run("return default window", func(t *testing.T) {
+		t.Setenv("SPLIT_PAYMENT_WINDOW", "")
+
+		assert.Equal(t, 48*time.Hour, SplitPaymentWindow())
+	})
+}
@@ -0,0 +1,32 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignToken(t *testing.T) {
+	type claims struct {
+		OrderID string `json:"order_id"`
+		Exp     int64  `json:"exp"`
+	}
+	secret := []byte("secret")
+
+	token, err := SignToken(secret, claims{OrderID: "ORDER-1", Exp: 100})
+	assert.NoError(t, err)
+
+	var got claims
+	assert.NoError(t, VerifyToken(secret, token, &got))
+	assert.Equal(t, claims{OrderID: "ORDER-1", Exp: 100}, got)
+
+	assert.ErrorIs(t, VerifyToken([]byte("other"), token, &got), ErrInvalidToken)
+	assert.ErrorIs(t, VerifyToken(secret, "garbage", &got), ErrInvalidToken)
+
+	// payload swapped, signature kept
+	other, _ := SignToken(secret, claims{OrderID: "ORDER-2"})
+	otherPayload, _, _ := strings.Cut(other, ".")
+	_, signature, _ := strings.Cut(token, ".")
+	assert.ErrorIs(t, VerifyToken(secret, otherPayload+"."+signature, &got), ErrInvalidToken)
+}
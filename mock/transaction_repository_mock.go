@@ -11,6 +11,7 @@ package mock
 
 import (
 	reflect "reflect"
+	time "time"
 
 	model "github.com/SyamSolution/transaction-service/internal/model"
 	gomock "go.uber.org/mock/gomock"
@@ -39,6 +40,21 @@ func (m *MockTransactionPersister) EXPECT() *MockTransactionPersisterMockRecorde
 	return m.recorder
 }
 
+// CancelTransaction mocks base method.
+func (m *MockTransactionPersister) CancelTransaction(orderID string, event model.TransactionEvent) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelTransaction", orderID, event)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelTransaction indicates an expected call of CancelTransaction.
+func (mr *MockTransactionPersisterMockRecorder) CancelTransaction(orderID, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelTransaction", reflect.TypeOf((*MockTransactionPersister)(nil).CancelTransaction), orderID, event)
+}
+
 // CreateTransaction mocks base method.
 func (m *MockTransactionPersister) CreateTransaction(transaction model.Transaction, detailTransaction []model.DetailTransaction) error {
 	m.ctrl.T.Helper()
@@ -53,6 +69,20 @@ func (mr *MockTransactionPersisterMockRecorder) CreateTransaction(transaction, d
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransaction", reflect.TypeOf((*MockTransactionPersister)(nil).CreateTransaction), transaction, detailTransaction)
 }
 
+// CreateTransactionEvent mocks base method.
+func (m *MockTransactionPersister) CreateTransactionEvent(event model.TransactionEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransactionEvent", event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTransactionEvent indicates an expected call of CreateTransactionEvent.
+func (mr *MockTransactionPersisterMockRecorder) CreateTransactionEvent(event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransactionEvent", reflect.TypeOf((*MockTransactionPersister)(nil).CreateTransactionEvent), event)
+}
+
 // GetDetailTransactionByTransactionID mocks base method.
 func (m *MockTransactionPersister) GetDetailTransactionByTransactionID(transactionID int) ([]model.DetailTransaction, error) {
 	m.ctrl.T.Helper()
@@ -98,6 +128,21 @@ func (mr *MockTransactionPersisterMockRecorder) GetListTransaction(request any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListTransaction", reflect.TypeOf((*MockTransactionPersister)(nil).GetListTransaction), request)
 }
 
+// GetPaymentByOrderID mocks base method.
+func (m *MockTransactionPersister) GetPaymentByOrderID(orderID string) (model.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentByOrderID", orderID)
+	ret0, _ := ret[0].(model.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentByOrderID indicates an expected call of GetPaymentByOrderID.
+func (mr *MockTransactionPersisterMockRecorder) GetPaymentByOrderID(orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentByOrderID", reflect.TypeOf((*MockTransactionPersister)(nil).GetPaymentByOrderID), orderID)
+}
+
 // GetTransactionByOrderID mocks base method.
 func (m *MockTransactionPersister) GetTransactionByOrderID(orderID string) (model.Transaction, error) {
 	m.ctrl.T.Helper()
@@ -128,16 +173,76 @@ func (mr *MockTransactionPersisterMockRecorder) GetTransactionByTransactionID(tr
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionByTransactionID", reflect.TypeOf((*MockTransactionPersister)(nil).GetTransactionByTransactionID), transactionID, email)
 }
 
-// UpdateTransactionStatus mocks base method.
-func (m *MockTransactionPersister) UpdateTransactionStatus(orderID, status string) error {
+// GetTransactionEvents mocks base method.
+func (m *MockTransactionPersister) GetTransactionEvents(transactionID int) ([]model.TransactionEvent, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTransactionStatus", orderID, status)
+	ret := m.ctrl.Call(m, "GetTransactionEvents", transactionID)
+	ret0, _ := ret[0].([]model.TransactionEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionEvents indicates an expected call of GetTransactionEvents.
+func (mr *MockTransactionPersisterMockRecorder) GetTransactionEvents(transactionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionEvents", reflect.TypeOf((*MockTransactionPersister)(nil).GetTransactionEvents), transactionID)
+}
+
+// GetTransactionExport mocks base method.
+func (m *MockTransactionPersister) GetTransactionExport(email string, from, to time.Time) ([]model.TransactionExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionExport", email, from, to)
+	ret0, _ := ret[0].([]model.TransactionExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionExport indicates an expected call of GetTransactionExport.
+func (mr *MockTransactionPersisterMockRecorder) GetTransactionExport(email, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionExport", reflect.TypeOf((*MockTransactionPersister)(nil).GetTransactionExport), email, from, to)
+}
+
+// GetTransactionsByDateRange mocks base method.
+func (m *MockTransactionPersister) GetTransactionsByDateRange(from, to time.Time, afterID, limit int) ([]model.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionsByDateRange", from, to, afterID, limit)
+	ret0, _ := ret[0].([]model.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionsByDateRange indicates an expected call of GetTransactionsByDateRange.
+func (mr *MockTransactionPersisterMockRecorder) GetTransactionsByDateRange(from, to, afterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsByDateRange", reflect.TypeOf((*MockTransactionPersister)(nil).GetTransactionsByDateRange), from, to, afterID, limit)
+}
+
+// SavePayment mocks base method.
+func (m *MockTransactionPersister) SavePayment(payment model.Payment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePayment", payment)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// SavePayment indicates an expected call of SavePayment.
+func (mr *MockTransactionPersisterMockRecorder) SavePayment(payment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePayment", reflect.TypeOf((*MockTransactionPersister)(nil).SavePayment), payment)
+}
+
+// UpdateTransactionStatus mocks base method.
+func (m *MockTransactionPersister) UpdateTransactionStatus(orderID, status string, event model.TransactionEvent) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTransactionStatus", orderID, status, event)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // UpdateTransactionStatus indicates an expected call of UpdateTransactionStatus.
-func (mr *MockTransactionPersisterMockRecorder) UpdateTransactionStatus(orderID, status any) *gomock.Call {
+func (mr *MockTransactionPersisterMockRecorder) UpdateTransactionStatus(orderID, status, event any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransactionStatus", reflect.TypeOf((*MockTransactionPersister)(nil).UpdateTransactionStatus), orderID, status)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransactionStatus", reflect.TypeOf((*MockTransactionPersister)(nil).UpdateTransactionStatus), orderID, status, event)
 }
@@ -13,7 +13,7 @@ import (
 	reflect "reflect"
 
 	model "github.com/SyamSolution/transaction-service/internal/model"
-	snap "github.com/midtrans/midtrans-go/snap"
+	coreapi "github.com/midtrans/midtrans-go/coreapi"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -40,15 +40,27 @@ func (m *MockTransactionExecutor) EXPECT() *MockTransactionExecutorMockRecorder
 	return m.recorder
 }
 
+// CancelTransaction mocks base method.
+func (m *MockTransactionExecutor) CancelTransaction(orderID, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelTransaction", orderID, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelTransaction indicates an expected call of CancelTransaction.
+func (mr *MockTransactionExecutorMockRecorder) CancelTransaction(orderID, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelTransaction", reflect.TypeOf((*MockTransactionExecutor)(nil).CancelTransaction), orderID, email)
+}
+
 // CreateTransaction mocks base method.
-func (m *MockTransactionExecutor) CreateTransaction(request model.TransactionRequest, user model.User) (*snap.Response, float32, float32, error) {
+func (m *MockTransactionExecutor) CreateTransaction(request model.TransactionRequest, user model.User) (model.CreateTransactionResponse, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateTransaction", request, user)
-	ret0, _ := ret[0].(*snap.Response)
-	ret1, _ := ret[1].(float32)
-	ret2, _ := ret[2].(float32)
-	ret3, _ := ret[3].(error)
-	return ret0, ret1, ret2, ret3
+	ret0, _ := ret[0].(model.CreateTransactionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // CreateTransaction indicates an expected call of CreateTransaction.
@@ -57,6 +69,35 @@ func (mr *MockTransactionExecutorMockRecorder) CreateTransaction(request, user a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransaction", reflect.TypeOf((*MockTransactionExecutor)(nil).CreateTransaction), request, user)
 }
 
+// ExpireSplitTransactions mocks base method.
+func (m *MockTransactionExecutor) ExpireSplitTransactions() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireSplitTransactions")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExpireSplitTransactions indicates an expected call of ExpireSplitTransactions.
+func (mr *MockTransactionExecutorMockRecorder) ExpireSplitTransactions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireSplitTransactions", reflect.TypeOf((*MockTransactionExecutor)(nil).ExpireSplitTransactions))
+}
+
+// ExportTransactions mocks base method.
+func (m *MockTransactionExecutor) ExportTransactions(email, from, to string) ([]model.TransactionExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportTransactions", email, from, to)
+	ret0, _ := ret[0].([]model.TransactionExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportTransactions indicates an expected call of ExportTransactions.
+func (mr *MockTransactionExecutorMockRecorder) ExportTransactions(email, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportTransactions", reflect.TypeOf((*MockTransactionExecutor)(nil).ExportTransactions), email, from, to)
+}
+
 // GetListTransaction mocks base method.
 func (m *MockTransactionExecutor) GetListTransaction(request model.TransactionListRequest) ([]model.TransactionListResponse, error) {
 	m.ctrl.T.Helper()
@@ -72,6 +113,50 @@ func (mr *MockTransactionExecutorMockRecorder) GetListTransaction(request any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListTransaction", reflect.TypeOf((*MockTransactionExecutor)(nil).GetListTransaction), request)
 }
 
+// GetOrder mocks base method.
+func (m *MockTransactionExecutor) GetOrder(orderID, email string) (model.TransactionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrder", orderID, email)
+	ret0, _ := ret[0].(model.TransactionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrder indicates an expected call of GetOrder.
+func (mr *MockTransactionExecutorMockRecorder) GetOrder(orderID, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockTransactionExecutor)(nil).GetOrder), orderID, email)
+}
+
+// GetPaymentMethods mocks base method.
+func (m *MockTransactionExecutor) GetPaymentMethods() []model.PaymentMethod {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentMethods")
+	ret0, _ := ret[0].([]model.PaymentMethod)
+	return ret0
+}
+
+// GetPaymentMethods indicates an expected call of GetPaymentMethods.
+func (mr *MockTransactionExecutorMockRecorder) GetPaymentMethods() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentMethods", reflect.TypeOf((*MockTransactionExecutor)(nil).GetPaymentMethods))
+}
+
+// GetPaymentStatus mocks base method.
+func (m *MockTransactionExecutor) GetPaymentStatus(orderID string) (*coreapi.TransactionStatusResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentStatus", orderID)
+	ret0, _ := ret[0].(*coreapi.TransactionStatusResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentStatus indicates an expected call of GetPaymentStatus.
+func (mr *MockTransactionExecutorMockRecorder) GetPaymentStatus(orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentStatus", reflect.TypeOf((*MockTransactionExecutor)(nil).GetPaymentStatus), orderID)
+}
+
 // GetTransactionByOrderID mocks base method.
 func (m *MockTransactionExecutor) GetTransactionByOrderID(orderID string) (model.TransactionResponse, error) {
 	m.ctrl.T.Helper()
@@ -102,16 +187,107 @@ func (mr *MockTransactionExecutorMockRecorder) GetTransactionByTransactionID(tra
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionByTransactionID", reflect.TypeOf((*MockTransactionExecutor)(nil).GetTransactionByTransactionID), transactionID, email)
 }
 
-// UpdateTransactionStatus mocks base method.
-func (m *MockTransactionExecutor) UpdateTransactionStatus(orderID, status, email string) error {
+// GetTransactionHistory mocks base method.
+func (m *MockTransactionExecutor) GetTransactionHistory(transactionID int, email string) ([]model.TransactionEvent, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTransactionStatus", orderID, status, email)
+	ret := m.ctrl.Call(m, "GetTransactionHistory", transactionID, email)
+	ret0, _ := ret[0].([]model.TransactionEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionHistory indicates an expected call of GetTransactionHistory.
+func (mr *MockTransactionExecutorMockRecorder) GetTransactionHistory(transactionID, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionHistory", reflect.TypeOf((*MockTransactionExecutor)(nil).GetTransactionHistory), transactionID, email)
+}
+
+// QuoteTransaction mocks base method.
+func (m *MockTransactionExecutor) QuoteTransaction(request model.TransactionRequest, user model.User) (model.QuoteResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuoteTransaction", request, user)
+	ret0, _ := ret[0].(model.QuoteResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QuoteTransaction indicates an expected call of QuoteTransaction.
+func (mr *MockTransactionExecutorMockRecorder) QuoteTransaction(request, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuoteTransaction", reflect.TypeOf((*MockTransactionExecutor)(nil).QuoteTransaction), request, user)
+}
+
+// RecordGatewayPayment mocks base method.
+func (m *MockTransactionExecutor) RecordGatewayPayment(orderID string, status *coreapi.TransactionStatusResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordGatewayPayment", orderID, status)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// RecordGatewayPayment indicates an expected call of RecordGatewayPayment.
+func (mr *MockTransactionExecutorMockRecorder) RecordGatewayPayment(orderID, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordGatewayPayment", reflect.TypeOf((*MockTransactionExecutor)(nil).RecordGatewayPayment), orderID, status)
+}
+
+// ResendPaymentLink mocks base method.
+func (m *MockTransactionExecutor) ResendPaymentLink(transactionID int, email string) (model.PaymentLinkResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResendPaymentLink", transactionID, email)
+	ret0, _ := ret[0].(model.PaymentLinkResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResendPaymentLink indicates an expected call of ResendPaymentLink.
+func (mr *MockTransactionExecutorMockRecorder) ResendPaymentLink(transactionID, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendPaymentLink", reflect.TypeOf((*MockTransactionExecutor)(nil).ResendPaymentLink), transactionID, email)
+}
+
+// ResolvePaymentLink mocks base method.
+func (m *MockTransactionExecutor) ResolvePaymentLink(gatewayOrderID string) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolvePaymentLink", gatewayOrderID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ResolvePaymentLink indicates an expected call of ResolvePaymentLink.
+func (mr *MockTransactionExecutorMockRecorder) ResolvePaymentLink(gatewayOrderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolvePaymentLink", reflect.TypeOf((*MockTransactionExecutor)(nil).ResolvePaymentLink), gatewayOrderID)
+}
+
+// UpdatePaymentShare mocks base method.
+func (m *MockTransactionExecutor) UpdatePaymentShare(shareOrderID, transactionStatus, fraudStatus string, event model.TransactionEvent) (model.PaymentShareResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePaymentShare", shareOrderID, transactionStatus, fraudStatus, event)
+	ret0, _ := ret[0].(model.PaymentShareResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePaymentShare indicates an expected call of UpdatePaymentShare.
+func (mr *MockTransactionExecutorMockRecorder) UpdatePaymentShare(shareOrderID, transactionStatus, fraudStatus, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePaymentShare", reflect.TypeOf((*MockTransactionExecutor)(nil).UpdatePaymentShare), shareOrderID, transactionStatus, fraudStatus, event)
+}
+
+// UpdateTransactionStatus mocks base method.
+func (m *MockTransactionExecutor) UpdateTransactionStatus(orderID, status string, event model.TransactionEvent) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTransactionStatus", orderID, status, event)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // UpdateTransactionStatus indicates an expected call of UpdateTransactionStatus.
-func (mr *MockTransactionExecutorMockRecorder) UpdateTransactionStatus(orderID, status, email any) *gomock.Call {
+func (mr *MockTransactionExecutorMockRecorder) UpdateTransactionStatus(orderID, status, event any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransactionStatus", reflect.TypeOf((*MockTransactionExecutor)(nil).UpdateTransactionStatus), orderID, status, email)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransactionStatus", reflect.TypeOf((*MockTransactionExecutor)(nil).UpdateTransactionStatus), orderID, status, event)
 }